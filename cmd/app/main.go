@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"syscall"
 	"time"
+	// Вбудована база часових поясів: звіти надсилаються за часом користувача навіть без tzdata в системі
+	_ "time/tzdata"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -16,20 +18,34 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"gorm.io/gorm"
 
+	"timebride/internal/cache"
+	"timebride/internal/channels"
 	"timebride/internal/config"
 	"timebride/internal/db"
+	"timebride/internal/events"
 	"timebride/internal/handlers"
+	"timebride/internal/jobs"
+	"timebride/internal/mailer"
 	"timebride/internal/middleware"
+	"timebride/internal/models"
 	"timebride/internal/repositories"
+	"timebride/internal/scanner"
 	"timebride/internal/services"
 	"timebride/internal/services/auth"
 	"timebride/internal/services/booking"
 	"timebride/internal/services/client"
+	"timebride/internal/services/contract"
+	"timebride/internal/services/deadline"
+	"timebride/internal/services/digest"
+	"timebride/internal/services/email"
+	"timebride/internal/services/gallery"
+	"timebride/internal/services/notification"
 	"timebride/internal/services/price"
 	"timebride/internal/services/storage"
 	"timebride/internal/services/team"
 	"timebride/internal/services/template"
 	"timebride/internal/services/user"
+	"timebride/internal/services/webhook"
 
 	"github.com/gofiber/template/html/v2"
 )
@@ -38,6 +54,7 @@ import (
 type AppModules struct {
 	Config      *config.Config
 	DB          *gorm.DB
+	Cache       *cache.InstrumentedCache
 	Templates   *html.Engine
 	Static      string
 	Controllers string
@@ -46,6 +63,10 @@ type AppModules struct {
 	Services    *services.Services
 	Repos       *repositories.Repositories
 	Middleware  *middleware.Middleware
+	// Events - шина доменних подій; підписники отримують події з outbox
+	Events *events.Bus
+	// Jobs - черга фонових задач
+	Jobs *jobs.Queue
 }
 
 func main() {
@@ -58,6 +79,11 @@ func main() {
 	// Налаштовуємо і запускаємо сервер
 	server := setupServer(app)
 
+	// Фонові задачі зупиняються разом з сервером
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	startBackgroundJobs(bgCtx, app)
+
 	// Запускаємо сервер
 	go func() {
 		if err := server.Listen(app.Config.Server.Address); err != nil {
@@ -70,13 +96,17 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	// Graceful shutdown
+	// Graceful shutdown: нові задачі не беруться, поточні мають той самий час на завершення, що й запити
+	stopBackground()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := server.ShutdownWithContext(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	if err := app.Jobs.Shutdown(ctx); err != nil {
+		log.Printf("Background jobs did not finish before shutdown: %v", err)
+	}
 
 	log.Println("Server exiting")
 }
@@ -94,18 +124,33 @@ func initializeApp() (*AppModules, error) {
 		return nil, err
 	}
 
+	// Ініціалізуємо кеш
+	appCache := initCache(cfg.Cache)
+
 	// Ініціалізуємо репозиторії
-	repos := repositories.NewRepositories(database)
+	repos := repositories.NewRepositories(database, appCache)
 
 	// Ініціалізуємо сервіси
 	authService := auth.NewAuthService(cfg, repos.User)
 	userService := user.NewUserService(repos.User)
-	storageService := storage.NewStorageService(cfg, repos.File)
-	clientService := client.NewService(repos.Client, repos.File, storageService)
-	bookingService := booking.NewService(repos.Booking, repos.Client)
+	emailService := email.NewEmailService(repos.EmailOutbox, repos.Template, repos.User, initMailer(cfg.SMTP))
+	notificationService := notification.NewNotificationService(cfg, repos.Notification, repos.Delivery, repos.User, initChannels(cfg, emailService)...)
+	jobQueue := jobs.NewQueue(repos.Job)
+	storageService := storage.NewStorageService(cfg, repos.File, repos.FileLink, repos.FileBlob, repos.User, repos.Tx, initScanner(cfg.Storage), notificationService, jobQueue)
+	webhookService := webhook.NewWebhookService(repos.Webhook, repos.WebhookLog)
+
+	// Підписники доменних подій
+	bus := events.NewBus(repos.EventOutbox)
+	webhook.Subscribe(bus, webhookService)
+	clientService := client.NewService(repos.Client, storageService)
+	bookingService := booking.NewService(repos.Booking, repos.Client, repos.EventOutbox, repos.Tx)
 	teamService := team.NewTeamService(repos.Team)
 	priceService := price.NewPriceService(repos.Price)
-	templateService := template.NewTemplateService(repos.Template)
+	templateService := template.NewTemplateService(repos.Template, repos.Booking, repos.Client, repos.User)
+	contractService := contract.NewContractService(repos.Contract, repos.Booking, repos.Client, repos.User, templateService, storageService, notificationService, emailService, repos.EventOutbox, repos.Tx)
+	deadlineService := deadline.NewDeadlineService(repos.Booking, repos.Reminder, repos.User, notificationService)
+	digestService := digest.NewDigestService(repos.Booking, repos.Gallery, repos.User, deadlineService, notificationService)
+	galleryService := gallery.NewGalleryService(cfg, repos.Gallery, repos.Booking, repos.User, repos.Price, repos.GalleryProof, storageService, notificationService, repos.EventOutbox, repos.Tx)
 
 	// Створюємо екземпляр Services
 	services := services.NewServices(
@@ -117,6 +162,14 @@ func initializeApp() (*AppModules, error) {
 		priceService,
 		storageService,
 		templateService,
+		notificationService,
+		galleryService,
+		contractService,
+		emailService,
+		deadlineService,
+		digestService,
+		webhookService,
+		jobQueue,
 	)
 
 	// Ініціалізуємо шаблонізатор
@@ -139,6 +192,7 @@ func initializeApp() (*AppModules, error) {
 
 	return &AppModules{
 		Config:      cfg,
+		Cache:       appCache,
 		DB:          database,
 		Templates:   templates,
 		Static:      static,
@@ -148,6 +202,8 @@ func initializeApp() (*AppModules, error) {
 		Services:    services,
 		Repos:       repos,
 		Middleware:  middleware,
+		Events:      bus,
+		Jobs:        jobQueue,
 	}, nil
 }
 
@@ -256,6 +312,152 @@ func setupRoutes(app *fiber.App, modules *AppModules) {
 	}
 }
 
+// Зберігання завершених задач у черзі
+const (
+	doneJobsRetention   = 24 * time.Hour
+	failedJobsRetention = 30 * 24 * time.Hour
+)
+
+// startBackgroundJobs реєструє повторювані фонові задачі та запускає чергу задач
+func startBackgroundJobs(ctx context.Context, app *AppModules) {
+	queue := app.Jobs
+
+	// Остаточне видалення файлів, термін зберігання яких у кошику минув
+	queue.Every("trash.purge", "@hourly", 30*time.Minute, func(ctx context.Context, _ *models.Job) error {
+		purged, err := app.Services.Storage.PurgeExpiredTrash(ctx)
+		if purged > 0 {
+			log.Printf("Purged %d files from trash", purged)
+		}
+		return err
+	})
+
+	// Перевірка завантажених файлів; кількість одночасних перевірок обмежена пулом обробників черги.
+	// Невдала перевірка не повторюється чергою: файл зі статусом failed підбирає storage.rescan.
+	queue.Register(storage.ScanJob, jobs.Options{MaxAttempts: 1, Timeout: storage.ScanTimeout}, func(ctx context.Context, job *models.Job) error {
+		var payload storage.ScanPayload
+		if err := jobs.Decode(job, &payload); err != nil {
+			return err
+		}
+		return app.Services.Storage.ScanFile(ctx, payload.FileID)
+	})
+
+	// Повторна перевірка файлів, перевірка яких не вдалася або не завершилася
+	queue.Every("storage.rescan", "*/10 * * * *", 0, func(ctx context.Context, _ *models.Job) error {
+		_, err := app.Services.Storage.RescanPending(ctx)
+		return err
+	})
+
+	// Доставка сповіщень у зовнішні канали та повторні спроби
+	queue.Every("notifications.send", "@every 30s", 0, func(ctx context.Context, _ *models.Job) error {
+		_, err := app.Services.Notification.SendDue(ctx)
+		return err
+	})
+
+	// Позначення прострочених проєктів та нагадування про терміни віддачі
+	queue.Every("deadlines.check", "*/30 * * * *", 0, func(ctx context.Context, _ *models.Job) error {
+		sent, err := app.Services.Deadline.Check(ctx)
+		if sent > 0 {
+			log.Printf("Sent %d deadline reminders", sent)
+		}
+		return err
+	})
+
+	// Щоденні та щотижневі звіти за часовим поясом користувача, разом зі зведенням активності в галереях
+	queue.Every("digests.send", "*/15 * * * *", 0, func(ctx context.Context, _ *models.Job) error {
+		sent, err := app.Services.Digest.SendDue(ctx)
+		if sent > 0 {
+			log.Printf("Sent %d digests", sent)
+		}
+		return err
+	})
+
+	// Розсилка доменних подій з outbox підписникам
+	queue.Every("events.drain", "@every 5s", 0, func(ctx context.Context, _ *models.Job) error {
+		_, err := app.Events.Drain(ctx)
+		return err
+	})
+
+	// Повторні спроби доставки вебхуків
+	queue.Every("webhooks.send", "@every 30s", 0, func(ctx context.Context, _ *models.Job) error {
+		_, err := app.Services.Webhook.SendDue(ctx)
+		return err
+	})
+
+	// Надсилання листів з черги
+	queue.Every("emails.send", "@every 30s", 0, func(ctx context.Context, _ *models.Job) error {
+		_, err := app.Services.Email.SendDue(ctx)
+		return err
+	})
+
+	// Статистика кешу
+	queue.Every("cache.stats", "*/15 * * * *", 0, func(ctx context.Context, _ *models.Job) error {
+		stats := app.Cache.Stats()
+		log.Printf("Cache stats: hits=%d misses=%d errors=%d hit_rate=%.2f",
+			stats.Hits, stats.Misses, stats.Errors, stats.HitRate)
+		return nil
+	})
+
+	// Очищення черги від старих виконаних та невдалих задач
+	queue.Every("jobs.cleanup", "@hourly", 0, func(ctx context.Context, _ *models.Job) error {
+		now := time.Now()
+		if _, err := app.Repos.Job.DeleteFinished(ctx, models.JobStatusDone, now.Add(-doneJobsRetention)); err != nil {
+			return err
+		}
+		_, err := app.Repos.Job.DeleteFinished(ctx, models.JobStatusFailed, now.Add(-failedJobsRetention))
+		return err
+	})
+
+	queue.Start(ctx)
+
+	// Команди Telegram-бота: підключення та відключення сповіщень (довге опитування, не задача черги)
+	go app.Services.Notification.ServeTelegram(ctx)
+}
+
+// initCache створює кеш: спільний Redis, якщо він налаштований, інакше обмежений кеш у пам'яті
+func initCache(cfg config.CacheConfig) *cache.InstrumentedCache {
+	if addr := cfg.RedisAddr(); addr != "" {
+		redisCache, err := cache.NewRedisCache(addr, cfg.RedisPassword, cfg.RedisDB)
+		if err == nil {
+			log.Printf("Using redis cache at %s", addr)
+			return cache.NewInstrumentedCache(redisCache)
+		}
+		log.Printf("WARNING: Redis is unavailable, falling back to in-memory cache: %v", err)
+	}
+	return cache.NewInstrumentedCache(cache.NewMemoryCache(cfg.MaxEntries))
+}
+
+// initScanner створює антивірусний сканер; без clamd файли вважаються чистими
+func initScanner(cfg config.StorageConfig) scanner.Scanner {
+	if cfg.ClamdAddr == "" {
+		log.Printf("WARNING: CLAMD_ADDR is not set, uploads are not scanned for malware")
+		return scanner.NewNoopScanner()
+	}
+	return scanner.NewClamdScanner(cfg.ClamdAddr, cfg.ScanTimeout)
+}
+
+// initMailer створює поштовий сервіс або заглушку, якщо SMTP не налаштований
+func initMailer(cfg config.SMTPConfig) mailer.Mailer {
+	if cfg.Host == "" {
+		log.Printf("WARNING: SMTP_HOST is not set, emails are not sent")
+		return mailer.NewNoopMailer()
+	}
+	return mailer.NewSMTPMailer(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From, cfg.Timeout)
+}
+
+// initChannels створює зовнішні канали сповіщень: email - за налаштованого SMTP, Telegram - за токена бота
+func initChannels(cfg *config.Config, mail channels.MailQueue) []channels.Channel {
+	var external []channels.Channel
+	if cfg.SMTP.Host != "" {
+		external = append(external, channels.NewEmail(mail))
+	}
+	if cfg.Telegram.BotToken == "" {
+		log.Printf("WARNING: TELEGRAM_BOT_TOKEN is not set, telegram notifications are disabled")
+	} else {
+		external = append(external, channels.NewTelegram(cfg.Telegram.BotToken, cfg.Telegram.BotUsername, cfg.Telegram.PollTimeout))
+	}
+	return external
+}
+
 func initTemplates() *html.Engine {
 	templateDir := os.Getenv("TEMPLATE_DIR")
 	if templateDir == "" {
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"timebride/internal/errors"
+)
+
+// MemoryCache реалізує інтерфейс Cache в пам'яті процесу з TTL та обмеженням
+// кількості записів (найдовше невикористані записи витісняються першими).
+// Значення зберігаються у вигляді JSON, як і в Redis, тож викликачі отримують
+// копії та не можуть змінити закешований об'єкт.
+type MemoryCache struct {
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List
+	maxEntries int
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache створює новий екземпляр MemoryCache;
+// maxEntries <= 0 означає кеш без обмеження розміру
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get отримує значення з кешу
+func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	elem, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		return errors.NewNotFoundError("cache key not found")
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if c.expired(entry) {
+		c.removeElement(elem)
+		c.mu.Unlock()
+		return errors.NewNotFoundError("cache key not found")
+	}
+	c.order.MoveToFront(elem)
+	value := entry.value
+	c.mu.Unlock()
+
+	if err := json.Unmarshal(value, dest); err != nil {
+		return errors.NewInternalError("failed to unmarshal cache value", err)
+	}
+	return nil
+}
+
+// Set зберігає значення в кеш; expiration <= 0 означає запис без терміну дії
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.NewInternalError("failed to marshal cache value", err)
+	}
+
+	var expiresAt time.Time
+	if expiration > 0 {
+		expiresAt = c.now().Add(expiration)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = data
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&memoryEntry{key: key, value: data, expiresAt: expiresAt})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+	return nil
+}
+
+// Delete видаляє значення з кешу
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	return nil
+}
+
+// Exists перевіряє чи існує ключ в кеші
+func (c *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false, nil
+	}
+	if c.expired(elem.Value.(*memoryEntry)) {
+		c.removeElement(elem)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Len повертає кількість записів у кеші (включно з ще не видаленими простроченими)
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *MemoryCache) expired(entry *memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && c.now().After(entry.expiresAt)
+}
+
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"timebride/internal/errors"
+)
+
+// Stats містить статистику звернень до кешу
+type Stats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Errors  uint64  `json:"errors"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// InstrumentedCache рахує влучання та промахи кешу, що обгортається
+type InstrumentedCache struct {
+	Cache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+// NewInstrumentedCache створює обгортку зі статистикою над кешем
+func NewInstrumentedCache(c Cache) *InstrumentedCache {
+	return &InstrumentedCache{Cache: c}
+}
+
+// Get отримує значення з кешу та оновлює статистику
+func (c *InstrumentedCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := c.Cache.Get(ctx, key, dest)
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+	case errors.IsNotFound(err):
+		c.misses.Add(1)
+	default:
+		c.errors.Add(1)
+	}
+	return err
+}
+
+// Set зберігає значення в кеш та рахує помилки
+func (c *InstrumentedCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	err := c.Cache.Set(ctx, key, value, expiration)
+	if err != nil {
+		c.errors.Add(1)
+	}
+	return err
+}
+
+// Stats повертає поточну статистику
+func (c *InstrumentedCache) Stats() Stats {
+	stats := Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Errors: c.errors.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,35 @@
+// Package channels доставляє сповіщення користувачам у зовнішні канали (Telegram, email)
+package channels
+
+import (
+	"context"
+	"errors"
+
+	"timebride/internal/models"
+)
+
+// ErrNotLinked - користувач не підключив канал; повторні спроби не мають сенсу
+var ErrNotLinked = errors.New("channel is not linked to the user")
+
+// Message - сповіщення, підготовлене до надсилання
+type Message struct {
+	Type  models.NotificationType
+	Title string
+	Text  string
+	// Markdown - повний текст у розмітці Telegram MarkdownV2 разом із заголовком
+	Markdown string
+	// HTML - HTML-версія тексту для листа
+	HTML string
+}
+
+// Channel надсилає сповіщення користувачу
+type Channel interface {
+	// Name повертає назву каналу для налаштувань та журналу доставки
+	Name() models.NotificationChannel
+
+	// Linked перевіряє, чи користувач підключив канал
+	Linked(user *models.User) bool
+
+	// Send надсилає повідомлення; ErrNotLinked - якщо канал не підключено
+	Send(ctx context.Context, user *models.User, msg *Message) error
+}
@@ -0,0 +1,41 @@
+package channels
+
+import (
+	"context"
+
+	"timebride/internal/models"
+)
+
+// MailQueue ставить листи зі сповіщеннями в чергу надсилання
+type MailQueue interface {
+	QueueNotification(ctx context.Context, user *models.User, msg *Message) error
+}
+
+// Email надсилає сповіщення листом на адресу акаунта.
+// Лист лише ставиться в чергу: повторні спроби SMTP виконує обробник черги листів.
+type Email struct {
+	queue MailQueue
+}
+
+// NewEmail створює канал email поверх черги листів
+func NewEmail(queue MailQueue) *Email {
+	return &Email{queue: queue}
+}
+
+// Name повертає назву каналу
+func (e *Email) Name() models.NotificationChannel {
+	return models.NotificationChannelEmail
+}
+
+// Linked перевіряє, чи в акаунті вказана адреса
+func (e *Email) Linked(user *models.User) bool {
+	return user.Email != ""
+}
+
+// Send ставить сповіщення в чергу листів
+func (e *Email) Send(ctx context.Context, user *models.User, msg *Message) error {
+	if !e.Linked(user) {
+		return ErrNotLinked
+	}
+	return e.queue.QueueNotification(ctx, user, msg)
+}
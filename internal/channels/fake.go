@@ -0,0 +1,65 @@
+package channels
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// Sent - повідомлення, надіслане через Fake
+type Sent struct {
+	UserID  uuid.UUID
+	Message Message
+}
+
+// Fake - канал для тестів: запам'ятовує надіслані повідомлення та повертає задану помилку
+type Fake struct {
+	name models.NotificationChannel
+
+	mu   sync.Mutex
+	sent []Sent
+	err  error
+}
+
+// NewFake створює тестовий канал з вказаною назвою
+func NewFake(name models.NotificationChannel) *Fake {
+	return &Fake{name: name}
+}
+
+// Name повертає назву каналу
+func (f *Fake) Name() models.NotificationChannel {
+	return f.name
+}
+
+// Linked - тестовий канал підключений для всіх користувачів
+func (f *Fake) Linked(user *models.User) bool {
+	return true
+}
+
+// Send запам'ятовує повідомлення або повертає помилку, задану через Fail
+func (f *Fake) Send(ctx context.Context, user *models.User, msg *Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, Sent{UserID: user.ID, Message: *msg})
+	return nil
+}
+
+// Fail змушує наступні надсилання повертати err; nil відновлює роботу каналу
+func (f *Fake) Fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Sent повертає копію надісланих повідомлень
+func (f *Fake) Sent() []Sent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Sent(nil), f.sent...)
+}
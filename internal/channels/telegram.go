@@ -0,0 +1,225 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"timebride/internal/models"
+)
+
+// telegramAPI - адреса Telegram Bot API
+const telegramAPI = "https://api.telegram.org"
+
+// telegramRetryDelay - пауза після помилки отримання оновлень
+const telegramRetryDelay = 5 * time.Second
+
+// TelegramUpdate - текстове повідомлення, надіслане боту
+type TelegramUpdate struct {
+	ChatID   string
+	Username string
+	Text     string
+}
+
+// TelegramError - помилка, повернена Bot API
+type TelegramError struct {
+	Code        int
+	Description string
+}
+
+func (e *TelegramError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.Code, e.Description)
+}
+
+// chatUnavailable - бот заблокований користувачем або чат видалено
+func (e *TelegramError) chatUnavailable() bool {
+	return e.Code == http.StatusForbidden ||
+		e.Code == http.StatusBadRequest && strings.Contains(e.Description, "chat not found")
+}
+
+// Telegram надсилає сповіщення через бота та приймає команди підключення методом long polling
+type Telegram struct {
+	token       string
+	username    string
+	pollTimeout time.Duration
+	client      *http.Client
+}
+
+// NewTelegram створює канал Telegram для бота з токеном token та іменем username (без @)
+func NewTelegram(token, username string, pollTimeout time.Duration) *Telegram {
+	return &Telegram{
+		token:       token,
+		username:    username,
+		pollTimeout: pollTimeout,
+		// Запит getUpdates триває до pollTimeout, тож загальний тайм-аут має бути більшим
+		client: &http.Client{Timeout: pollTimeout + 10*time.Second},
+	}
+}
+
+// Name повертає назву каналу
+func (t *Telegram) Name() models.NotificationChannel {
+	return models.NotificationChannelTelegram
+}
+
+// Username повертає ім'я бота для посилання t.me
+func (t *Telegram) Username() string {
+	return t.username
+}
+
+// Linked перевіряє, чи користувач підключив бота
+func (t *Telegram) Linked(user *models.User) bool {
+	return user.TelegramChatID != ""
+}
+
+// Send надсилає сповіщення в чат користувача з ботом
+func (t *Telegram) Send(ctx context.Context, user *models.User, msg *Message) error {
+	if !t.Linked(user) {
+		return ErrNotLinked
+	}
+	if msg.Markdown != "" {
+		return t.send(ctx, user.TelegramChatID, msg.Markdown, "MarkdownV2")
+	}
+
+	text := "<b>" + html.EscapeString(msg.Title) + "</b>"
+	if msg.Text != "" {
+		text += "\n" + html.EscapeString(msg.Text)
+	}
+	return t.SendText(ctx, user.TelegramChatID, text)
+}
+
+// SendText надсилає повідомлення з HTML-розміткою Telegram у чат.
+// Якщо користувач заблокував бота або чат не існує, повертається ErrNotLinked.
+func (t *Telegram) SendText(ctx context.Context, chatID, text string) error {
+	return t.send(ctx, chatID, text, "HTML")
+}
+
+// send надсилає повідомлення з розміткою parseMode (HTML або MarkdownV2)
+func (t *Telegram) send(ctx context.Context, chatID, text, parseMode string) error {
+	err := t.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               parseMode,
+		"disable_web_page_preview": true,
+	}, nil)
+
+	var apiErr *TelegramError
+	if errors.As(err, &apiErr) && apiErr.chatUnavailable() {
+		return fmt.Errorf("%w: %v", ErrNotLinked, apiErr)
+	}
+	return err
+}
+
+// Poll отримує повідомлення боту до скасування контексту.
+// Непорожня відповідь handle надсилається в той самий чат.
+func (t *Telegram) Poll(ctx context.Context, handle func(ctx context.Context, update TelegramUpdate) string) {
+	var offset int64
+	for ctx.Err() == nil {
+		var updates []struct {
+			UpdateID int64 `json:"update_id"`
+			Message  *struct {
+				Text string `json:"text"`
+				Chat struct {
+					ID int64 `json:"id"`
+				} `json:"chat"`
+				From struct {
+					Username string `json:"username"`
+				} `json:"from"`
+			} `json:"message"`
+		}
+
+		err := t.call(ctx, "getUpdates", map[string]interface{}{
+			"offset":          offset,
+			"timeout":         int(t.pollTimeout.Seconds()),
+			"allowed_updates": []string{"message"},
+		}, &updates)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to fetch telegram updates: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramRetryDelay):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || u.Message.Text == "" {
+				continue
+			}
+
+			chatID := strconv.FormatInt(u.Message.Chat.ID, 10)
+			reply := handle(ctx, TelegramUpdate{
+				ChatID:   chatID,
+				Username: u.Message.From.Username,
+				Text:     u.Message.Text,
+			})
+			if reply == "" {
+				continue
+			}
+			if err := t.SendText(ctx, chatID, reply); err != nil {
+				log.Printf("Failed to reply to telegram chat %s: %v", chatID, err)
+			}
+		}
+	}
+}
+
+// call викликає метод Bot API та розбирає поле result відповіді в result
+func (t *Telegram) call(ctx context.Context, method string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := telegramAPI + "/bot" + t.token + "/" + method
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Помилка містить URL разом з токеном бота - він не повинен потрапити в журнал
+		return fmt.Errorf("telegram %s request failed: %w", method, unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("telegram %s: invalid response: %w", method, err)
+	}
+	if !envelope.OK {
+		return &TelegramError{Code: envelope.ErrorCode, Description: envelope.Description}
+	}
+	if result != nil {
+		return json.Unmarshal(envelope.Result, result)
+	}
+	return nil
+}
+
+// unwrapURLError прибирає з помилки HTTP-клієнта адресу запиту
+func unwrapURLError(err error) error {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
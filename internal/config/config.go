@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +16,41 @@ type Config struct {
 	Database DatabaseConfig `yaml:"database"`
 	JWT      JWTConfig      `yaml:"jwt"`
 	Storage  StorageConfig  `yaml:"storage"`
+	Cache    CacheConfig    `yaml:"cache"`
+	SMTP     SMTPConfig     `yaml:"smtp"`
+	Telegram TelegramConfig `yaml:"telegram"`
+}
+
+// TelegramConfig містить налаштування бота для сповіщень
+type TelegramConfig struct {
+	// BotToken - токен від @BotFather; якщо порожній, сповіщення в Telegram не надсилаються
+	BotToken string `yaml:"bot_token"`
+	// BotUsername - ім'я бота без @ для посилань підключення t.me
+	BotUsername string `yaml:"bot_username"`
+	// PollTimeout - тривалість одного запиту long polling
+	PollTimeout time.Duration `yaml:"poll_timeout"`
+}
+
+// SMTPConfig містить налаштування поштового сервера
+type SMTPConfig struct {
+	// Host - адреса SMTP-сервера; якщо порожня, листи не надсилаються
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port"`
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	From     string        `yaml:"from"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+// CacheConfig містить налаштування кешу
+type CacheConfig struct {
+	// RedisHost - хост Redis; якщо порожній, використовується кеш у пам'яті процесу
+	RedisHost     string `yaml:"redis_host"`
+	RedisPort     string `yaml:"redis_port"`
+	RedisPassword string `yaml:"redis_password"`
+	RedisDB       int    `yaml:"redis_db"`
+	// MaxEntries - максимальна кількість записів кешу в пам'яті
+	MaxEntries int `yaml:"max_entries"`
 }
 
 // ServerConfig містить налаштування сервера
@@ -23,6 +60,8 @@ type ServerConfig struct {
 	ReadTimeout    time.Duration `yaml:"read_timeout"`
 	WriteTimeout   time.Duration `yaml:"write_timeout"`
 	MaxHeaderBytes int           `yaml:"max_header_bytes"`
+	// BaseURL - зовнішня адреса застосунку для публічних посилань
+	BaseURL string `yaml:"base_url"`
 }
 
 // DatabaseConfig містить налаштування бази даних
@@ -44,18 +83,30 @@ type JWTConfig struct {
 	Audience                string        `yaml:"audience"`
 }
 
+// RedisAddr повертає адресу Redis або порожній рядок, якщо Redis не налаштований
+func (c *CacheConfig) RedisAddr() string {
+	if c.RedisHost == "" {
+		return ""
+	}
+	return c.RedisHost + ":" + c.RedisPort
+}
+
+// ErrMissingSigningKey - не задано ключ підпису посилань на файли
+var ErrMissingSigningKey = errors.New("STORAGE_SIGNING_KEY must be set")
+
 // Load завантажує конфігурацію з .env файлу та змінних середовища
 func Load() (*Config, error) {
 	// Завантажуємо .env файл, якщо він існує
 	_ = godotenv.Load()
 
-	return &Config{
+	cfg := &Config{
 		Server: ServerConfig{
 			Address:        getEnv("SERVER_ADDRESS", ":3000"),
 			CorsOrigins:    []string{getEnv("CORS_ORIGINS", "*")},
 			ReadTimeout:    time.Duration(getEnvInt("SERVER_READ_TIMEOUT", 60)) * time.Second,
 			WriteTimeout:   time.Duration(getEnvInt("SERVER_WRITE_TIMEOUT", 60)) * time.Second,
 			MaxHeaderBytes: 1 << 20, // 1MB
+			BaseURL:        strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:3000"), "/"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -72,16 +123,49 @@ func Load() (*Config, error) {
 			Issuer:                  getEnv("JWT_ISSUER", "timebride"),
 			Audience:                getEnv("JWT_AUDIENCE", "timebride-api"),
 		},
+		Cache: CacheConfig{
+			RedisHost:     getEnv("REDIS_HOST", ""),
+			RedisPort:     getEnv("REDIS_PORT", "6379"),
+			RedisPassword: getEnv("REDIS_PASSWORD", ""),
+			RedisDB:       getEnvInt("REDIS_DB", 0),
+			MaxEntries:    getEnvInt("CACHE_MAX_ENTRIES", 10000),
+		},
 		Storage: StorageConfig{
-			Provider:  getEnv("STORAGE_PROVIDER", "local"),
-			Path:      getEnv("STORAGE_PATH", "./storage"),
-			MaxSizeGB: getEnvInt("STORAGE_MAX_SIZE_GB", 100),
-			Region:    getEnv("STORAGE_REGION", ""),
-			Endpoint:  getEnv("STORAGE_ENDPOINT", ""),
-			AccessKey: getEnv("STORAGE_ACCESS_KEY", ""),
-			SecretKey: getEnv("STORAGE_SECRET_KEY", ""),
+			Provider:           getEnv("STORAGE_PROVIDER", "local"),
+			Path:               getEnv("STORAGE_PATH", "./storage"),
+			MaxSizeGB:          getEnvInt("STORAGE_MAX_SIZE_GB", 100),
+			Region:             getEnv("STORAGE_REGION", ""),
+			Endpoint:           getEnv("STORAGE_ENDPOINT", ""),
+			AccessKey:          getEnv("STORAGE_ACCESS_KEY", ""),
+			SecretKey:          getEnv("STORAGE_SECRET_KEY", ""),
+			SigningKey:         getEnv("STORAGE_SIGNING_KEY", ""),
+			URLExpiry:          time.Duration(getEnvInt("STORAGE_URL_EXPIRY_MINUTES", 60)) * time.Minute,
+			TrashRetentionDays: getEnvInt("STORAGE_TRASH_RETENTION_DAYS", 30),
+			ClamdAddr:          getEnv("CLAMD_ADDR", ""),
+			ScanTimeout:        time.Duration(getEnvInt("CLAMD_TIMEOUT_SECONDS", 300)) * time.Second,
+			ClientUploadMaxMB:  getEnvInt("CLIENT_UPLOAD_MAX_MB", 50),
+			UploadMaxMB:        getEnvInt("STORAGE_UPLOAD_MAX_MB", 2048),
+		},
+		Telegram: TelegramConfig{
+			BotToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
+			BotUsername: getEnv("TELEGRAM_BOT_USERNAME", ""),
+			PollTimeout: time.Duration(getEnvInt("TELEGRAM_POLL_TIMEOUT_SECONDS", 30)) * time.Second,
 		},
-	}, nil
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "TimeBride <no-reply@timebride.app>"),
+			Timeout:  time.Duration(getEnvInt("SMTP_TIMEOUT_SECONDS", 30)) * time.Second,
+		},
+	}
+
+	// Відомий ключ за замовчуванням дозволив би підробляти посилання на файли та галереї
+	if cfg.Storage.SigningKey == "" {
+		return nil, ErrMissingSigningKey
+	}
+	return cfg, nil
 }
 
 // getEnv отримує значення змінної середовища або повертає значення за замовчуванням
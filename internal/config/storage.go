@@ -1,14 +1,28 @@
 package config
 
+import "time"
+
 // StorageConfig містить налаштування сховища файлів
 type StorageConfig struct {
-	Provider  string `yaml:"provider"` // local, s3, etc.
-	Path      string `yaml:"path"`     // локальний шлях або bucket
-	MaxSizeGB int    `yaml:"max_size_gb"`
-	Region    string `yaml:"region"`   // для cloud storage
-	Endpoint  string `yaml:"endpoint"` // для custom endpoints
-	AccessKey string `yaml:"access_key"`
-	SecretKey string `yaml:"secret_key"`
+	Provider   string        `yaml:"provider"` // local, s3, etc.
+	Path       string        `yaml:"path"`     // локальний шлях або bucket
+	MaxSizeGB  int           `yaml:"max_size_gb"`
+	Region     string        `yaml:"region"`   // для cloud storage
+	Endpoint   string        `yaml:"endpoint"` // для custom endpoints
+	AccessKey  string        `yaml:"access_key"`
+	SecretKey  string        `yaml:"secret_key"`
+	SigningKey string        `yaml:"signing_key"` // ключ HMAC для підпису посилань на файли
+	URLExpiry  time.Duration `yaml:"url_expiry"`  // термін дії підписаних посилань
+	// TrashRetentionDays - скільки днів файли зберігаються в кошику перед остаточним видаленням
+	TrashRetentionDays int `yaml:"trash_retention_days"`
+	// ClamdAddr - адреса clamd ("host:port" або "unix:/path"); якщо порожня, перевірка вимкнена
+	ClamdAddr string `yaml:"clamd_addr"`
+	// ScanTimeout - максимальний час перевірки одного файлу в clamd
+	ScanTimeout time.Duration `yaml:"scan_timeout"`
+	// ClientUploadMaxMB - максимальний розмір файлу, який клієнт може надіслати з публічної сторінки
+	ClientUploadMaxMB int `yaml:"client_upload_max_mb"`
+	// UploadMaxMB - максимальний розмір файлу, який студія завантажує у своє сховище
+	UploadMaxMB int `yaml:"upload_max_mb"`
 }
 
 // GetStorageProvider повертає тип провайдера сховища
@@ -49,3 +63,43 @@ func (c *StorageConfig) GetEndpoint() string {
 func (c *StorageConfig) HasCredentials() bool {
 	return c.AccessKey != "" && c.SecretKey != ""
 }
+
+// GetURLExpiry повертає термін дії підписаних посилань
+func (c *StorageConfig) GetURLExpiry() time.Duration {
+	if c.URLExpiry <= 0 {
+		return time.Hour
+	}
+	return c.URLExpiry
+}
+
+// GetRegion повертає регіон хмарного сховища
+func (c *StorageConfig) GetRegion() string {
+	if c.Region == "" {
+		return "us-east-1"
+	}
+	return c.Region
+}
+
+// GetClientUploadMaxSize повертає максимальний розмір файлу від клієнта в байтах
+func (c *StorageConfig) GetClientUploadMaxSize() int64 {
+	if c.ClientUploadMaxMB <= 0 {
+		return 50 * 1024 * 1024
+	}
+	return int64(c.ClientUploadMaxMB) * 1024 * 1024
+}
+
+// GetUploadMaxSize повертає максимальний розмір файлу від студії в байтах
+func (c *StorageConfig) GetUploadMaxSize() int64 {
+	if c.UploadMaxMB <= 0 {
+		return 2048 * 1024 * 1024
+	}
+	return int64(c.UploadMaxMB) * 1024 * 1024
+}
+
+// GetTrashRetention повертає термін зберігання файлів у кошику
+func (c *StorageConfig) GetTrashRetention() time.Duration {
+	if c.TrashRetentionDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+const (
+	// drainLease - час на обробку взятої події; після нього подія знову доступна обробнику
+	drainLease = 5 * time.Minute
+	// drainBatch - кількість подій, що обробляються за один запуск
+	drainBatch = 100
+)
+
+// retryDelays - паузи перед повторними спробами; після останньої подія вважається необробленою
+var retryDelays = []time.Duration{
+	10 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+// Handler обробляє подію. Помилка повторює обробку пізніше лише для цього підписника.
+type Handler func(ctx context.Context, event Event) error
+
+// eventIDKey - ключ контексту, під яким обробник отримує ідентифікатор події в outbox
+type eventIDKey struct{}
+
+// ID повертає ідентифікатор події, яку обробляє підписник; однаковий для всіх повторних спроб,
+// тож підписники можуть за ним відкидати дублікати. Поза обробником шини повертає uuid.Nil.
+func ID(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(eventIDKey{}).(uuid.UUID)
+	return id
+}
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// Bus розсилає події з outbox підписникам у межах процесу
+type Bus struct {
+	outbox repositories.EventOutboxRepository
+
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+}
+
+// NewBus створює шину подій поверх outbox
+func NewBus(outbox repositories.EventOutboxRepository) *Bus {
+	return &Bus{
+		outbox:      outbox,
+		subscribers: make(map[string][]subscriber),
+	}
+}
+
+// Subscribe підписує обробник на подію з назвою event. Назва підписника name має бути
+// стабільною: за нею outbox запам'ятовує, хто вже обробив подію.
+func (b *Bus) Subscribe(event, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.subscribers[event] {
+		if s.name == name {
+			panic(fmt.Sprintf("events: subscriber %q is already subscribed to %s", name, event))
+		}
+	}
+	b.subscribers[event] = append(b.subscribers[event], subscriber{name: name, handle: handler})
+}
+
+// Subscribe підписує типізований обробник на подію типу T
+func Subscribe[T Event](b *Bus, name string, handler func(ctx context.Context, event T) error) {
+	var zero T
+	b.Subscribe(zero.Name(), name, func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("unexpected %T for %s", event, zero.Name())
+		}
+		return handler(ctx, typed)
+	})
+}
+
+// Drain обробляє події з outbox, час яких настав; повертає кількість повністю оброблених.
+// Кожен підписник отримує подію принаймні один раз: після збою до нього надходять повторні спроби,
+// а підписники, що вже обробили подію, пропускаються.
+func (b *Bus) Drain(ctx context.Context) (int, error) {
+	due, err := b.outbox.Claim(ctx, time.Now(), drainLease, drainBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, record := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if b.dispatch(ctx, record) {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// dispatch передає подію підписникам, які її ще не обробили, та записує результат
+func (b *Bus) dispatch(ctx context.Context, record *models.DomainEvent) bool {
+	record.Attempts++
+	event, err := decode(record)
+	if err != nil {
+		b.fail(ctx, record, err)
+		return false
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers[record.Name]
+	b.mu.RUnlock()
+
+	var errs []error
+	handlerCtx := context.WithValue(ctx, eventIDKey{}, record.ID)
+	for _, s := range subscribers {
+		if handled(record, s.name) {
+			continue
+		}
+		if err := b.handle(handlerCtx, s, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		record.Handled = append(record.Handled, s.name)
+	}
+
+	now := time.Now()
+	switch {
+	case len(errs) == 0:
+		record.Status = models.EventStatusProcessed
+		record.ProcessedAt = &now
+		record.NextAttemptAt = nil
+		record.LastError = ""
+	case record.Attempts > len(retryDelays):
+		b.fail(ctx, record, errors.Join(errs...))
+		return false
+	default:
+		next := now.Add(retryDelays[record.Attempts-1])
+		record.NextAttemptAt = &next
+		record.LastError = errors.Join(errs...).Error()
+		log.Printf("Failed to handle event %s %s (attempt %d): %s", record.Name, record.ID, record.Attempts, record.LastError)
+	}
+
+	if err := b.outbox.Update(ctx, record); err != nil {
+		log.Printf("Failed to update event %s: %v", record.ID, err)
+	}
+	return record.Status == models.EventStatusProcessed
+}
+
+// handle викликає обробник підписника; паніка підписника не зупиняє розсилку іншим
+func (b *Bus) handle(ctx context.Context, s subscriber, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handle(ctx, event)
+}
+
+// fail позначає подію необробленою без подальших спроб
+func (b *Bus) fail(ctx context.Context, record *models.DomainEvent, cause error) {
+	record.Status = models.EventStatusFailed
+	record.NextAttemptAt = nil
+	record.LastError = cause.Error()
+	log.Printf("Event %s %s failed: %v", record.Name, record.ID, cause)
+	if err := b.outbox.Update(ctx, record); err != nil {
+		log.Printf("Failed to update event %s: %v", record.ID, err)
+	}
+}
+
+// handled перевіряє, чи підписник уже обробив подію
+func handled(record *models.DomainEvent, name string) bool {
+	for _, h := range record.Handled {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
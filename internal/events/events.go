@@ -0,0 +1,113 @@
+// Package events - шина доменних подій. Сервіси записують події в outbox разом зі зміною,
+// а фоновий обробник розсилає їх підписникам (сповіщення, вебхуки, синхронізація тощо),
+// тож підписники не залежать від коду сервісу, що спричинив подію.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// Event - доменна подія; Name - стабільна назва, під якою подія зберігається в outbox
+type Event interface {
+	Name() string
+}
+
+// BookingCreated - створено бронювання
+type BookingCreated struct {
+	Booking *models.Booking `json:"booking"`
+}
+
+// Name повертає назву події
+func (*BookingCreated) Name() string { return "booking.created" }
+
+// BookingStatusChanged - змінився статус бронювання
+type BookingStatusChanged struct {
+	Booking        *models.Booking      `json:"booking"`
+	PreviousStatus models.BookingStatus `json:"previous_status"`
+}
+
+// Name повертає назву події
+func (*BookingStatusChanged) Name() string { return "booking.status_changed" }
+
+// PaymentReceived - зросла отримана сума за бронювання
+type PaymentReceived struct {
+	BookingID    uuid.UUID `json:"booking_id"`
+	BookingTitle string    `json:"booking_title"`
+	UserID       uuid.UUID `json:"user_id"`
+	ClientID     uuid.UUID `json:"client_id"`
+	// Amount - отримана сума; TotalPaid - усього сплачено; Balance - залишок до сплати
+	Amount    float64 `json:"amount"`
+	TotalPaid float64 `json:"total_paid"`
+	Balance   float64 `json:"balance"`
+}
+
+// Name повертає назву події
+func (*PaymentReceived) Name() string { return "payment.received" }
+
+// GalleryDownloaded - клієнт завантажив файл або архів галереї
+type GalleryDownloaded struct {
+	GalleryID uuid.UUID            `json:"gallery_id"`
+	BookingID uuid.UUID            `json:"booking_id"`
+	UserID    uuid.UUID            `json:"user_id"`
+	Action    models.GalleryAction `json:"action"`
+	// FileID та FileName заповнені для окремого файлу і порожні для архіву
+	FileID   *uuid.UUID `json:"file_id,omitempty"`
+	FileName string     `json:"file_name,omitempty"`
+}
+
+// Name повертає назву події
+func (*GalleryDownloaded) Name() string { return "gallery.downloaded" }
+
+// registry створює порожню подію за назвою для розбору payload з outbox
+var registry = map[string]func() Event{}
+
+func init() {
+	for _, factory := range []func() Event{
+		func() Event { return &BookingCreated{} },
+		func() Event { return &BookingStatusChanged{} },
+		func() Event { return &PaymentReceived{} },
+		func() Event { return &GalleryDownloaded{} },
+	} {
+		registry[factory().Name()] = factory
+	}
+}
+
+// Record готує події до запису в outbox у транзакції зміни
+func Record(events ...Event) ([]*models.DomainEvent, error) {
+	now := time.Now()
+	records := make([]*models.DomainEvent, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s event: %w", event.Name(), err)
+		}
+		records = append(records, &models.DomainEvent{
+			Name:    event.Name(),
+			Payload: payload,
+			Status:  models.EventStatusPending,
+			Handled: []string{},
+			// Подія доступна обробнику одразу після фіксації транзакції
+			NextAttemptAt: &now,
+		})
+	}
+	return records, nil
+}
+
+// decode відновлює типізовану подію із запису outbox
+func decode(record *models.DomainEvent) (Event, error) {
+	factory, ok := registry[record.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", record.Name)
+	}
+	event := factory()
+	if err := json.Unmarshal(record.Payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", record.Name, err)
+	}
+	return event, nil
+}
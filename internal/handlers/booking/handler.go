@@ -1,6 +1,7 @@
 package booking
 
 import (
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -8,21 +9,24 @@ import (
 
 	"timebride/internal/models"
 	"timebride/internal/services/booking"
+	"timebride/internal/services/deadline"
 	"timebride/internal/services/user"
 	"timebride/internal/types"
 )
 
 // Handler реалізує обробку запитів бронювань
 type Handler struct {
-	bookingService booking.IBookingService
-	userService    user.IUserService
+	bookingService  booking.IBookingService
+	userService     user.IUserService
+	deadlineService deadline.IDeadlineService
 }
 
 // NewHandler створює новий екземпляр обробника бронювань
-func NewHandler(bookingService booking.IBookingService, userService user.IUserService) *Handler {
+func NewHandler(bookingService booking.IBookingService, userService user.IUserService, deadlineService deadline.IDeadlineService) *Handler {
 	return &Handler{
-		bookingService: bookingService,
-		userService:    userService,
+		bookingService:  bookingService,
+		userService:     userService,
+		deadlineService: deadlineService,
 	}
 }
 
@@ -221,3 +225,29 @@ func (h *Handler) GetStatistics(c *fiber.Ctx) error {
 		"end_date":       endDate,
 	})
 }
+
+// Deadlines повертає прострочені проєкти та терміни віддачі в найближчі ?days днів (за замовчуванням 14)
+func (h *Handler) Deadlines(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	deadlines, err := h.deadlineService.Upcoming(c.Context(), userID, c.QueryInt("days", 14))
+	if err != nil {
+		if errors.Is(err, deadline.ErrInvalidPeriod) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch deadlines",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"deadlines": deadlines,
+	})
+}
@@ -0,0 +1,273 @@
+package contract
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	storagehandler "timebride/internal/handlers/storage"
+	"timebride/internal/merge"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/contract"
+	"timebride/internal/services/storage"
+)
+
+// Handler обробляє запити договорів бронювань
+type Handler struct {
+	contractService contract.IContractService
+	storageService  storage.IStorageService
+}
+
+// NewHandler створює новий обробник договорів
+func NewHandler(contractService contract.IContractService, storageService storage.IStorageService) *Handler {
+	return &Handler{
+		contractService: contractService,
+		storageService:  storageService,
+	}
+}
+
+// Generate формує нову версію договору бронювання з шаблону template_id
+func (h *Handler) Generate(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	var input struct {
+		TemplateID uuid.UUID `json:"template_id"`
+	}
+	if err := c.BodyParser(&input); err != nil || input.TemplateID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "template_id is required",
+		})
+	}
+
+	version, err := h.contractService.Generate(c.Context(), userID, bookingID, input.TemplateID)
+	if err != nil {
+		return contractError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(version)
+}
+
+// Versions повертає історію версій договору бронювання
+func (h *Handler) Versions(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	versions, err := h.contractService.Versions(c.Context(), userID, bookingID)
+	if err != nil {
+		return contractError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"versions": versions,
+	})
+}
+
+// List повертає зведену таблицю договорів: останню версію договору кожного бронювання.
+// Фільтри: client_id, q (ім'я клієнта), status, from та to (дата події, YYYY-MM-DD, включно).
+// Браузеру віддається сторінка, решті клієнтів - JSON.
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	opts, err := parseListOptions(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	contracts, err := h.contractService.List(c.Context(), userID, opts)
+	if err != nil {
+		return contractError(c, err)
+	}
+
+	if c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+		return c.Render("contract/list", fiber.Map{
+			"Title":     "Договори",
+			"Contracts": contracts,
+			"Search":    opts.Search,
+			"Status":    opts.Status,
+			"From":      c.Query("from"),
+			"To":        c.Query("to"),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"contracts": contracts,
+	})
+}
+
+// Replace зберігає завантажений PDF (поле форми "file") як нову версію договору бронювання
+func (h *Handler) Replace(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No file provided",
+		})
+	}
+
+	version, err := h.contractService.Replace(c.Context(), userID, bookingID, header)
+	if err != nil {
+		return contractError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(version)
+}
+
+// Delete видаляє договір бронювання з усіма версіями
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.contractService.Delete(c.Context(), userID, bookingID); err != nil {
+		return contractError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Send відкриває останню версію договору для підписання на публічній сторінці бронювання
+func (h *Handler) Send(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	version, err := h.contractService.Send(c.Context(), userID, bookingID)
+	if err != nil {
+		return contractError(c, err)
+	}
+
+	return c.JSON(version)
+}
+
+// File відкриває PDF версії договору; для підписаної версії - примірник з журналом підписання
+// (?download=1 - як вкладення)
+func (h *Handler) File(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid contract ID")
+	}
+
+	version, err := h.contractService.Get(c.Context(), userID, id)
+	if err != nil {
+		return contractError(c, err)
+	}
+
+	content, err := h.storageService.OpenFile(c.Context(), version.Document())
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Contract file not found")
+	}
+
+	return storagehandler.SendContent(c, content, c.QueryBool("download"))
+}
+
+// parseListOptions збирає фільтри зведеної таблиці з параметрів запиту
+func parseListOptions(c *fiber.Ctx) (models.ContractListOptions, error) {
+	opts := models.ContractListOptions{
+		Search: strings.TrimSpace(c.Query("q")),
+		Status: models.ContractStatus(c.Query("status")),
+	}
+
+	if raw := c.Query("client_id"); raw != "" {
+		clientID, err := uuid.Parse(raw)
+		if err != nil {
+			return opts, errors.New("Invalid client_id")
+		}
+		opts.ClientID = &clientID
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return opts, errors.New("Invalid from date")
+		}
+		opts.EventFrom = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return opts, errors.New("Invalid to date")
+		}
+		// Дата "до" включається цілим днем
+		to = to.AddDate(0, 0, 1)
+		opts.EventTo = &to
+	}
+	return opts, nil
+}
+
+// ownerParams повертає ID поточного користувача та бронювання з параметра :id
+func ownerParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	bookingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid booking ID")
+	}
+
+	return userID, bookingID, nil
+}
+
+// contractError перетворює помилку сервісу на відповідь
+func contractError(c *fiber.Ctx, err error) error {
+	var invalid *merge.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":    "Invalid template",
+			"problems": invalid.Problems,
+		})
+	case errors.Is(err, contract.ErrContractSigned),
+		errors.Is(err, repositories.ErrContractHasSigned):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, contract.ErrNotContractTemplate),
+		errors.Is(err, contract.ErrNotPDF),
+		errors.Is(err, contract.ErrInvalidListFilter):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repositories.ErrBookingNotFound),
+		errors.Is(err, repositories.ErrTemplateNotFound),
+		errors.Is(err, repositories.ErrContractNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repositories.ErrStorageQuotaExceeded):
+		return c.Status(fiber.StatusInsufficientStorage).JSON(fiber.Map{
+			"error": "Storage quota exceeded",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process contract",
+		})
+	}
+}
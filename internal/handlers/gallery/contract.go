@@ -0,0 +1,101 @@
+package gallery
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	storagehandler "timebride/internal/handlers/storage"
+	"timebride/internal/repositories"
+	"timebride/internal/services/contract"
+)
+
+// signatureDataURLPrefix - префікс підпису, отриманого з canvas.toDataURL()
+const signatureDataURLPrefix = "data:image/png;base64,"
+
+// Contract відкриває PDF договору, надісланого клієнту; після підписання - підписаний примірник
+func (h *Handler) Contract(c *fiber.Ctx) error {
+	g, err := h.galleryService.Access(c.Context(), c.Params("token"), visitor(c))
+	if err != nil {
+		return publicError(err)
+	}
+
+	agreement, err := h.contractService.ForClient(c.Context(), g.BookingID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Contract not found")
+	}
+
+	content, err := h.storageService.OpenFile(c.Context(), agreement.Document())
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Contract not found")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return storagehandler.SendContent(c, content, c.QueryBool("download"))
+}
+
+// SignContract фіксує підпис клієнта: ПІБ та намальований підпис (PNG data URL)
+func (h *Handler) SignContract(c *fiber.Ctx) error {
+	v := visitor(c)
+	g, err := h.galleryService.Access(c.Context(), c.Params("token"), v)
+	if err != nil {
+		return publicError(err)
+	}
+
+	var input struct {
+		ContractID uuid.UUID `json:"contract_id"`
+		Name       string    `json:"name"`
+		Signature  string    `json:"signature"`
+	}
+	if err := c.BodyParser(&input); err != nil || !strings.HasPrefix(input.Signature, signatureDataURLPrefix) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Вкажіть ПІБ та намалюйте підпис",
+		})
+	}
+	image, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(input.Signature, signatureDataURLPrefix))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Вкажіть ПІБ та намалюйте підпис",
+		})
+	}
+
+	agreement, err := h.contractService.Sign(c.Context(), g.BookingID, &contract.SignInput{
+		ContractID: input.ContractID,
+		Name:       input.Name,
+		Signature:  image,
+		IP:         v.IP,
+		UserAgent:  v.UserAgent,
+	})
+	switch {
+	case errors.Is(err, contract.ErrInvalidSignerName):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Вкажіть повне імʼя",
+		})
+	case errors.Is(err, contract.ErrInvalidSignature):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Намалюйте підпис",
+		})
+	case errors.Is(err, contract.ErrContractChanged):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Договір оновлено, оновіть сторінку та перегляньте нову версію",
+		})
+	case errors.Is(err, contract.ErrContractSigned), errors.Is(err, repositories.ErrContractNotSignable):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Договір уже підписано",
+		})
+	case errors.Is(err, repositories.ErrContractNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Contract not found")
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Не вдалося підписати договір, спробуйте пізніше",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":    agreement.Status,
+		"signed_at": agreement.SignedAt,
+	})
+}
@@ -0,0 +1,506 @@
+package gallery
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	storagehandler "timebride/internal/handlers/storage"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/contract"
+	"timebride/internal/services/gallery"
+	"timebride/internal/services/storage"
+)
+
+// accessCookie - cookie з токеном доступу до захищеної паролем сторінки
+const accessCookie = "gallery_access"
+
+// maxActivityRecords - максимальна кількість записів журналу доступу в одній відповіді
+const maxActivityRecords = 500
+
+// Handler обробляє запити публічних галерей бронювань
+type Handler struct {
+	galleryService  gallery.IGalleryService
+	contractService contract.IContractService
+	storageService  storage.IStorageService
+}
+
+// NewHandler створює новий обробник галерей
+func NewHandler(galleryService gallery.IGalleryService, contractService contract.IContractService, storageService storage.IStorageService) *Handler {
+	return &Handler{
+		galleryService:  galleryService,
+		contractService: contractService,
+		storageService:  storageService,
+	}
+}
+
+// galleryItem містить дані файлу для шаблону галереї
+type galleryItem struct {
+	ID          uuid.UUID
+	Name        string
+	Size        string
+	MimeType    string
+	ThumbURL    string
+	PreviewURL  string
+	URL         string
+	DownloadURL string
+}
+
+// Get повертає галерею бронювання
+func (h *Handler) Get(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	g, err := h.galleryService.GetByBooking(c.Context(), userID, bookingID)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(g)
+}
+
+// Generate створює галерею або перегенеровує її посилання
+func (h *Handler) Generate(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	g, err := h.galleryService.Generate(c.Context(), userID, bookingID)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(g)
+}
+
+// Disable вимикає публічну сторінку
+func (h *Handler) Disable(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.galleryService.Disable(c.Context(), userID, bookingID); err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Update змінює налаштування доступу до сторінки
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	var input models.GalleryUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input data",
+		})
+	}
+
+	g, err := h.galleryService.Update(c.Context(), userID, bookingID, &input)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(g)
+}
+
+// Extend продовжує доступ до сторінки
+func (h *Handler) Extend(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	var input struct {
+		Days int `json:"days"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid input data",
+			})
+		}
+	}
+
+	g, err := h.galleryService.Extend(c.Context(), userID, bookingID, input.Days)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(g)
+}
+
+// Activity повертає статистику переглядів та завантажень сторінки
+func (h *Handler) Activity(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > maxActivityRecords {
+		limit = maxActivityRecords
+	}
+
+	activity, err := h.galleryService.Activity(c.Context(), userID, bookingID, limit)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(activity)
+}
+
+// UpdateBranding оновлює оформлення публічних сторінок студії
+func (h *Handler) UpdateBranding(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input gallery.Branding
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input data",
+		})
+	}
+
+	user, err := h.galleryService.UpdateBranding(c.Context(), userID, &input)
+	switch {
+	case errors.Is(err, gallery.ErrInvalidTemplate), errors.Is(err, gallery.ErrInvalidBrandingLink):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update gallery settings",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"template":     user.GetGalleryTemplate(),
+		"logo_url":     user.GalleryLogoURL,
+		"social_links": user.GetSocialLinks(),
+		"templates":    models.GalleryTemplates,
+	})
+}
+
+// Show відображає публічну сторінку галереї
+func (h *Handler) Show(c *fiber.Ctx) error {
+	view, err := h.galleryService.Open(c.Context(), c.Params("token"), visitor(c))
+	switch {
+	case errors.Is(err, gallery.ErrPasswordRequired):
+		return h.renderLocked(c, fiber.StatusUnauthorized, "")
+	case errors.Is(err, gallery.ErrGalleryExpired):
+		return h.renderLocked(c, fiber.StatusGone, "")
+	case err != nil:
+		return h.notFound(c)
+	}
+
+	// Договір показується лише після надсилання клієнту
+	agreement, err := h.contractService.ForClient(c.Context(), view.Booking.ID)
+	if err != nil && !errors.Is(err, repositories.ErrContractNotFound) {
+		log.Printf("Failed to load contract for gallery %s: %v", view.Gallery.ID, err)
+	}
+
+	base := view.Gallery.PublicPath()
+	return c.Render("gallery/index", fiber.Map{
+		"Title":         view.Booking.Title,
+		"Template":      view.Studio.GetGalleryTemplate(),
+		"StudioName":    studioName(view.Studio),
+		"LogoURL":       view.Studio.GalleryLogoURL,
+		"SocialLinks":   view.Studio.GetSocialLinks(),
+		"Booking":       view.Booking.ToPublic(),
+		"ExpiresAt":     view.Gallery.ExpiresAt,
+		"AllowDownload": view.Gallery.AllowDownload && !view.Gallery.DownloadLimitReached() && !view.OriginalsLocked,
+		"Locked":        view.OriginalsLocked,
+		"Proofing":      view.Gallery.ProofingEnabled,
+		"MaxSelections": view.Gallery.MaxSelections,
+		"Submitted":     view.Gallery.SelectionSubmitted(),
+		"ProofingURL":   base + "/proofing",
+		"ArchiveURL":    base + "/archive",
+		"ClientUploads": view.Gallery.ClientUploads,
+		"UploadURL":     base + "/uploads",
+		"UploadMaxMB":   view.UploadMaxBytes / (1024 * 1024),
+		"UploadAccept":  gallery.ClientUploadAccept(),
+		"Contract":      agreement,
+		"ContractURL":   base + "/contract",
+		"SignURL":       base + "/contract/sign",
+		"Images":        items(base, view.Images),
+		"Videos":        items(base, view.Videos),
+		"Others":        items(base, view.Others),
+	})
+}
+
+// Unlock перевіряє пароль сторінки та зберігає доступ у cookie
+func (h *Handler) Unlock(c *fiber.Ctx) error {
+	token := c.Params("token")
+	accessToken, err := h.galleryService.Unlock(c.Context(), token, c.FormValue("password"), visitor(c))
+	switch {
+	case errors.Is(err, gallery.ErrInvalidPassword):
+		return h.renderLocked(c, fiber.StatusUnauthorized, "Невірний пароль")
+	case err != nil:
+		return h.notFound(c)
+	}
+
+	path := "/g/" + token
+	c.Cookie(&fiber.Cookie{
+		Name:     accessCookie,
+		Value:    accessToken,
+		Path:     path,
+		Expires:  time.Now().Add(30 * 24 * time.Hour),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(path, fiber.StatusSeeOther)
+}
+
+// File віддає файл галереї (?download=1 - як вкладення).
+// Без дозволу на завантаження на сторінці доступне лише відтворення відео.
+func (h *Handler) File(c *fiber.Ctx) error {
+	download := c.QueryBool("download")
+	file, err := h.galleryFile(c, h.galleryService.GetFile)
+	if err != nil {
+		return err
+	}
+	switch {
+	case download || !file.IsVideo():
+		file, err = h.galleryFile(c, h.galleryService.Download)
+	case isFirstRange(c):
+		// Плеєр запитує відео частинами - переглядом рахується лише перший запит
+		file, err = h.galleryFile(c, h.galleryService.ViewFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := h.storageService.OpenFile(c.Context(), file)
+	if err != nil {
+		return h.notFound(c)
+	}
+
+	return storagehandler.SendContent(c, content, download)
+}
+
+// Thumbnail віддає зменшену копію зображення (?size=large - для перегляду).
+// До повної оплати бронювання копія містить водяний знак студії.
+func (h *Handler) Thumbnail(c *fiber.Ctx) error {
+	fileID, err := uuid.Parse(c.Params("file"))
+	if err != nil {
+		return h.notFound(c)
+	}
+
+	size := storage.ThumbnailSmall
+	if c.Query("size") == "large" {
+		size = storage.ThumbnailLarge
+	}
+
+	content, err := h.galleryService.Preview(c.Context(), c.Params("token"), fileID, size, visitor(c))
+	if err != nil {
+		return publicError(err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	return storagehandler.SendContent(c, content, false)
+}
+
+// Archive віддає ZIP-архів усіх файлів галереї
+func (h *Handler) Archive(c *fiber.Ctx) error {
+	view, err := h.galleryService.Archive(c.Context(), c.Params("token"), visitor(c))
+	if err != nil {
+		return publicError(err)
+	}
+
+	files := make([]*models.File, 0, len(view.Images)+len(view.Videos)+len(view.Others))
+	files = append(files, view.Images...)
+	files = append(files, view.Videos...)
+	files = append(files, view.Others...)
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Attachment(view.Booking.Title + ".zip")
+
+	// Архів пишеться у відповідь після завершення обробника, тому контекст запиту тут недоступний
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.storageService.WriteArchive(context.Background(), w, files); err != nil {
+			log.Printf("Failed to stream gallery archive: %v", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// ActivitySummary повертає активність клієнтів у всіх галереях за останні ?days днів
+func (h *Handler) ActivitySummary(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	days := c.QueryInt("days", 7)
+	if days <= 0 || days > 365 {
+		days = 365
+	}
+
+	activity, err := h.galleryService.ActivitySummary(c.Context(), userID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load gallery activity",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"days":     days,
+		"bookings": activity,
+	})
+}
+
+// fileLookup - метод сервісу, що повертає файл галереї для відвідувача
+type fileLookup func(ctx context.Context, token string, fileID uuid.UUID, visitor *gallery.Visitor) (*models.File, error)
+
+// galleryFile повертає файл за токеном галереї та параметром :file
+func (h *Handler) galleryFile(c *fiber.Ctx, lookup fileLookup) (*models.File, error) {
+	fileID, err := uuid.Parse(c.Params("file"))
+	if err != nil {
+		return nil, h.notFound(c)
+	}
+
+	file, err := lookup(c.Context(), c.Params("token"), fileID, visitor(c))
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return file, nil
+}
+
+// publicError перетворює помилку доступу до файлів галереї на HTTP-помилку
+func publicError(err error) error {
+	switch {
+	case errors.Is(err, gallery.ErrPasswordRequired):
+		return fiber.NewError(fiber.StatusUnauthorized, "Gallery password required")
+	case errors.Is(err, gallery.ErrGalleryExpired):
+		return fiber.NewError(fiber.StatusGone, "Gallery access has expired")
+	case errors.Is(err, gallery.ErrDownloadsDisabled):
+		return fiber.NewError(fiber.StatusForbidden, "Downloads are disabled")
+	case errors.Is(err, gallery.ErrDownloadLimitReached):
+		return fiber.NewError(fiber.StatusForbidden, "Download limit reached")
+	case errors.Is(err, gallery.ErrOriginalsLocked):
+		return fiber.NewError(fiber.StatusPaymentRequired, "Originals are available after full payment")
+	default:
+		return fiber.NewError(fiber.StatusNotFound, "Gallery not found")
+	}
+}
+
+// isFirstRange перевіряє, чи це перший запит потоку (без Range або з початку файлу)
+func isFirstRange(c *fiber.Ctx) bool {
+	rangeHeader := c.Get(fiber.HeaderRange)
+	return rangeHeader == "" || strings.HasPrefix(rangeHeader, "bytes=0-")
+}
+
+// renderLocked показує сторінку введення пароля (401) або завершення доступу (410)
+func (h *Handler) renderLocked(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).Render("gallery/locked", fiber.Map{
+		"Expired": status == fiber.StatusGone,
+		"Error":   message,
+		"Action":  "/g/" + c.Params("token") + "/unlock",
+	})
+}
+
+// notFound не розкриває, чи існує галерея, чи її вимкнено
+func (h *Handler) notFound(c *fiber.Ctx) error {
+	return fiber.NewError(fiber.StatusNotFound, "Gallery not found")
+}
+
+// ownerParams повертає ID поточного користувача та бронювання з параметра :id
+func ownerParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	bookingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid booking ID")
+	}
+
+	return userID, bookingID, nil
+}
+
+// ownerError перетворює помилку сервісу на відповідь для власника
+func ownerError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrBookingNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Booking not found",
+		})
+	case errors.Is(err, repositories.ErrGalleryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Gallery not found",
+		})
+	case errors.Is(err, gallery.ErrInvalidDownloadLimit), errors.Is(err, gallery.ErrInvalidMaxSelection):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process gallery",
+		})
+	}
+}
+
+// visitor збирає дані відвідувача публічної сторінки для перевірки доступу та журналу
+func visitor(c *fiber.Ctx) *gallery.Visitor {
+	return &gallery.Visitor{
+		IP:          c.IP(),
+		UserAgent:   c.Get(fiber.HeaderUserAgent),
+		AccessToken: c.Cookies(accessCookie),
+	}
+}
+
+func items(base string, files []*models.File) []galleryItem {
+	result := make([]galleryItem, 0, len(files))
+	for _, file := range files {
+		fileURL := base + "/files/" + file.ID.String()
+		thumbURL := ""
+		previewURL := fileURL
+		if file.IsImage() {
+			thumbURL = base + "/thumbs/" + file.ID.String()
+			previewURL = thumbURL + "?size=large"
+		}
+		result = append(result, galleryItem{
+			ID:          file.ID,
+			Name:        file.Name,
+			Size:        file.GetHumanSize(),
+			MimeType:    file.MimeType,
+			ThumbURL:    thumbURL,
+			PreviewURL:  previewURL,
+			URL:         fileURL,
+			DownloadURL: fileURL + "?download=1",
+		})
+	}
+	return result
+}
+
+func studioName(user *models.User) string {
+	if user.CompanyName != "" {
+		return user.CompanyName
+	}
+	return user.FullName
+}
@@ -0,0 +1,158 @@
+package gallery
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/services/gallery"
+)
+
+// Proofs повертає позначки клієнта в режимі відбору
+func (h *Handler) Proofs(c *fiber.Ctx) error {
+	selection, err := h.galleryService.Proofs(c.Context(), c.Params("token"), visitor(c))
+	if err != nil {
+		return proofingError(c, err)
+	}
+
+	return c.JSON(selection)
+}
+
+// MarkProof позначає фото як обране та/або зберігає коментар клієнта
+func (h *Handler) MarkProof(c *fiber.Ctx) error {
+	fileID, err := uuid.Parse(c.Params("file"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid file ID",
+		})
+	}
+
+	var input models.ProofUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input data",
+		})
+	}
+
+	proof, err := h.galleryService.MarkProof(c.Context(), c.Params("token"), fileID, visitor(c), &input)
+	if err != nil {
+		return proofingError(c, err)
+	}
+
+	return c.JSON(proof)
+}
+
+// SubmitSelection надсилає відбір клієнта фотографу
+func (h *Handler) SubmitSelection(c *fiber.Ctx) error {
+	selection, err := h.galleryService.SubmitSelection(c.Context(), c.Params("token"), visitor(c))
+	if err != nil {
+		return proofingError(c, err)
+	}
+
+	return c.JSON(selection)
+}
+
+// Selection повертає відбір клієнта разом з рядком фільтра для Lightroom
+func (h *Handler) Selection(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	selection, err := h.galleryService.Selection(c.Context(), userID, bookingID)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"selection":        selection,
+		"file_names":       selection.FavoriteNames(),
+		"lightroom_filter": selection.LightroomFilter(),
+	})
+}
+
+// SelectionCSV віддає відбір клієнта у форматі CSV
+func (h *Handler) SelectionCSV(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	selection, err := h.galleryService.Selection(c.Context(), userID, bookingID)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment(fmt.Sprintf("selection-%s.csv", bookingID))
+
+	w := csv.NewWriter(c.Response().BodyWriter())
+	records := [][]string{{"file_name", "favorite", "comment", "updated_at"}}
+	for _, item := range selection.Items {
+		records = append(records, []string{
+			csvCell(item.FileName),
+			strconv.FormatBool(item.Favorite),
+			csvCell(item.Comment),
+			item.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return w.WriteAll(records)
+}
+
+// csvCell екранує текст клієнта, щоб Excel чи Google Sheets не виконали його як формулу
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ReopenSelection дозволяє клієнту змінити надісланий відбір
+func (h *Handler) ReopenSelection(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	g, err := h.galleryService.ReopenSelection(c.Context(), userID, bookingID)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(g)
+}
+
+// proofingError перетворює помилку відбору на відповідь для клієнта
+func proofingError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusNotFound
+	message := "Gallery not found"
+	switch {
+	case errors.Is(err, gallery.ErrPasswordRequired):
+		status, message = fiber.StatusUnauthorized, "Gallery password required"
+	case errors.Is(err, gallery.ErrGalleryExpired):
+		status, message = fiber.StatusGone, "Gallery access has expired"
+	case errors.Is(err, gallery.ErrProofingDisabled):
+		status, message = fiber.StatusForbidden, "Proofing is disabled"
+	case errors.Is(err, gallery.ErrSelectionSubmitted):
+		status, message = fiber.StatusConflict, "Selection has already been submitted"
+	case errors.Is(err, gallery.ErrSelectionLimit):
+		status, message = fiber.StatusUnprocessableEntity, "Selection limit reached"
+	case errors.Is(err, gallery.ErrSelectionEmpty):
+		status, message = fiber.StatusUnprocessableEntity, "Selection is empty"
+	case errors.Is(err, gallery.ErrCommentTooLong):
+		status, message = fiber.StatusBadRequest, "Comment is too long"
+	case errors.Is(err, gallery.ErrFileNotInGallery):
+		status, message = fiber.StatusNotFound, "File not found"
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": message,
+	})
+}
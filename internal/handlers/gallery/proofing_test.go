@@ -0,0 +1,22 @@
+package gallery
+
+import "testing"
+
+func TestCSVCellNeutralisesFormulas(t *testing.T) {
+	cases := map[string]string{
+		`=HYPERLINK("http://evil","x")`: `'=HYPERLINK("http://evil","x")`,
+		"+1+1":                          "'+1+1",
+		"-2+3":                          "'-2+3",
+		"@SUM(A1)":                      "'@SUM(A1)",
+		"\tcmd":                         "'\tcmd",
+		"\rcmd":                         "'\rcmd",
+		"IMG_0001.jpg":                  "IMG_0001.jpg",
+		"дуже подобається":              "дуже подобається",
+		"":                              "",
+	}
+	for in, want := range cases {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package gallery
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"timebride/internal/repositories"
+	"timebride/internal/services/gallery"
+)
+
+// Upload приймає файл, надісланий клієнтом з публічної сторінки (поле форми "file")
+func (h *Handler) Upload(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No file provided",
+		})
+	}
+
+	file, err := h.galleryService.ClientUpload(c.Context(), c.Params("token"), header, visitor(c))
+	switch {
+	case errors.Is(err, gallery.ErrUploadTooLarge):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": "Файл завеликий",
+		})
+	case errors.Is(err, gallery.ErrUploadTypeNotAllowed):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Такий тип файлу не приймається",
+		})
+	case errors.Is(err, repositories.ErrStorageQuotaExceeded):
+		// Клієнту не розкривається стан сховища студії
+		return c.Status(fiber.StatusInsufficientStorage).JSON(fiber.Map{
+			"error": "Не вдалося зберегти файл, зверніться до фотографа",
+		})
+	case errors.Is(err, gallery.ErrClientUploadsDisabled):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Надсилання файлів вимкнено",
+		})
+	case err != nil:
+		return publicError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(file.ToPublic())
+}
+
+// ClientUploads повертає файли, надіслані клієнтом до бронювання
+func (h *Handler) ClientUploads(c *fiber.Ctx) error {
+	userID, bookingID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	files, err := h.galleryService.ClientUploads(c.Context(), userID, bookingID)
+	if err != nil {
+		return ownerError(c, err)
+	}
+
+	return c.JSON(files)
+}
@@ -0,0 +1,92 @@
+package gallery
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/services/gallery"
+)
+
+// Watermark повертає налаштування водяного знака превʼю
+func (h *Handler) Watermark(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	settings, err := h.galleryService.Watermark(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load watermark settings",
+		})
+	}
+
+	return c.JSON(settings)
+}
+
+// UpdateWatermark змінює положення, прозорість та масштаб водяного знака
+func (h *Handler) UpdateWatermark(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input models.WatermarkSettings
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input data",
+		})
+	}
+
+	settings, err := h.galleryService.UpdateWatermark(c.Context(), userID, &input)
+	if err != nil {
+		return watermarkError(c, err)
+	}
+
+	return c.JSON(settings)
+}
+
+// UploadWatermarkLogo завантажує логотип водяного знака (поле форми "logo")
+func (h *Handler) UploadWatermarkLogo(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	header, err := c.FormFile("logo")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Logo file is required",
+		})
+	}
+
+	settings, err := h.galleryService.UploadWatermarkLogo(c.Context(), userID, header)
+	if err != nil {
+		return watermarkError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(settings)
+}
+
+// watermarkError перетворює помилку налаштувань водяного знака на відповідь
+func watermarkError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gallery.ErrInvalidWatermark), errors.Is(err, gallery.ErrInvalidLogo):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update watermark settings",
+		})
+	}
+}
@@ -6,11 +6,17 @@ import (
 	"timebride/internal/handlers/auth"
 	"timebride/internal/handlers/booking"
 	"timebride/internal/handlers/client"
+	"timebride/internal/handlers/contract"
+	"timebride/internal/handlers/gallery"
 	"timebride/internal/handlers/interfaces"
+	"timebride/internal/handlers/job"
+	"timebride/internal/handlers/notification"
 	"timebride/internal/handlers/price"
 	"timebride/internal/handlers/storage"
 	"timebride/internal/handlers/team"
+	"timebride/internal/handlers/template"
 	"timebride/internal/handlers/user"
+	"timebride/internal/handlers/webhook"
 	"timebride/internal/services"
 )
 
@@ -23,6 +29,13 @@ type Handlers struct {
 	Team     interfaces.ITeamHandler
 	Prices   interfaces.IPriceHandler
 	Storage  interfaces.IStorageHandler
+
+	Notifications interfaces.INotificationHandler
+	Galleries     interfaces.IGalleryHandler
+	Templates     interfaces.ITemplateHandler
+	Contracts     interfaces.IContractHandler
+	Webhooks      interfaces.IWebhookHandler
+	Jobs          interfaces.IJobHandler
 }
 
 // NewHandlers створює нову структуру обробників
@@ -30,11 +43,18 @@ func NewHandlers(services *services.Services) *Handlers {
 	return &Handlers{
 		Auth:     auth.NewHandler(services.Auth),
 		Users:    user.NewHandler(services.User),
-		Bookings: booking.NewHandler(services.Booking, services.User),
+		Bookings: booking.NewHandler(services.Booking, services.User, services.Deadline),
 		Clients:  client.NewHandler(services.Client, services.Booking),
 		Team:     team.NewHandler(services.Team),
 		Prices:   price.NewHandler(services.Price),
-		Storage:  storage.NewHandler(services.Storage),
+		Storage:  storage.NewHandler(services.Storage, services.Booking),
+
+		Notifications: notification.NewHandler(services.Notification, services.Digest),
+		Galleries:     gallery.NewHandler(services.Gallery, services.Contract, services.Storage),
+		Templates:     template.NewHandler(services.Template),
+		Contracts:     contract.NewHandler(services.Contract, services.Storage),
+		Webhooks:      webhook.NewHandler(services.Webhook),
+		Jobs:          job.NewHandler(services.Jobs),
 	}
 }
 
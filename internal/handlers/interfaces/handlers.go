@@ -19,6 +19,10 @@ type IUserHandler interface {
 	Get(c *fiber.Ctx) error
 	Update(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
+	NotificationSettings(c *fiber.Ctx) error
+	UpdateNotificationSettings(c *fiber.Ctx) error
+	Requisites(c *fiber.Ctx) error
+	UpdateRequisites(c *fiber.Ctx) error
 }
 
 // IBookingHandler визначає інтерфейс для обробки запитів бронювань
@@ -28,6 +32,7 @@ type IBookingHandler interface {
 	Get(c *fiber.Ctx) error
 	Update(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
+	Deadlines(c *fiber.Ctx) error
 }
 
 // IClientHandler визначає інтерфейс для обробки запитів клієнтів
@@ -62,5 +67,96 @@ type IStorageHandler interface {
 	List(c *fiber.Ctx) error
 	Upload(c *fiber.Ctx) error
 	Download(c *fiber.Ctx) error
+	CreateLink(c *fiber.Ctx) error
+	ServeSigned(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
+	Trash(c *fiber.Ctx) error
+	Restore(c *fiber.Ctx) error
+	Purge(c *fiber.Ctx) error
+	EmptyTrash(c *fiber.Ctx) error
+	Archive(c *fiber.Ctx) error
+}
+
+// IGalleryHandler визначає інтерфейс для обробки запитів публічних галерей
+type IGalleryHandler interface {
+	Get(c *fiber.Ctx) error
+	Generate(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Extend(c *fiber.Ctx) error
+	Activity(c *fiber.Ctx) error
+	UpdateBranding(c *fiber.Ctx) error
+	Watermark(c *fiber.Ctx) error
+	UpdateWatermark(c *fiber.Ctx) error
+	UploadWatermarkLogo(c *fiber.Ctx) error
+	Show(c *fiber.Ctx) error
+	Unlock(c *fiber.Ctx) error
+	Archive(c *fiber.Ctx) error
+	ActivitySummary(c *fiber.Ctx) error
+	Proofs(c *fiber.Ctx) error
+	MarkProof(c *fiber.Ctx) error
+	SubmitSelection(c *fiber.Ctx) error
+	Selection(c *fiber.Ctx) error
+	SelectionCSV(c *fiber.Ctx) error
+	ReopenSelection(c *fiber.Ctx) error
+	Upload(c *fiber.Ctx) error
+	ClientUploads(c *fiber.Ctx) error
+	Contract(c *fiber.Ctx) error
+	SignContract(c *fiber.Ctx) error
+	File(c *fiber.Ctx) error
+	Thumbnail(c *fiber.Ctx) error
+}
+
+// ITemplateHandler визначає інтерфейс для обробки запитів шаблонів документів
+type ITemplateHandler interface {
+	List(c *fiber.Ctx) error
+	Fields(c *fiber.Ctx) error
+	EmailEvents(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Get(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	Preview(c *fiber.Ctx) error
+	Render(c *fiber.Ctx) error
+}
+
+// IContractHandler визначає інтерфейс для обробки запитів договорів
+type IContractHandler interface {
+	List(c *fiber.Ctx) error
+	Generate(c *fiber.Ctx) error
+	Versions(c *fiber.Ctx) error
+	Replace(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	Send(c *fiber.Ctx) error
+	File(c *fiber.Ctx) error
+}
+
+// INotificationHandler визначає інтерфейс для обробки запитів сповіщень
+type INotificationHandler interface {
+	List(c *fiber.Ctx) error
+	MarkRead(c *fiber.Ctx) error
+	MarkAllRead(c *fiber.Ctx) error
+	Deliveries(c *fiber.Ctx) error
+	TelegramStatus(c *fiber.Ctx) error
+	TelegramLink(c *fiber.Ctx) error
+	UnlinkTelegram(c *fiber.Ctx) error
+	DigestPreview(c *fiber.Ctx) error
+}
+
+// IWebhookHandler визначає інтерфейс для обробки запитів вебхуків
+type IWebhookHandler interface {
+	Events(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	Deliveries(c *fiber.Ctx) error
+	Ping(c *fiber.Ctx) error
+	Replay(c *fiber.Ctx) error
+}
+
+// IJobHandler визначає інтерфейс для обробки запитів адміністратора до черги задач
+type IJobHandler interface {
+	List(c *fiber.Ctx) error
+	Retry(c *fiber.Ctx) error
 }
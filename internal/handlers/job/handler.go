@@ -0,0 +1,74 @@
+package job
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"timebride/internal/jobs"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+// Handler обробляє запити адміністратора до черги фонових задач
+type Handler struct {
+	queue jobs.IJobQueue
+}
+
+// NewHandler створює новий обробник черги задач
+func NewHandler(queue jobs.IJobQueue) *Handler {
+	return &Handler{
+		queue: queue,
+	}
+}
+
+// List повертає останні задачі зі станом ?status= (за замовчуванням - невдалі)
+func (h *Handler) List(c *fiber.Ctx) error {
+	status := models.JobStatus(c.Query("status", string(models.JobStatusFailed)))
+	if !status.IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job status",
+		})
+	}
+
+	list, err := h.queue.List(c.Context(), status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch jobs",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"jobs": list,
+	})
+}
+
+// Retry повертає невдалу задачу в чергу
+func (h *Handler) Retry(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	if err := h.queue.Retry(c.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrJobNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, jobs.ErrNotFailed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to retry job",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
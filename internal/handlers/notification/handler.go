@@ -0,0 +1,206 @@
+package notification
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"timebride/internal/repositories"
+	"timebride/internal/services/digest"
+	"timebride/internal/services/notification"
+)
+
+// Handler обробляє запити для роботи зі сповіщеннями
+type Handler struct {
+	notificationService notification.INotificationService
+	digestService       digest.IDigestService
+}
+
+// NewHandler створює новий обробник сповіщень
+func NewHandler(notificationService notification.INotificationService, digestService digest.IDigestService) *Handler {
+	return &Handler{
+		notificationService: notificationService,
+		digestService:       digestService,
+	}
+}
+
+// List повертає сповіщення користувача (?unread=1 - лише непрочитані)
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	notifications, err := h.notificationService.List(c.Context(), userID, c.QueryBool("unread"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch notifications",
+		})
+	}
+
+	unread, err := h.notificationService.CountUnread(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch notifications",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"notifications": notifications,
+		"unread":        unread,
+	})
+}
+
+// MarkRead позначає сповіщення як прочитане
+func (h *Handler) MarkRead(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid notification ID",
+		})
+	}
+
+	if err := h.notificationService.MarkRead(c.Context(), userID, id); err != nil {
+		if errors.Is(err, repositories.ErrNotificationNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Notification not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update notification",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MarkAllRead позначає всі сповіщення як прочитані
+func (h *Handler) MarkAllRead(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if err := h.notificationService.MarkAllRead(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update notifications",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Deliveries повертає журнал доставки сповіщень у зовнішні канали
+func (h *Handler) Deliveries(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	deliveries, err := h.notificationService.Deliveries(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch deliveries",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"deliveries": deliveries,
+	})
+}
+
+// TelegramStatus повертає стан підключення Telegram-бота
+func (h *Handler) TelegramStatus(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	status, err := h.notificationService.TelegramStatus(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch telegram status",
+		})
+	}
+
+	return c.JSON(status)
+}
+
+// TelegramLink повертає посилання, за яким користувач підключає Telegram-бота командою /start
+func (h *Handler) TelegramLink(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	url, err := h.notificationService.TelegramLink(c.Context(), userID)
+	if err != nil {
+		if errors.Is(err, notification.ErrChannelUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Telegram notifications are not configured",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create telegram link",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"url": url,
+	})
+}
+
+// UnlinkTelegram відключає Telegram-бота від акаунта
+func (h *Handler) UnlinkTelegram(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if err := h.notificationService.UnlinkTelegram(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to unlink telegram",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DigestPreview повертає звіт за поточний період у тому вигляді, в якому його буде надіслано
+func (h *Handler) DigestPreview(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	preview, err := h.digestService.Preview(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build digest",
+		})
+	}
+
+	return c.JSON(preview)
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"timebride/internal/services/storage"
+)
+
+// SendContent віддає файл клієнту з підтримкою HTTP Range-запитів,
+// що потрібно для перемотування відео в браузері.
+// Вміст закривається після відправки відповіді.
+func SendContent(c *fiber.Ctx, content *storage.FileContent, attachment bool) error {
+	disposition := "inline"
+	if attachment {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("%s; filename*=UTF-8''%s", disposition, url.PathEscape(content.Name)))
+	c.Set(fiber.HeaderContentType, content.ContentType)
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	if !content.ModTime.IsZero() {
+		c.Set(fiber.HeaderLastModified, content.ModTime.UTC().Format(http.TimeFormat))
+	}
+
+	rangeHeader := c.Get(fiber.HeaderRange)
+	if rangeHeader == "" {
+		return c.SendStream(content.Reader, int(content.Size))
+	}
+
+	start, end, ok := storage.ParseRange(rangeHeader, content.Size)
+	if !ok {
+		content.Reader.Close()
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", content.Size))
+		return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+	}
+
+	if _, err := content.Reader.Seek(start, io.SeekStart); err != nil {
+		content.Reader.Close()
+		return err
+	}
+
+	length := end - start + 1
+	c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, content.Size))
+	c.Status(fiber.StatusPartialContent)
+	return c.SendStream(&limitedReadCloser{
+		Reader: io.LimitReader(content.Reader, length),
+		closer: content.Reader,
+	}, int(length))
+}
+
+// limitedReadCloser закриває файл після того, як fasthttp вичитає діапазон
+type limitedReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r *limitedReadCloser) Close() error {
+	return r.closer.Close()
+}
@@ -1,6 +1,18 @@
 package storage
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"timebride/internal/media"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/booking"
 	"timebride/internal/services/storage"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,16 +22,20 @@ import (
 // Handler обробляє запити для роботи з файлами
 type Handler struct {
 	storageService storage.IStorageService
+	bookingService booking.IBookingService
 }
 
 // NewHandler створює новий обробник файлів
-func NewHandler(storageService storage.IStorageService) *Handler {
+func NewHandler(storageService storage.IStorageService, bookingService booking.IBookingService) *Handler {
 	return &Handler{
 		storageService: storageService,
+		bookingService: bookingService,
 	}
 }
 
-// List повертає список файлів
+// List повертає список файлів.
+// Підтримує фільтри booking_id, type, taken_from, taken_to (YYYY-MM-DD або RFC3339),
+// camera, orientation та сортування sort=created_at|taken_at|name, order=asc|desc.
 func (h *Handler) List(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 	userUUID, err := uuid.Parse(userID)
@@ -29,22 +45,90 @@ func (h *Handler) List(c *fiber.Ctx) error {
 		})
 	}
 
-	filter := map[string]interface{}{
-		"user_id": userUUID,
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
-	files, err := h.storageService.ListFiles(c.Context(), filter)
+	files, err := h.storageService.SearchFiles(c.Context(), userUUID, opts)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch files",
 		})
 	}
 
+	// Посилання в БД мають обмежений термін дії, тому видаємо свіжі
+	for _, file := range files {
+		file.URL = h.storageService.GetFileURL(c.Context(), file)
+	}
+
 	return c.JSON(fiber.Map{
 		"files": files,
 	})
 }
 
+// parseSearchOptions розбирає фільтри списку файлів з рядка запиту
+func parseSearchOptions(c *fiber.Ctx) (models.FileSearchOptions, error) {
+	opts := models.FileSearchOptions{
+		Type:        models.FileType(c.Query("type")),
+		Camera:      c.Query("camera"),
+		Orientation: c.Query("orientation"),
+		SortBy:      c.Query("sort", "created_at"),
+		SortDesc:    c.Query("order") == "desc",
+	}
+
+	if raw := c.Query("booking_id"); raw != "" {
+		bookingID, err := uuid.Parse(raw)
+		if err != nil {
+			return opts, errors.New("Invalid booking ID")
+		}
+		opts.BookingID = &bookingID
+	}
+	// ?folder= без значення відбирає файли студії, ?folder=from-client - надіслані клієнтом
+	if c.Context().QueryArgs().Has("folder") {
+		folder := c.Query("folder")
+		opts.Folder = &folder
+	}
+
+	switch opts.Orientation {
+	case "", media.OrientationLandscape, media.OrientationPortrait, media.OrientationSquare:
+	default:
+		return opts, errors.New("Invalid orientation")
+	}
+
+	if raw := c.Query("taken_from"); raw != "" {
+		from, _, err := parseDateParam(raw)
+		if err != nil {
+			return opts, errors.New("Invalid taken_from")
+		}
+		opts.TakenFrom = &from
+	}
+	if raw := c.Query("taken_to"); raw != "" {
+		to, dateOnly, err := parseDateParam(raw)
+		if err != nil {
+			return opts, errors.New("Invalid taken_to")
+		}
+		if dateOnly {
+			// Дата без часу включає весь день
+			to = to.AddDate(0, 0, 1)
+		}
+		opts.TakenTo = &to
+	}
+
+	return opts, nil
+}
+
+// parseDateParam розбирає дату у форматі YYYY-MM-DD або RFC3339
+func parseDateParam(raw string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	return t, false, err
+}
+
 // Upload завантажує файл
 func (h *Handler) Upload(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
@@ -62,7 +146,33 @@ func (h *Handler) Upload(c *fiber.Ctx) error {
 		})
 	}
 
-	uploadedFile, err := h.storageService.UploadFile(c.Context(), file, userUUID.String())
+	input := &storage.UploadInput{
+		UserID: userUUID,
+		File:   file,
+	}
+	if bookingID := c.FormValue("booking_id"); bookingID != "" {
+		id, err := uuid.Parse(bookingID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid booking ID",
+			})
+		}
+		// Файли бронювання показуються в галереї клієнта, тому прив'язати їх можна лише до свого бронювання
+		owned, err := h.bookingService.Get(c.Context(), id)
+		if err != nil || owned.UserID != userUUID {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Booking not found",
+			})
+		}
+		input.BookingID = &id
+	}
+
+	uploadedFile, err := h.storageService.UploadFile(c.Context(), input)
+	if errors.Is(err, repositories.ErrStorageQuotaExceeded) {
+		return c.Status(fiber.StatusInsufficientStorage).JSON(fiber.Map{
+			"error": "Storage quota exceeded",
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to upload file",
@@ -74,36 +184,298 @@ func (h *Handler) Upload(c *fiber.Ctx) error {
 
 // Download завантажує файл
 func (h *Handler) Download(c *fiber.Ctx) error {
-	fileID := c.Params("id")
-	id, err := uuid.Parse(fileID)
+	file, err := h.ownedFile(c)
+	if err != nil {
+		return err
+	}
+
+	content, err := h.storageService.OpenFile(c.Context(), file)
+	if isScanError(err) {
+		return sendScanError(c, err)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "File not found",
+		})
+	}
+
+	return SendContent(c, content, c.QueryBool("download"))
+}
+
+// CreateLink створює підписане посилання на файл з терміном дії та лімітом завантажень
+func (h *Handler) CreateLink(c *fiber.Ctx) error {
+	file, err := h.ownedFile(c)
 	if err != nil {
+		return err
+	}
+
+	var input struct {
+		ExpiresInMinutes int `json:"expires_in_minutes"`
+		MaxDownloads     int `json:"max_downloads"`
+	}
+	if err := c.BodyParser(&input); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid file ID",
+			"error": "Invalid input data",
 		})
 	}
 
-	reader, err := h.storageService.DownloadFile(c.Context(), id.String())
+	signedURL, err := h.storageService.GetSignedURL(c.Context(), file, storage.SignedURLOptions{
+		Expiry:       time.Duration(input.ExpiresInMinutes) * time.Minute,
+		MaxDownloads: input.MaxDownloads,
+	})
+	if isScanError(err) {
+		return sendScanError(c, err)
+	}
 	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create file link",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"url": signedURL,
+	})
+}
+
+// ServeSigned віддає файл за підписаним посиланням без аутентифікації
+func (h *Handler) ServeSigned(c *fiber.Ctx) error {
+	path, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid file path",
+		})
+	}
+
+	params := storage.SignedURLParams{
+		Expires:   c.Query("expires"),
+		LinkID:    c.Query("link"),
+		Name:      c.Query("name"),
+		Signature: c.Query("sig"),
+		Range:     c.Get(fiber.HeaderRange),
+	}
+
+	content, err := h.storageService.OpenSignedFile(c.Context(), path, params)
+	switch {
+	case err == nil:
+		return SendContent(c, content, c.QueryBool("download"))
+	case errors.Is(err, storage.ErrInvalidSignature):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Invalid signature",
+		})
+	case isScanError(err):
+		return sendScanError(c, err)
+	case errors.Is(err, storage.ErrURLExpired), errors.Is(err, repositories.ErrFileLinkExhausted):
+		return c.Status(fiber.StatusGone).JSON(fiber.Map{
+			"error": "Link has expired",
+		})
+	default:
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "File not found",
 		})
 	}
-	defer reader.Close()
+}
+
+// maxArchiveFiles - максимальна кількість вибраних файлів в одному архіві
+const maxArchiveFiles = 1000
 
-	return c.SendStream(reader)
+// archiveRequest визначає вибір файлів для архіву
+type archiveRequest struct {
+	BookingID string   `json:"booking_id" query:"booking_id"`
+	FileIDs   []string `json:"file_ids" query:"file_ids"`
+}
+
+// Archive потоково віддає ZIP-архів з файлами бронювання або вибраними файлами
+func (h *Handler) Archive(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req archiveRequest
+	if c.Method() == fiber.MethodPost {
+		err = c.BodyParser(&req)
+	} else {
+		err = c.QueryParser(&req)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	files, archiveName, err := h.archiveFiles(c, userID, &req)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "No files to archive",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", archiveName))
+
+	// Архів пишеться безпосередньо у відповідь, після завершення обробника,
+	// тому контекст запиту тут використовувати не можна
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.storageService.WriteArchive(context.Background(), w, files); err != nil {
+			log.Printf("Failed to stream archive: %v", err)
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+// archiveFiles повертає файли для архіву та його ім'я
+func (h *Handler) archiveFiles(c *fiber.Ctx, userID uuid.UUID, req *archiveRequest) ([]*models.File, string, error) {
+	if req.BookingID != "" {
+		bookingID, err := uuid.Parse(req.BookingID)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid booking ID")
+		}
+
+		files, err := h.storageService.ListFiles(c.Context(), map[string]interface{}{
+			"user_id":    userID,
+			"booking_id": bookingID,
+		})
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch files")
+		}
+		return files, "booking-" + bookingID.String() + ".zip", nil
+	}
+
+	if len(req.FileIDs) == 0 {
+		return nil, "", fiber.NewError(fiber.StatusBadRequest, "booking_id or file_ids is required")
+	}
+	if len(req.FileIDs) > maxArchiveFiles {
+		return nil, "", fiber.NewError(fiber.StatusBadRequest, "Too many files selected")
+	}
+
+	files := make([]*models.File, 0, len(req.FileIDs))
+	seen := make(map[uuid.UUID]bool, len(req.FileIDs))
+	for _, rawID := range req.FileIDs {
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid file ID")
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		file, err := h.storageService.GetFile(c.Context(), id)
+		if err != nil || file.UserID != userID {
+			return nil, "", fiber.NewError(fiber.StatusNotFound, "File not found")
+		}
+		files = append(files, file)
+	}
+
+	return files, "files-" + time.Now().Format("20060102-150405") + ".zip", nil
+}
+
+// isScanError перевіряє, чи доступ заблоковано перевіркою на шкідливе ПЗ
+func isScanError(err error) bool {
+	return errors.Is(err, storage.ErrFileQuarantined) || errors.Is(err, storage.ErrFileNotScanned)
+}
+
+// sendScanError повідомляє, чому файл недоступний
+func sendScanError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, storage.ErrFileQuarantined) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "File is quarantined",
+		})
+	}
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "File is being scanned, try again later",
+	})
+}
+
+// ownedFile повертає файл з параметра :id, якщо він належить поточному користувачу
+func (h *Handler) ownedFile(c *fiber.Ctx) (*models.File, error) {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid file ID")
+	}
+
+	file, err := h.storageService.GetFile(c.Context(), id)
+	if err != nil || file.UserID != userID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "File not found")
+	}
+
+	return file, nil
 }
 
 // Delete видаляє файл
 func (h *Handler) Delete(c *fiber.Ctx) error {
-	fileID := c.Params("id")
-	id, err := uuid.Parse(fileID)
+	file, err := h.ownedFile(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.storageService.DeleteFile(c.Context(), file.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete file",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Trash повертає список файлів у кошику
+func (h *Handler) Trash(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid file ID",
+			"error": "Invalid user ID",
+		})
+	}
+
+	files, err := h.storageService.ListTrash(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch trash",
 		})
 	}
 
-	if err := h.storageService.DeleteFile(c.Context(), id.String()); err != nil {
+	return c.JSON(fiber.Map{
+		"files": files,
+	})
+}
+
+// Restore повертає файл з кошика
+func (h *Handler) Restore(c *fiber.Ctx) error {
+	file, err := h.trashedFile(c)
+	if err != nil {
+		return err
+	}
+
+	restored, err := h.storageService.RestoreFile(c.Context(), file.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to restore file",
+		})
+	}
+
+	return c.JSON(restored)
+}
+
+// Purge остаточно видаляє файл з кошика
+func (h *Handler) Purge(c *fiber.Ctx) error {
+	file, err := h.trashedFile(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.storageService.PurgeFile(c.Context(), file.ID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to delete file",
 		})
@@ -111,3 +483,45 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// EmptyTrash остаточно видаляє всі файли з кошика
+func (h *Handler) EmptyTrash(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	purged, err := h.storageService.EmptyTrash(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":  "Failed to empty trash",
+			"purged": purged,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"purged": purged,
+	})
+}
+
+// trashedFile повертає файл з кошика за параметром :id, якщо він належить поточному користувачу
+func (h *Handler) trashedFile(c *fiber.Ctx) (*models.File, error) {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid file ID")
+	}
+
+	file, err := h.storageService.GetTrashedFile(c.Context(), id)
+	if err != nil || file.UserID != userID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "File not found in trash")
+	}
+
+	return file, nil
+}
@@ -0,0 +1,232 @@
+package template
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"timebride/internal/merge"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/email"
+	"timebride/internal/services/template"
+)
+
+// Handler обробляє запити шаблонів документів
+type Handler struct {
+	templateService template.ITemplateService
+}
+
+// NewHandler створює новий обробник шаблонів
+func NewHandler(templateService template.ITemplateService) *Handler {
+	return &Handler{
+		templateService: templateService,
+	}
+}
+
+// previewInput - шаблон, який потрібно заповнити даними бронювання
+type previewInput struct {
+	models.Template
+	BookingID uuid.UUID `json:"booking_id"`
+}
+
+// List повертає шаблони користувача
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	templates, err := h.templateService.GetByUserID(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch templates",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"templates": templates,
+	})
+}
+
+// Fields повертає поля підстановки, доступні в шаблонах договорів
+func (h *Handler) Fields(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"fields": h.templateService.Fields(),
+	})
+}
+
+// EmailEvents повертає події листів, для яких можна створити шаблон, з полями підстановки
+func (h *Handler) EmailEvents(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"events":    h.templateService.EmailEvents(),
+		"languages": email.Languages,
+	})
+}
+
+// Create створює шаблон
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input models.Template
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input data",
+		})
+	}
+
+	input.ID = uuid.Nil
+	input.UserID = userID
+	if err := h.templateService.Create(c.Context(), &input); err != nil {
+		return templateError(c, err, "Failed to create template")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(input)
+}
+
+// Get повертає шаблон за ID
+func (h *Handler) Get(c *fiber.Ctx) error {
+	tmpl, err := h.ownedTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tmpl)
+}
+
+// Update оновлює шаблон
+func (h *Handler) Update(c *fiber.Ctx) error {
+	existing, err := h.ownedTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	var input models.Template
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input data",
+		})
+	}
+
+	input.ID = existing.ID
+	input.UserID = existing.UserID
+	input.CreatedAt = existing.CreatedAt
+	if err := h.templateService.Update(c.Context(), &input); err != nil {
+		return templateError(c, err, "Failed to update template")
+	}
+
+	return c.JSON(input)
+}
+
+// Delete видаляє шаблон
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	tmpl, err := h.ownedTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.templateService.Delete(c.Context(), tmpl.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete template",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Preview заповнює шаблон з тіла запиту даними бронювання booking_id, не зберігаючи його
+func (h *Handler) Preview(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input previewInput
+	if err := c.BodyParser(&input); err != nil || input.BookingID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Template and booking_id are required",
+		})
+	}
+
+	rendered, err := h.templateService.Preview(c.Context(), userID, &input.Template, input.BookingID)
+	if err != nil {
+		return templateError(c, err, "Failed to render template")
+	}
+
+	return c.JSON(rendered)
+}
+
+// Render заповнює збережений шаблон даними бронювання ?booking_id
+func (h *Handler) Render(c *fiber.Ctx) error {
+	tmpl, err := h.ownedTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	bookingID, err := uuid.Parse(c.Query("booking_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid booking ID",
+		})
+	}
+
+	rendered, err := h.templateService.Render(c.Context(), tmpl.UserID, tmpl.ID, bookingID)
+	if err != nil {
+		return templateError(c, err, "Failed to render template")
+	}
+
+	return c.JSON(rendered)
+}
+
+// ownedTemplate повертає шаблон з параметра :id, якщо він належить поточному користувачу
+func (h *Handler) ownedTemplate(c *fiber.Ctx) (*models.Template, error) {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid template ID")
+	}
+
+	tmpl, err := h.templateService.GetByID(c.Context(), id)
+	if err != nil || tmpl.UserID != userID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Template not found")
+	}
+	return tmpl, nil
+}
+
+// templateError перетворює помилку сервісу на відповідь; помилки шаблону повертаються з номерами рядків
+func templateError(c *fiber.Ctx, err error, message string) error {
+	var invalid *merge.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":    "Invalid template",
+			"problems": invalid.Problems,
+		})
+	case errors.Is(err, template.ErrInvalidVariable), errors.Is(err, template.ErrInvalidEmailEvent), errors.Is(err, template.ErrInvalidLanguage):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repositories.ErrBookingNotFound), errors.Is(err, repositories.ErrTemplateNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
@@ -121,3 +123,157 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// NotificationSettings повертає налаштування сповіщень поточного користувача
+func (h *Handler) NotificationSettings(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	settings, err := h.userService.GetSettings(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(notificationSettingsResponse(settings.NotificationSettings))
+}
+
+// UpdateNotificationSettings оновлює налаштування сповіщень поточного користувача
+func (h *Handler) UpdateNotificationSettings(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input models.UserNotificationSettings
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input",
+		})
+	}
+	if input.GalleryActivity != "" && !input.GalleryActivity.IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid notification frequency",
+		})
+	}
+	for _, days := range input.DeadlineReminders {
+		if days < 1 || days > models.MaxDeadlineReminderDays {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Deadline reminders must be between 1 and 60 days",
+			})
+		}
+	}
+	if input.Digest != "" && (!input.Digest.IsValid() || input.Digest == models.NotificationFrequencyInstant) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Digest frequency must be off, daily or weekly",
+		})
+	}
+	if input.DigestHour != nil && (*input.DigestHour < 0 || *input.DigestHour > 23) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Digest hour must be between 0 and 23",
+		})
+	}
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid time zone",
+			})
+		}
+	}
+	for _, chosen := range input.Channels {
+		for _, channel := range chosen {
+			if !channel.IsValid() {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid notification channel",
+				})
+			}
+		}
+	}
+
+	settings, err := h.userService.GetSettings(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	settings.NotificationSettings = input
+	if err := h.userService.UpdateSettings(c.Context(), userID, settings); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update notification settings",
+		})
+	}
+
+	return c.JSON(notificationSettingsResponse(settings.NotificationSettings))
+}
+
+// Requisites повертає реквізити студії для договорів
+func (h *Handler) Requisites(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	settings, err := h.userService.GetSettings(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(settings.Requisites)
+}
+
+// UpdateRequisites оновлює реквізити студії для договорів
+func (h *Handler) UpdateRequisites(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input models.StudioRequisites
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input",
+		})
+	}
+
+	settings, err := h.userService.GetSettings(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	settings.Requisites = input
+	if err := h.userService.UpdateSettings(c.Context(), userID, settings); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update requisites",
+		})
+	}
+
+	return c.JSON(settings.Requisites)
+}
+
+// notificationSettingsResponse повертає налаштування з урахуванням значень за замовчуванням
+func notificationSettingsResponse(settings models.UserNotificationSettings) fiber.Map {
+	return fiber.Map{
+		"gallery_activity":   settings.GalleryActivityFrequency(),
+		"channels":           settings.Channels,
+		"deadline_reminders": settings.DeadlineReminderDays(),
+		"digest":             settings.DigestFrequency(),
+		"digest_hour":        settings.DigestTime(),
+		"timezone":           settings.Location().String(),
+	}
+}
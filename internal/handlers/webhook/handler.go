@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"timebride/internal/repositories"
+	"timebride/internal/services/webhook"
+)
+
+// Handler обробляє запити підписок на вебхуки
+type Handler struct {
+	webhookService webhook.IWebhookService
+}
+
+// NewHandler створює новий обробник вебхуків
+func NewHandler(webhookService webhook.IWebhookService) *Handler {
+	return &Handler{
+		webhookService: webhookService,
+	}
+}
+
+// Events повертає події, на які можна підписатися
+func (h *Handler) Events(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"events": h.webhookService.Events(),
+	})
+}
+
+// List повертає підписки користувача
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	webhooks, err := h.webhookService.List(c.Context(), userID)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": webhooks,
+	})
+}
+
+// Create створює підписку; секрет для перевірки підпису повертається у відповіді
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input webhook.Input
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input",
+		})
+	}
+
+	created, err := h.webhookService.Create(c.Context(), userID, &input)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// Update змінює URL, події або стан підписки
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, webhookID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	var input webhook.Input
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid input",
+		})
+	}
+
+	updated, err := h.webhookService.Update(c.Context(), userID, webhookID, &input)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(updated)
+}
+
+// Delete видаляє підписку разом із журналом доставки
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, webhookID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.webhookService.Delete(c.Context(), userID, webhookID); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Deliveries повертає журнал доставки підписки
+func (h *Handler) Deliveries(c *fiber.Ctx) error {
+	userID, webhookID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.webhookService.Deliveries(c.Context(), userID, webhookID)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"deliveries": deliveries,
+	})
+}
+
+// Ping надсилає тестовий запит і повертає результат доставки
+func (h *Handler) Ping(c *fiber.Ctx) error {
+	userID, webhookID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := h.webhookService.Ping(c.Context(), userID, webhookID)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(delivery)
+}
+
+// Replay повторно надсилає запит із журналу доставки
+func (h *Handler) Replay(c *fiber.Ctx) error {
+	userID, deliveryID, err := ownerParams(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := h.webhookService.Replay(c.Context(), userID, deliveryID)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(delivery)
+}
+
+// ownerParams повертає ID користувача та ID підписки або доставки з шляху запиту
+func ownerParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	userID, err := uuid.Parse(c.Locals("user_id").(string))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	return userID, id, nil
+}
+
+// webhookError перетворює помилку сервісу на HTTP-відповідь
+func webhookError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidURL),
+		errors.Is(err, webhook.ErrForbiddenHost),
+		errors.Is(err, webhook.ErrInvalidEvents):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repositories.ErrWebhookNotFound),
+		errors.Is(err, repositories.ErrWebhookDeliveryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process webhook",
+		})
+	}
+}
@@ -0,0 +1,432 @@
+// Package jobs - черга фонових задач у Postgres. Задачі беруться обробниками через
+// SELECT ... FOR UPDATE SKIP LOCKED, тож кілька екземплярів застосунку не виконують одну задачу двічі.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+const (
+	// defaultConcurrency - кількість задач, що виконуються одночасно
+	defaultConcurrency = 4
+	// pollInterval - як часто черга перевіряє нові задачі, коли вільні обробники
+	pollInterval = time.Second
+	// scheduleInterval - як часто перевіряється, що кожна повторювана задача стоїть у черзі
+	scheduleInterval = 30 * time.Second
+	// defaultTimeout - максимальна тривалість задачі, якщо тип не задав власну
+	defaultTimeout = 5 * time.Minute
+	// leaseGrace - запас понад тайм-аут, після якого задачу вважають покинутою і беруть знову
+	leaseGrace = time.Minute
+	// defaultMaxAttempts - кількість спроб, якщо ні тип, ні задача не задали власну
+	defaultMaxAttempts = 5
+	// retryBase, retryMax - пауза перед повтором подвоюється від retryBase до retryMax
+	retryBase = 10 * time.Second
+	retryMax  = time.Hour
+	// listLimit - кількість задач у списку для адміністратора
+	listLimit = 100
+)
+
+var (
+	ErrUnknownType = errors.New("unknown job type")
+	ErrNotFailed   = errors.New("only failed jobs can be retried")
+)
+
+// Handler виконує задачу. Помилка повторює задачу пізніше, помилка Permanent - позначає її невдалою одразу.
+type Handler func(ctx context.Context, job *models.Job) error
+
+// Options - налаштування типу задач
+type Options struct {
+	// MaxAttempts - кількість спроб за замовчуванням для задач цього типу
+	MaxAttempts int
+	// Timeout - максимальна тривалість однієї спроби
+	Timeout time.Duration
+}
+
+// Request - задача, яку потрібно поставити в чергу
+type Request struct {
+	Type    string
+	Payload interface{}
+	// Delay або RunAt відкладають виконання; без них задача виконується якнайшвидше
+	Delay time.Duration
+	RunAt time.Time
+	// UniqueKey - не додавати задачу, якщо задача з таким ключем уже очікує або виконується
+	UniqueKey string
+	// MaxAttempts перевизначає кількість спроб типу задачі
+	MaxAttempts int
+}
+
+// IJobQueue визначає інтерфейс черги фонових задач для сервісів та обробників запитів
+type IJobQueue interface {
+	// Enqueue ставить задачу в чергу; false - задача з таким UniqueKey уже є
+	Enqueue(ctx context.Context, req Request) (bool, error)
+	// List повертає останні задачі з указаним станом
+	List(ctx context.Context, status models.JobStatus) ([]*models.Job, error)
+	// Retry повертає невдалу задачу в чергу
+	Retry(ctx context.Context, id uuid.UUID) error
+}
+
+type registration struct {
+	handler Handler
+	options Options
+}
+
+type recurring struct {
+	jobType  string
+	schedule Schedule
+}
+
+// Queue - черга задач з обробниками в межах процесу
+type Queue struct {
+	repo        repositories.JobRepository
+	concurrency int
+
+	mu        sync.RWMutex
+	handlers  map[string]registration
+	schedules []recurring
+
+	// jobCtx скасовується, лише якщо задачі не завершилися до кінця graceful shutdown
+	jobCtx     context.Context
+	cancelJobs context.CancelFunc
+	stop       chan struct{}
+	stopOnce   sync.Once
+	running    sync.WaitGroup
+}
+
+// NewQueue створює чергу задач
+func NewQueue(repo repositories.JobRepository) *Queue {
+	jobCtx, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		repo:        repo,
+		concurrency: defaultConcurrency,
+		handlers:    make(map[string]registration),
+		jobCtx:      jobCtx,
+		cancelJobs:  cancel,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Register реєструє обробник типу задач; черга бере лише задачі зареєстрованих типів
+func (q *Queue) Register(jobType string, options Options, handler Handler) {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.handlers[jobType]; ok {
+		panic(fmt.Sprintf("jobs: handler for %q is already registered", jobType))
+	}
+	q.handlers[jobType] = registration{handler: handler, options: options}
+}
+
+// Every реєструє повторювану задачу: обробник виконується за розкладом spec (див. ParseSchedule).
+// У черзі одночасно стоїть лише один запуск задачі; невдалий запуск не повторюється - його заміняє наступний.
+func (q *Queue) Every(jobType, spec string, timeout time.Duration, handler Handler) {
+	q.Register(jobType, Options{MaxAttempts: 1, Timeout: timeout}, handler)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.schedules = append(q.schedules, recurring{jobType: jobType, schedule: MustParseSchedule(spec)})
+}
+
+// Enqueue ставить задачу в чергу
+func (q *Queue) Enqueue(ctx context.Context, req Request) (bool, error) {
+	q.mu.RLock()
+	reg, ok := q.handlers[req.Type]
+	q.mu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnknownType, req.Type)
+	}
+
+	job := &models.Job{
+		Type:        req.Type,
+		Status:      models.JobStatusPending,
+		MaxAttempts: reg.options.MaxAttempts,
+		RunAt:       time.Now().Add(req.Delay),
+	}
+	if !req.RunAt.IsZero() {
+		job.RunAt = req.RunAt
+	}
+	if req.MaxAttempts > 0 {
+		job.MaxAttempts = req.MaxAttempts
+	}
+	if req.UniqueKey != "" {
+		job.UniqueKey = &req.UniqueKey
+	}
+	if req.Payload != nil {
+		payload, err := json.Marshal(req.Payload)
+		if err != nil {
+			return false, fmt.Errorf("failed to encode %s job payload: %w", req.Type, err)
+		}
+		job.Payload = payload
+	}
+	return q.repo.Enqueue(ctx, job)
+}
+
+// List повертає останні задачі з указаним станом
+func (q *Queue) List(ctx context.Context, status models.JobStatus) ([]*models.Job, error) {
+	return q.repo.ListByStatus(ctx, status, listLimit)
+}
+
+// Retry повертає невдалу задачу в чергу з новими спробами
+func (q *Queue) Retry(ctx context.Context, id uuid.UUID) error {
+	job, err := q.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status != models.JobStatusFailed {
+		return ErrNotFailed
+	}
+	return q.repo.Requeue(ctx, id, time.Now())
+}
+
+// Start запускає обробку задач та розклад повторюваних задач до скасування ctx або Shutdown
+func (q *Queue) Start(ctx context.Context) {
+	go q.poll(ctx)
+	go q.schedule(ctx)
+}
+
+// Shutdown припиняє брати нові задачі та чекає на завершення поточних. Якщо ctx спливає раніше,
+// контекст задач скасовується; незавершені задачі інший обробник візьме після закінчення їхньої оренди.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.stopOnce.Do(func() { close(q.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.cancelJobs()
+		return ctx.Err()
+	}
+}
+
+// poll бере задачі, коли є вільні обробники, і запускає їх
+func (q *Queue) poll(ctx context.Context) {
+	slots := make(chan struct{}, q.concurrency)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if free := q.concurrency - len(slots); free > 0 {
+			jobs, err := q.repo.Claim(ctx, q.types(), time.Now(), q.lease(), free)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to claim jobs: %v", err)
+			}
+			for _, job := range jobs {
+				slots <- struct{}{}
+				q.running.Add(1)
+				go func(job *models.Job) {
+					defer func() {
+						<-slots
+						q.running.Done()
+					}()
+					q.run(job)
+				}(job)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run виконує задачу і записує результат
+func (q *Queue) run(job *models.Job) {
+	q.mu.RLock()
+	reg, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	var err error
+	if ok {
+		ctx, cancel := context.WithTimeout(q.jobCtx, reg.options.Timeout)
+		err = q.call(ctx, reg.handler, job)
+		cancel()
+	} else {
+		err = Permanent(fmt.Errorf("%w: %s", ErrUnknownType, job.Type))
+	}
+
+	now := time.Now()
+	lockedUntil := *job.LockedUntil
+	job.LockedUntil = nil
+	switch {
+	case err == nil:
+		job.Status = models.JobStatusDone
+		job.FinishedAt = &now
+		job.LastError = ""
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		job.Status = models.JobStatusFailed
+		job.FinishedAt = &now
+		job.LastError = err.Error()
+		log.Printf("Job %s %s failed: %v", job.Type, job.ID, err)
+	default:
+		job.Status = models.JobStatusPending
+		job.RunAt = now.Add(backoff(job.Attempts))
+		job.LastError = err.Error()
+		log.Printf("Job %s %s failed (attempt %d), retrying at %s: %v",
+			job.Type, job.ID, job.Attempts, job.RunAt.Format(time.RFC3339), err)
+	}
+
+	// Результат записується і під час shutdown, коли контекст задач уже скасовано
+	if err := q.repo.Update(context.Background(), job, lockedUntil); err != nil {
+		if errors.Is(err, repositories.ErrJobLeaseExpired) {
+			// Задачу вже виконує інший обробник - його результат і запише
+			log.Printf("Job %s %s outlived its lease, result discarded", job.Type, job.ID)
+			return
+		}
+		log.Printf("Failed to update job %s: %v", job.ID, err)
+	}
+	if job.Status != models.JobStatusPending {
+		q.scheduleNext(context.Background(), job.Type)
+	}
+}
+
+// call викликає обробник; паніка обробника позначає задачу невдалою без зупинки черги
+func (q *Queue) call(ctx context.Context, handler Handler, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Permanent(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// schedule періодично перевіряє, що кожна повторювана задача стоїть у черзі
+func (q *Queue) schedule(ctx context.Context) {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		q.mu.RLock()
+		schedules := q.schedules
+		q.mu.RUnlock()
+		for _, s := range schedules {
+			q.enqueueRecurring(ctx, s)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scheduleNext одразу ставить наступний запуск повторюваної задачі, щойно завершився попередній
+func (q *Queue) scheduleNext(ctx context.Context, jobType string) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	for _, s := range q.schedules {
+		if s.jobType == jobType {
+			q.enqueueRecurring(ctx, s)
+			return
+		}
+	}
+}
+
+// enqueueRecurring ставить наступний запуск; ключ унікальності не дає поставити другий
+func (q *Queue) enqueueRecurring(ctx context.Context, s recurring) {
+	_, err := q.Enqueue(ctx, Request{
+		Type:      s.jobType,
+		RunAt:     s.schedule.Next(time.Now()),
+		UniqueKey: "schedule:" + s.jobType,
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Failed to schedule job %s: %v", s.jobType, err)
+	}
+}
+
+// types повертає зареєстровані типи задач
+func (q *Queue) types() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+// lease повертає оренду задачі: найбільший тайм-аут серед типів із запасом
+func (q *Queue) lease() time.Duration {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	lease := defaultTimeout
+	for _, reg := range q.handlers {
+		if reg.options.Timeout > lease {
+			lease = reg.options.Timeout
+		}
+	}
+	return lease + leaseGrace
+}
+
+// backoff повертає паузу перед наступною спробою: 10с, 20с, 40с ... але не більше години
+func backoff(attempts int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	if delay > retryMax {
+		delay = retryMax
+	}
+	return delay
+}
+
+// Decode розбирає payload задачі у v
+func Decode(job *models.Job, v interface{}) error {
+	if len(job.Payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(job.Payload, v); err != nil {
+		return Permanent(fmt.Errorf("failed to decode %s job payload: %w", job.Type, err))
+	}
+	return nil
+}
+
+// permanentError - помилка, після якої задачу не повторюють
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent позначає помилку задачі як таку, що не підлягає повтору
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent перевіряє, чи помилка не підлягає повтору
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"@every 90s", "2026-10-19 10:00:00", "2026-10-19 10:01:30"},
+		{"*/15 * * * *", "2026-10-19 10:07:30", "2026-10-19 10:15:00"},
+		// Наступний запуск - строго після t, навіть якщо t збігається з розкладом
+		{"@hourly", "2026-10-19 10:00:00", "2026-10-19 11:00:00"},
+		{"@daily", "2026-01-31 23:59:00", "2026-02-01 00:00:00"},
+		{"0 9 * * 1-5", "2026-10-16 10:00:00", "2026-10-19 09:00:00"},
+		{"0 0 * * 7", "2026-10-19 00:00:00", "2026-10-25 00:00:00"},
+		{"0 0 * * 0", "2026-10-19 00:00:00", "2026-10-25 00:00:00"},
+		{"0 12 1,15 * *", "2026-10-15 12:00:00", "2026-11-01 12:00:00"},
+		{"5 4 * 2 *", "2026-10-19 00:00:00", "2027-02-01 04:05:00"},
+		{"0 8-18/5 * * *", "2026-10-19 13:30:00", "2026-10-19 18:00:00"},
+		{"30 */6 * * *", "2026-10-19 18:30:00", "2026-10-20 00:30:00"},
+		// День місяця та день тижня обмежені обидва - достатньо збігу одного з них
+		{"0 0 13 * 5", "2026-10-10 00:00:00", "2026-10-13 00:00:00"},
+		{"0 0 13 * 5", "2026-10-13 00:00:00", "2026-10-16 00:00:00"},
+		{"0 0 29 2 *", "2026-03-01 00:00:00", "2028-02-29 00:00:00"},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := schedule.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.spec, tt.from, got.Format(time.DateTime), tt.want)
+		}
+	}
+}
+
+func TestScheduleNextNeverMatches(t *testing.T) {
+	schedule := MustParseSchedule("0 0 30 2 *")
+	from := at("2026-10-19 00:00:00")
+	if got := schedule.Next(from); got.Sub(from) < maxSearch {
+		t.Fatalf("Next = %s, want the search horizon", got.Format(time.DateTime))
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1-x * * * *",
+		"a * * * *",
+		"@every 500ms",
+		"@every soon",
+		"@weekly",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package mailer надсилає листи клієнтам та користувачам
+package mailer
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// Attachment - вкладення листа
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Message - лист з текстовим тілом та вкладеннями
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+	// HTML - необов'язкова HTML-версія тіла; текстова версія залишається для поштових клієнтів без HTML
+	HTML        string
+	Attachments []Attachment
+}
+
+// Mailer надсилає листи
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NoopMailer лише записує лист у журнал; використовується, коли SMTP не налаштований
+type NoopMailer struct{}
+
+// NewNoopMailer створює поштовий сервіс, що не надсилає листів
+func NewNoopMailer() NoopMailer {
+	return NoopMailer{}
+}
+
+// Send записує адресатів і тему листа в журнал
+func (NoopMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("SMTP is not configured, email %q to %s was not sent", msg.Subject, strings.Join(msg.To, ", "))
+	return nil
+}
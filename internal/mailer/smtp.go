@@ -0,0 +1,156 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoRecipients = errors.New("email has no recipients")
+
+// IsPermanent перевіряє, чи помилка надсилання остаточна: лист без адресатів
+// або відмова сервера з кодом 5xx (неіснуюча адреса тощо). Повторні спроби таких листів не мають сенсу.
+func IsPermanent(err error) bool {
+	var reply *textproto.Error
+	return errors.Is(err, ErrNoRecipients) || errors.As(err, &reply) && reply.Code >= 500
+}
+
+// SMTPMailer надсилає листи через SMTP-сервер з автентифікацією PLAIN
+type SMTPMailer struct {
+	addr    string
+	host    string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+// NewSMTPMailer створює поштовий сервіс; автентифікація вимикається, якщо ім'я користувача порожнє
+func NewSMTPMailer(host string, port int, username, password, from string, timeout time.Duration) *SMTPMailer {
+	m := &SMTPMailer{
+		addr:    net.JoinHostPort(host, strconv.Itoa(port)),
+		host:    host,
+		from:    from,
+		timeout: timeout,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+// Send надсилає лист; з'єднання обмежене тайм-аутом та контекстом
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	body, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.addr, m.auth, m.from, msg.To, body)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email: %w", ctx.Err())
+	}
+}
+
+// build формує MIME-повідомлення: текстова частина (з HTML-альтернативою, якщо вона є) та вкладення в base64
+func (m *SMTPMailer) build(msg *Message) ([]byte, error) {
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", m.from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	if msg.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "base64")
+		buf.WriteString("\r\n")
+		writeBase64(&buf, []byte(msg.Body))
+	} else {
+		alternative, err := newBoundary()
+		if err != nil {
+			return nil, err
+		}
+		header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", alternative))
+		buf.WriteString("\r\n")
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain; charset=utf-8", msg.Body},
+			{"text/html; charset=utf-8", msg.HTML},
+		} {
+			fmt.Fprintf(&buf, "--%s\r\n", alternative)
+			header("Content-Type", part.contentType)
+			header("Content-Transfer-Encoding", "base64")
+			buf.WriteString("\r\n")
+			writeBase64(&buf, []byte(part.body))
+		}
+		fmt.Fprintf(&buf, "--%s--\r\n", alternative)
+	}
+
+	for _, a := range msg.Attachments {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		header("Content-Type", a.ContentType)
+		header("Content-Transfer-Encoding", "base64")
+		header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}))
+		buf.WriteString("\r\n")
+		writeBase64(&buf, a.Data)
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64 записує дані в base64 рядками по 76 символів
+func writeBase64(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate mime boundary: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
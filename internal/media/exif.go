@@ -0,0 +1,342 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxExifSize - максимальний розмір сегмента APP1 (обмежений форматом JPEG)
+const maxExifSize = 64 * 1024
+
+var errInvalidExif = errors.New("invalid exif data")
+
+// Теги EXIF, які нас цікавлять
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagPixelXDimension    = 0xA002
+	tagPixelYDimension    = 0xA003
+	tagLensModel          = 0xA434
+
+	tagGPSLatitudeRef  = 0x0001
+	tagGPSLatitude     = 0x0002
+	tagGPSLongitudeRef = 0x0003
+	tagGPSLongitude    = 0x0004
+)
+
+// Типи значень TIFF
+const (
+	tiffByte      = 1
+	tiffASCII     = 2
+	tiffShort     = 3
+	tiffLong      = 4
+	tiffRational  = 5
+	tiffUndefined = 7
+	tiffSLong     = 9
+	tiffSRational = 10
+)
+
+var tiffTypeSize = map[uint16]uint32{
+	tiffByte: 1, tiffASCII: 1, tiffShort: 2, tiffLong: 4,
+	tiffRational: 8, tiffUndefined: 1, tiffSLong: 4, tiffSRational: 8,
+}
+
+// readJPEGExif знаходить сегмент APP1 з EXIF у JPEG та розбирає його.
+// Файли без EXIF або з пошкодженим EXIF не вважаються помилкою.
+func readJPEGExif(r io.ReadSeeker, meta *Metadata) error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	br := bufio.NewReader(r)
+
+	var soi [2]byte
+	if _, err := io.ReadFull(br, soi[:]); err != nil || soi[0] != 0xFF || soi[1] != 0xD8 {
+		return nil
+	}
+
+	for {
+		marker, err := nextMarker(br)
+		if err != nil {
+			return nil
+		}
+		// Початок даних зображення (SOS) або кінець файлу (EOI) - EXIF вже не буде
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+		// Маркери без довжини
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			continue
+		}
+
+		var lenBuf [2]byte
+		if _, err := io.ReadFull(br, lenBuf[:]); err != nil {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(lenBuf[:])) - 2
+		if length < 0 {
+			return nil
+		}
+
+		if marker != 0xE1 {
+			if _, err := br.Discard(length); err != nil {
+				return nil
+			}
+			continue
+		}
+
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(br, segment); err != nil {
+			return nil
+		}
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			_ = parseTIFF(segment[6:], meta)
+			return nil
+		}
+	}
+}
+
+// nextMarker пропускає байти заповнення та повертає код наступного маркера
+func nextMarker(br *bufio.Reader) (byte, error) {
+	b, err := br.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b != 0xFF {
+		return 0, errInvalidExif
+	}
+	for b == 0xFF {
+		if b, err = br.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+	return b, nil
+}
+
+// tiffReader читає структури TIFF з буфера EXIF
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// parseTIFF розбирає заголовок TIFF та потрібні IFD
+func parseTIFF(data []byte, meta *Metadata) error {
+	if len(data) < 8 {
+		return errInvalidExif
+	}
+	t := &tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return errInvalidExif
+	}
+	if t.order.Uint16(data[2:4]) != 42 {
+		return errInvalidExif
+	}
+
+	ifd0, err := t.readIFD(t.order.Uint32(data[4:8]))
+	if err != nil {
+		return err
+	}
+
+	var dateTime, dateTimeOriginal, offsetTime string
+	for _, e := range ifd0 {
+		switch e.tag {
+		case tagMake:
+			meta.CameraMake = t.ascii(e)
+		case tagModel:
+			meta.CameraModel = t.ascii(e)
+		case tagOrientation:
+			meta.ExifOrientation = int(t.uint(e))
+		case tagDateTime:
+			dateTime = t.ascii(e)
+		case tagExifIFD:
+			exifIFD, err := t.readIFD(t.uint(e))
+			if err != nil {
+				continue
+			}
+			for _, x := range exifIFD {
+				switch x.tag {
+				case tagDateTimeOriginal:
+					dateTimeOriginal = t.ascii(x)
+				case tagOffsetTimeOriginal:
+					offsetTime = t.ascii(x)
+				case tagPixelXDimension:
+					meta.Width = int(t.uint(x))
+				case tagPixelYDimension:
+					meta.Height = int(t.uint(x))
+				case tagLensModel:
+					meta.Lens = t.ascii(x)
+				}
+			}
+		case tagGPSIFD:
+			gpsIFD, err := t.readIFD(t.uint(e))
+			if err == nil {
+				meta.GPS = t.gps(gpsIFD)
+			}
+		}
+	}
+
+	if dateTimeOriginal == "" {
+		dateTimeOriginal = dateTime
+	}
+	if takenAt, ok := parseExifTime(dateTimeOriginal, offsetTime); ok {
+		meta.TakenAt = &takenAt
+	}
+
+	meta.CameraMake = strings.TrimSpace(meta.CameraMake)
+	meta.CameraModel = strings.TrimSpace(meta.CameraModel)
+	return nil
+}
+
+// readIFD читає записи каталогу за зміщенням
+func (t *tiffReader) readIFD(offset uint32) ([]ifdEntry, error) {
+	if int(offset)+2 > len(t.data) {
+		return nil, errInvalidExif
+	}
+	count := int(t.order.Uint16(t.data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(t.data) {
+		return nil, errInvalidExif
+	}
+
+	entries := make([]ifdEntry, 0, count)
+	for i := 0; i < count; i++ {
+		raw := t.data[start+i*12 : start+(i+1)*12]
+		e := ifdEntry{
+			tag:   t.order.Uint16(raw[0:2]),
+			typ:   t.order.Uint16(raw[2:4]),
+			count: t.order.Uint32(raw[4:8]),
+		}
+
+		size, ok := tiffTypeSize[e.typ]
+		if !ok || e.count > maxExifSize {
+			continue
+		}
+		total := size * e.count
+		if total <= 4 {
+			e.value = raw[8 : 8+total]
+		} else {
+			valueOffset := t.order.Uint32(raw[8:12])
+			if uint64(valueOffset)+uint64(total) > uint64(len(t.data)) {
+				continue
+			}
+			e.value = t.data[valueOffset : valueOffset+total]
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (t *tiffReader) ascii(e ifdEntry) string {
+	if e.typ != tiffASCII && e.typ != tiffUndefined {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+}
+
+func (t *tiffReader) uint(e ifdEntry) uint32 {
+	switch e.typ {
+	case tiffShort:
+		if len(e.value) >= 2 {
+			return uint32(t.order.Uint16(e.value))
+		}
+	case tiffLong, tiffSLong:
+		if len(e.value) >= 4 {
+			return t.order.Uint32(e.value)
+		}
+	case tiffByte:
+		if len(e.value) >= 1 {
+			return uint32(e.value[0])
+		}
+	}
+	return 0
+}
+
+// rationals повертає значення типу RATIONAL як числа з плаваючою крапкою
+func (t *tiffReader) rationals(e ifdEntry) []float64 {
+	if e.typ != tiffRational {
+		return nil
+	}
+	result := make([]float64, 0, e.count)
+	for i := 0; i+8 <= len(e.value); i += 8 {
+		num := t.order.Uint32(e.value[i:])
+		den := t.order.Uint32(e.value[i+4:])
+		if den == 0 {
+			return nil
+		}
+		result = append(result, float64(num)/float64(den))
+	}
+	return result
+}
+
+// gps перетворює координати з градусів, хвилин і секунд у десяткові градуси
+func (t *tiffReader) gps(entries []ifdEntry) *GPS {
+	var latRef, lonRef string
+	var lat, lon []float64
+	for _, e := range entries {
+		switch e.tag {
+		case tagGPSLatitudeRef:
+			latRef = t.ascii(e)
+		case tagGPSLatitude:
+			lat = t.rationals(e)
+		case tagGPSLongitudeRef:
+			lonRef = t.ascii(e)
+		case tagGPSLongitude:
+			lon = t.rationals(e)
+		}
+	}
+	if len(lat) != 3 || len(lon) != 3 {
+		return nil
+	}
+
+	gps := &GPS{
+		Latitude:  lat[0] + lat[1]/60 + lat[2]/3600,
+		Longitude: lon[0] + lon[1]/60 + lon[2]/3600,
+	}
+	if latRef == "S" {
+		gps.Latitude = -gps.Latitude
+	}
+	if lonRef == "W" {
+		gps.Longitude = -gps.Longitude
+	}
+	return gps
+}
+
+// parseExifTime розбирає дату EXIF ("2006:01:02 15:04:05") з необов'язковим зсувом.
+// Без зсуву час трактується як UTC, щоб знімки з різних камер сортувалися за "настінним" часом.
+func parseExifTime(value, offset string) (time.Time, bool) {
+	if value == "" || strings.HasPrefix(value, "0000") {
+		return time.Time{}, false
+	}
+	if offset != "" {
+		if t, err := time.Parse("2006:01:02 15:04:05-07:00", value+offset); err == nil {
+			return t, true
+		}
+	}
+	t, err := time.Parse("2006:01:02 15:04:05", value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
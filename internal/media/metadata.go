@@ -0,0 +1,110 @@
+// Package media містить розбір метаданих фото та відео без зовнішніх залежностей
+package media
+
+import (
+	"image"
+	_ "image/gif"  // реєстрація декодера для image.DecodeConfig
+	_ "image/jpeg" // реєстрація декодера для image.DecodeConfig
+	_ "image/png"  // реєстрація декодера для image.DecodeConfig
+	"io"
+	"strings"
+	"time"
+)
+
+// Орієнтація кадру з урахуванням повороту EXIF
+const (
+	OrientationLandscape = "landscape"
+	OrientationPortrait  = "portrait"
+	OrientationSquare    = "square"
+)
+
+// Metadata містить метадані медіафайлу
+type Metadata struct {
+	TakenAt     *time.Time `json:"taken_at,omitempty"`
+	CameraMake  string     `json:"camera_make,omitempty"`
+	CameraModel string     `json:"camera_model,omitempty"`
+	Lens        string     `json:"lens,omitempty"`
+	Width       int        `json:"width,omitempty"`
+	Height      int        `json:"height,omitempty"`
+	// Orientation - landscape, portrait або square
+	Orientation string `json:"orientation,omitempty"`
+	// ExifOrientation - значення тегу Orientation (1-8)
+	ExifOrientation int      `json:"exif_orientation,omitempty"`
+	GPS             *GPS     `json:"gps,omitempty"`
+	Duration        *float64 `json:"duration,omitempty"` // тривалість відео в секундах
+}
+
+// GPS містить координати зйомки
+type GPS struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// IsEmpty перевіряє, чи вдалося отримати хоч якісь метадані
+func (m *Metadata) IsEmpty() bool {
+	return m.TakenAt == nil && m.CameraModel == "" && m.CameraMake == "" &&
+		m.Width == 0 && m.Height == 0 && m.GPS == nil && m.Duration == nil
+}
+
+// Extract читає метадані з файлу відповідного MIME-типу.
+// Для непідтримуваних форматів повертає порожні метадані без помилки.
+func Extract(r io.ReadSeeker, mimeType string) (*Metadata, error) {
+	meta := &Metadata{}
+
+	switch {
+	case mimeType == "image/jpeg":
+		if err := readJPEGExif(r, meta); err != nil {
+			return nil, err
+		}
+		if err := readImageConfig(r, meta); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(mimeType, "image/"):
+		if err := readImageConfig(r, meta); err != nil {
+			return nil, err
+		}
+	case mimeType == "video/mp4" || mimeType == "video/quicktime":
+		if err := readMP4(r, meta); err != nil {
+			return nil, err
+		}
+	default:
+		return meta, nil
+	}
+
+	meta.Orientation = orientation(meta.Width, meta.Height, meta.ExifOrientation)
+	return meta, nil
+}
+
+// readImageConfig визначає розміри зображення за заголовком
+func readImageConfig(r io.ReadSeeker, meta *Metadata) error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		// Формат без зареєстрованого декодера (наприклад, WebP) - розміри невідомі
+		return nil
+	}
+	// Розміри з EXIF можуть відрізнятися від реальних після редагування
+	meta.Width = cfg.Width
+	meta.Height = cfg.Height
+	return nil
+}
+
+// orientation визначає орієнтацію кадру; значення EXIF 5-8 означають поворот на 90°
+func orientation(width, height, exifOrientation int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	if exifOrientation >= 5 && exifOrientation <= 8 {
+		width, height = height, width
+	}
+	switch {
+	case width > height:
+		return OrientationLandscape
+	case height > width:
+		return OrientationPortrait
+	default:
+		return OrientationSquare
+	}
+}
@@ -0,0 +1,160 @@
+package media
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// maxBoxDepth обмежує вкладеність атомів, щоб пошкоджений файл не спричинив глибоку рекурсію
+const maxBoxDepth = 8
+
+// mp4Epoch - початок відліку часу в контейнерах MP4/MOV
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// readMP4 розбирає атоми MP4/MOV: тривалість та дату створення з mvhd,
+// роздільну здатність та поворот відеодоріжки з tkhd
+func readMP4(r io.ReadSeeker, meta *Metadata) error {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	return walkBoxes(r, 0, end, 0, meta)
+}
+
+// walkBoxes обходить атоми в діапазоні [start, end)
+func walkBoxes(r io.ReadSeeker, start, end int64, depth int, meta *Metadata) error {
+	if depth > maxBoxDepth {
+		return nil
+	}
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil
+		}
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			// Атом триває до кінця файлу
+			size = end - offset
+		case 1:
+			var large [8]byte
+			if _, err := io.ReadFull(r, large[:]); err != nil {
+				return nil
+			}
+			size = int64(binary.BigEndian.Uint64(large[:]))
+			headerSize = 16
+		}
+		if size < headerSize || offset+size > end {
+			return nil
+		}
+
+		payloadStart := offset + headerSize
+		payloadEnd := offset + size
+
+		switch boxType {
+		case "moov", "trak":
+			if err := walkBoxes(r, payloadStart, payloadEnd, depth+1, meta); err != nil {
+				return err
+			}
+		case "mvhd":
+			readMvhd(r, payloadEnd-payloadStart, meta)
+		case "tkhd":
+			readTkhd(r, payloadEnd-payloadStart, meta)
+		}
+
+		offset += size
+	}
+	return nil
+}
+
+// readBoxPayload читає вміст атома обмеженого розміру
+func readBoxPayload(r io.Reader, size int64, limit int64) []byte {
+	if size <= 0 || size > limit {
+		return nil
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil
+	}
+	return buf
+}
+
+// readMvhd читає заголовок фільму: дату створення та тривалість
+func readMvhd(r io.Reader, size int64, meta *Metadata) {
+	buf := readBoxPayload(r, size, 512)
+	if len(buf) < 4 {
+		return
+	}
+
+	var created, timescale, duration uint64
+	if buf[0] == 1 {
+		if len(buf) < 36 {
+			return
+		}
+		created = binary.BigEndian.Uint64(buf[4:12])
+		timescale = uint64(binary.BigEndian.Uint32(buf[20:24]))
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	} else {
+		if len(buf) < 24 {
+			return
+		}
+		created = uint64(binary.BigEndian.Uint32(buf[4:8]))
+		timescale = uint64(binary.BigEndian.Uint32(buf[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	}
+
+	if timescale > 0 && duration > 0 {
+		seconds := float64(duration) / float64(timescale)
+		meta.Duration = &seconds
+	}
+	if created > 0 && meta.TakenAt == nil {
+		takenAt := mp4Epoch.Add(time.Duration(created) * time.Second)
+		meta.TakenAt = &takenAt
+	}
+}
+
+// readTkhd читає заголовок доріжки; розміри беруться з першої доріжки з ненульовою шириною (відео)
+func readTkhd(r io.Reader, size int64, meta *Metadata) {
+	if meta.Width > 0 {
+		return
+	}
+
+	buf := readBoxPayload(r, size, 512)
+	if len(buf) < 4 {
+		return
+	}
+
+	matrixOffset := 40
+	if buf[0] == 1 {
+		matrixOffset = 52
+	}
+	if len(buf) < matrixOffset+44 {
+		return
+	}
+
+	width := int(binary.BigEndian.Uint32(buf[matrixOffset+36:]) >> 16)
+	height := int(binary.BigEndian.Uint32(buf[matrixOffset+40:]) >> 16)
+	if width == 0 || height == 0 {
+		return
+	}
+	meta.Width = width
+	meta.Height = height
+
+	// Телефони записують вертикальне відео як горизонтальне з матрицею повороту
+	a := int32(binary.BigEndian.Uint32(buf[matrixOffset:]))
+	b := int32(binary.BigEndian.Uint32(buf[matrixOffset+4:]))
+	if a == 0 && b > 0 {
+		meta.ExifOrientation = 6 // поворот на 90°
+	} else if a == 0 && b < 0 {
+		meta.ExifOrientation = 8 // поворот на 270°
+	}
+}
@@ -0,0 +1,124 @@
+package media
+
+import (
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+)
+
+// Thumbnail зменшує зображення так, щоб більша сторона не перевищувала maxSize,
+// та повертає його з урахуванням орієнтації EXIF. Менші зображення не збільшуються.
+// Зображення понад MaxImagePixels не декодуються.
+func Thumbnail(r io.Reader, maxSize, exifOrientation int) (image.Image, error) {
+	src, err := DecodeImage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+
+	return orient(resize(src, width, height), exifOrientation), nil
+}
+
+// EncodeJPEG кодує зображення в JPEG із заданою якістю
+func EncodeJPEG(w io.Writer, img image.Image, quality int) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// resize зменшує зображення усередненням пікселів (box filter), що дає
+// якісний результат при значному зменшенні без зовнішніх бібліотек
+func resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		// draw.Draw має швидкі шляхи для YCbCr (JPEG) та інших поширених форматів
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+
+	srcW, srcH := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+	if srcW == width && srcH == height {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := max(y0+1, (y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := max(x0+1, (x+1)*srcW/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*rgba.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[offset])
+					g += uint64(rgba.Pix[offset+1])
+					b += uint64(rgba.Pix[offset+2])
+					a += uint64(rgba.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// orient повертає або віддзеркалює зображення відповідно до тегу Orientation (1-8)
+func orient(src *image.RGBA, exifOrientation int) *image.RGBA {
+	if exifOrientation < 2 || exifOrientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := w, h
+	if exifOrientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch exifOrientation {
+			case 2: // віддзеркалення по горизонталі
+				dx, dy = w-1-x, y
+			case 3: // поворот на 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // віддзеркалення по вертикалі
+				dx, dy = x, h-1-y
+			case 5: // транспонування
+				dx, dy = y, x
+			case 6: // поворот на 90° за годинниковою стрілкою
+				dx, dy = h-1-y, x
+			case 7: // транспонування з поворотом
+				dx, dy = h-1-y, w-1-x
+			case 8: // поворот на 90° проти годинникової стрілки
+				dx, dy = y, w-1-x
+			}
+			si := y*src.Stride + x*4
+			di := dy*dst.Stride + dx*4
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,47 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngWithSize кодує маленьке PNG і підміняє розміри в заголовку IHDR
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// Сигнатура (8) + довжина (4) + "IHDR" (4), далі ширина і висота; CRC після 13 байтів даних
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestThumbnailRejectsDecompressionBomb(t *testing.T) {
+	data := pngWithSize(t, 100_000, 100_000)
+	if _, err := Thumbnail(bytes.NewReader(data), 480, 1); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("Thumbnail error = %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestThumbnailScalesDown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1000, 500))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Thumbnail(&buf, 480, 1)
+	if err != nil {
+		t.Fatalf("Thumbnail: %v", err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(480, 240) {
+		t.Fatalf("thumbnail size = %v, want 480x240", size)
+	}
+}
@@ -0,0 +1,136 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+)
+
+// WatermarkPosition визначає розташування водяного знака на зображенні
+type WatermarkPosition string
+
+const (
+	WatermarkCenter      WatermarkPosition = "center"
+	WatermarkTopLeft     WatermarkPosition = "top-left"
+	WatermarkTopRight    WatermarkPosition = "top-right"
+	WatermarkBottomLeft  WatermarkPosition = "bottom-left"
+	WatermarkBottomRight WatermarkPosition = "bottom-right"
+	// WatermarkTile - знак повторюється по всьому зображенню
+	WatermarkTile WatermarkPosition = "tile"
+)
+
+// IsValid перевіряє допустимість розташування
+func (p WatermarkPosition) IsValid() bool {
+	switch p {
+	case WatermarkCenter, WatermarkTopLeft, WatermarkTopRight, WatermarkBottomLeft, WatermarkBottomRight, WatermarkTile:
+		return true
+	default:
+		return false
+	}
+}
+
+// WatermarkOptions визначає параметри накладання водяного знака
+type WatermarkOptions struct {
+	Position WatermarkPosition
+	// Opacity - непрозорість від 0 до 1
+	Opacity float64
+	// Scale - ширина знака відносно ширини зображення, від 0 до 1
+	Scale float64
+}
+
+// normalize підставляє значення за замовчуванням замість некоректних
+func (o WatermarkOptions) normalize() WatermarkOptions {
+	if !o.Position.IsValid() {
+		o.Position = WatermarkCenter
+	}
+	if o.Opacity <= 0 || o.Opacity > 1 {
+		o.Opacity = 0.5
+	}
+	if o.Scale <= 0 || o.Scale > 1 {
+		o.Scale = 0.3
+	}
+	return o
+}
+
+// MaxImagePixels - найбільша кількість пікселів зображення, яке дозволено декодувати (50 Мп).
+// Невеликий файл може оголосити величезні розміри і вичерпати пам'ять при декодуванні.
+const MaxImagePixels = 50_000_000
+
+// ErrImageTooLarge - розміри зображення перевищують MaxImagePixels
+var ErrImageTooLarge = errors.New("image dimensions exceed the decoding limit")
+
+// DecodeImage декодує зображення (JPEG, PNG, GIF), попередньо перевіривши розміри із заголовка
+func DecodeImage(r io.Reader) (image.Image, error) {
+	// Заголовок, прочитаний DecodeConfig, повторно подається декодеру
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&header, r))
+	return img, err
+}
+
+// Watermark накладає знак mark на зображення base та повертає нове зображення.
+// Прозорість логотипа (PNG з альфа-каналом) зберігається.
+func Watermark(base, mark image.Image, opts WatermarkOptions) *image.RGBA {
+	opts = opts.normalize()
+
+	bounds := base.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), base, bounds.Min, draw.Src)
+
+	markBounds := mark.Bounds()
+	if markBounds.Dx() == 0 || markBounds.Dy() == 0 {
+		return dst
+	}
+	width := max(1, int(float64(dst.Bounds().Dx())*opts.Scale))
+	height := max(1, markBounds.Dy()*width/markBounds.Dx())
+	scaled := resize(mark, width, height)
+
+	mask := image.NewUniform(color.Alpha{A: uint8(opts.Opacity * 255)})
+	for _, pt := range watermarkPoints(dst.Bounds().Size(), image.Pt(width, height), opts.Position) {
+		rect := image.Rectangle{Min: pt, Max: pt.Add(image.Pt(width, height))}
+		draw.DrawMask(dst, rect, scaled, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	return dst
+}
+
+// watermarkPoints повертає верхні ліві кути, в яких малюється знак
+func watermarkPoints(size, mark image.Point, position WatermarkPosition) []image.Point {
+	margin := min(size.X, size.Y) * 3 / 100
+	right := size.X - mark.X - margin
+	bottom := size.Y - mark.Y - margin
+
+	switch position {
+	case WatermarkTopLeft:
+		return []image.Point{{margin, margin}}
+	case WatermarkTopRight:
+		return []image.Point{{right, margin}}
+	case WatermarkBottomLeft:
+		return []image.Point{{margin, bottom}}
+	case WatermarkBottomRight:
+		return []image.Point{{right, bottom}}
+	case WatermarkTile:
+		// Рядки зсуваються на половину кроку, щоб знак не утворював рівних стовпців
+		var points []image.Point
+		stepX, stepY := mark.X*2, mark.Y*3
+		for row, y := 0, margin; y < size.Y; row, y = row+1, y+stepY {
+			offset := (row % 2) * mark.X
+			for x := margin - offset; x < size.X; x += stepX {
+				points = append(points, image.Pt(x, y))
+			}
+		}
+		return points
+	default:
+		return []image.Point{{(size.X - mark.X) / 2, (size.Y - mark.Y) / 2}}
+	}
+}
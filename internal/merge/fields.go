@@ -0,0 +1,73 @@
+package merge
+
+// Field описує поле підстановки, доступне в шаблонах документів
+type Field struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Items - поля елемента списку; задані лише для полів, по яких можна пройти {{#each}}
+	Items []Field `json:"items,omitempty"`
+}
+
+// IsList перевіряє, чи є поле списком для {{#each}}
+func (f Field) IsList() bool {
+	return f.Items != nil
+}
+
+// Fields - задокументований набір полів підстановки з даних бронювання.
+// Шаблон з полем поза цим переліком не проходить перевірку.
+var Fields = []Field{
+	{Name: "client.name", Description: "ПІБ клієнта"},
+	{Name: "client.phone", Description: "Телефон клієнта"},
+	{Name: "client.email", Description: "Email клієнта"},
+
+	{Name: "booking.title", Description: "Назва бронювання"},
+	{Name: "event.type", Description: "Тип події"},
+	{Name: "event.date", Description: "Дата події (ДД.ММ.РРРР)"},
+	{Name: "event.start_time", Description: "Час початку (ГГ:ХХ)"},
+	{Name: "event.end_time", Description: "Час завершення (ГГ:ХХ)"},
+	{Name: "event.location", Description: "Місце проведення"},
+
+	{Name: "package.name", Description: "Назва пакета послуг"},
+	{Name: "price.total", Description: "Вартість пакета"},
+	{Name: "price.extra", Description: "Додаткові послуги"},
+	{Name: "price.grand_total", Description: "Загальна сума до сплати"},
+	{Name: "price.deposit", Description: "Передоплата"},
+	{Name: "price.balance", Description: "Залишок після передоплати"},
+	{Name: "price.currency", Description: "Валюта (UAH, USD, EUR)"},
+
+	{Name: "deadline.days", Description: "Термін віддачі матеріалів, днів"},
+	{Name: "deadline.date", Description: "Дата віддачі матеріалів"},
+
+	{Name: "studio.name", Description: "Назва студії або імʼя фотографа"},
+	{Name: "studio.owner", Description: "ПІБ власника акаунта"},
+	{Name: "studio.phone", Description: "Телефон студії"},
+	{Name: "studio.email", Description: "Email студії"},
+	{Name: "studio.legal_name", Description: "Юридична назва (ФОП, ТОВ)"},
+	{Name: "studio.tax_id", Description: "ЄДРПОУ або РНОКПП"},
+	{Name: "studio.iban", Description: "IBAN"},
+	{Name: "studio.bank", Description: "Банк"},
+	{Name: "studio.address", Description: "Юридична адреса"},
+
+	{Name: "today", Description: "Дата формування документа"},
+
+	{
+		Name:        "installments",
+		Description: "Графік платежів: передоплата та остаточний розрахунок",
+		Items: []Field{
+			{Name: "installment.number", Description: "Номер платежу"},
+			{Name: "installment.label", Description: "Призначення платежу"},
+			{Name: "installment.amount", Description: "Сума платежу"},
+			{Name: "installment.due_date", Description: "Дата платежу"},
+		},
+	},
+}
+
+// lookupField повертає поле за назвою серед переданих
+func lookupField(fields []Field, name string) (Field, bool) {
+	for _, f := range fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,268 @@
+// Package merge реалізує шаблони документів з полями підстановки.
+//
+// Синтаксис:
+//
+//	{{client.name}}                          - значення поля
+//	{{#if price.deposit}} ... {{else}} ... {{/if}} - умовний розділ; хибний для порожнього або нульового значення
+//	{{#each installments}} {{installment.amount}} {{/each}} - повтор розділу для кожного елемента списку
+package merge
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fieldName - допустима назва поля: слова з малих латинських літер, розділені крапкою
+var fieldName = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// Problem описує помилку в шаблоні
+type Problem struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError містить усі знайдені в шаблоні помилки
+type ValidationError struct {
+	Problems []Problem `json:"problems"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return "invalid template: " + strings.Join(messages, "; ")
+}
+
+// Data містить значення полів для підстановки
+type Data struct {
+	Values map[string]string
+	Lists  map[string][]map[string]string
+}
+
+type node interface{}
+
+type textNode string
+
+type fieldNode struct {
+	name string
+	line int
+}
+
+type ifNode struct {
+	name string
+	line int
+	then []node
+	els  []node
+}
+
+type eachNode struct {
+	name string
+	line int
+	body []node
+}
+
+// Template - розібраний шаблон документа
+type Template struct {
+	nodes []node
+}
+
+// frame - відкритий розділ {{#if}} або {{#each}} під час розбору
+type frame struct {
+	open  node
+	nodes *[]node
+}
+
+// Parse розбирає текст шаблону; синтаксичні помилки повертаються як *ValidationError
+func Parse(content string) (*Template, error) {
+	root := []node{}
+	stack := []frame{{nodes: &root}}
+	line := 1
+
+	for len(content) > 0 {
+		start := strings.Index(content, "{{")
+		if start < 0 {
+			*stack[len(stack)-1].nodes = append(*stack[len(stack)-1].nodes, textNode(content))
+			break
+		}
+		if start > 0 {
+			*stack[len(stack)-1].nodes = append(*stack[len(stack)-1].nodes, textNode(content[:start]))
+			line += strings.Count(content[:start], "\n")
+		}
+
+		end := strings.Index(content[start:], "}}")
+		if end < 0 {
+			return nil, syntaxError(line, "", "unclosed {{")
+		}
+		tag := strings.TrimSpace(content[start+2 : start+end])
+		content = content[start+end+2:]
+
+		current := stack[len(stack)-1].nodes
+		switch {
+		case strings.HasPrefix(tag, "#if "), strings.HasPrefix(tag, "#each "):
+			keyword, name, _ := strings.Cut(tag, " ")
+			name = strings.TrimSpace(name)
+			if !fieldName.MatchString(name) {
+				return nil, syntaxError(line, name, "invalid field name")
+			}
+			if keyword == "#if" {
+				n := &ifNode{name: name, line: line}
+				*current = append(*current, n)
+				stack = append(stack, frame{open: n, nodes: &n.then})
+			} else {
+				n := &eachNode{name: name, line: line}
+				*current = append(*current, n)
+				stack = append(stack, frame{open: n, nodes: &n.body})
+			}
+		case tag == "else":
+			n, ok := stack[len(stack)-1].open.(*ifNode)
+			if !ok || stack[len(stack)-1].nodes == &n.els {
+				return nil, syntaxError(line, "", "{{else}} outside of {{#if}}")
+			}
+			stack[len(stack)-1].nodes = &n.els
+		case tag == "/if":
+			if _, ok := stack[len(stack)-1].open.(*ifNode); !ok {
+				return nil, syntaxError(line, "", "unexpected {{/if}}")
+			}
+			stack = stack[:len(stack)-1]
+		case tag == "/each":
+			if _, ok := stack[len(stack)-1].open.(*eachNode); !ok {
+				return nil, syntaxError(line, "", "unexpected {{/each}}")
+			}
+			stack = stack[:len(stack)-1]
+		case fieldName.MatchString(tag):
+			*current = append(*current, &fieldNode{name: tag, line: line})
+		default:
+			return nil, syntaxError(line, tag, fmt.Sprintf("invalid tag {{%s}}", tag))
+		}
+		line += strings.Count(tag, "\n")
+	}
+
+	if len(stack) > 1 {
+		switch n := stack[len(stack)-1].open.(type) {
+		case *ifNode:
+			return nil, syntaxError(n.line, n.name, "{{#if}} is not closed")
+		case *eachNode:
+			return nil, syntaxError(n.line, n.name, "{{#each}} is not closed")
+		}
+	}
+	return &Template{nodes: root}, nil
+}
+
+// Validate перевіряє, що шаблон використовує лише задокументовані поля та власні поля custom:
+// {{#each}} - лише по списках, поля елемента - лише всередині свого {{#each}}
+func (t *Template) Validate(custom []Field) error {
+	var problems []Problem
+	validateNodes(t.nodes, append(append([]Field{}, Fields...), custom...), nil, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validateNodes(nodes []node, fields, item []Field, problems *[]Problem) {
+	known := func(name string, line int) (Field, bool) {
+		if f, ok := lookupField(fields, name); ok {
+			return f, true
+		}
+		if f, ok := lookupField(item, name); ok {
+			return f, true
+		}
+		*problems = append(*problems, Problem{Line: line, Field: name, Message: fmt.Sprintf("unknown field %q", name)})
+		return Field{}, false
+	}
+
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *fieldNode:
+			if f, ok := known(n.name, n.line); ok && f.IsList() {
+				*problems = append(*problems, Problem{Line: n.line, Field: n.name, Message: fmt.Sprintf("%q is a list, use {{#each %s}}", n.name, n.name)})
+			}
+		case *ifNode:
+			known(n.name, n.line)
+			validateNodes(n.then, fields, item, problems)
+			validateNodes(n.els, fields, item, problems)
+		case *eachNode:
+			f, ok := known(n.name, n.line)
+			if ok && !f.IsList() {
+				*problems = append(*problems, Problem{Line: n.line, Field: n.name, Message: fmt.Sprintf("%q is not a list", n.name)})
+			}
+			validateNodes(n.body, fields, f.Items, problems)
+		}
+	}
+}
+
+// Render підставляє значення полів у шаблон
+func (t *Template) Render(data Data) string {
+	var out strings.Builder
+	renderNodes(&out, t.nodes, data, nil)
+	return out.String()
+}
+
+func renderNodes(out *strings.Builder, nodes []node, data Data, item map[string]string) {
+	value := func(name string) string {
+		if v, ok := item[name]; ok {
+			return v
+		}
+		return data.Values[name]
+	}
+
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			out.WriteString(string(n))
+		case *fieldNode:
+			out.WriteString(value(n.name))
+		case *ifNode:
+			if list, ok := data.Lists[n.name]; ok && len(list) > 0 || isTruthy(value(n.name)) {
+				renderNodes(out, n.then, data, item)
+			} else {
+				renderNodes(out, n.els, data, item)
+			}
+		case *eachNode:
+			for _, element := range data.Lists[n.name] {
+				renderNodes(out, n.body, data, element)
+			}
+		}
+	}
+}
+
+// isTruthy - умова хибна для порожнього значення та нуля (0, 0,00)
+func isTruthy(value string) bool {
+	return strings.Trim(value, "0,. ") != ""
+}
+
+// Validate розбирає та перевіряє шаблон
+func Validate(content string, custom []Field) error {
+	tmpl, err := Parse(content)
+	if err != nil {
+		return err
+	}
+	return tmpl.Validate(custom)
+}
+
+// ValidateFields розбирає шаблон і перевіряє його з власним набором полів замість полів бронювання
+func ValidateFields(content string, fields []Field) error {
+	tmpl, err := Parse(content)
+	if err != nil {
+		return err
+	}
+
+	var problems []Problem
+	validateNodes(tmpl.nodes, fields, nil, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// IsValidName перевіряє назву власного поля
+func IsValidName(name string) bool {
+	return fieldName.MatchString(name)
+}
+
+func syntaxError(line int, field, message string) error {
+	return &ValidationError{Problems: []Problem{{Line: line, Field: field, Message: message}}}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// MultipartOverhead - запас на заголовки та межі multipart-форми понад розмір файлу
+const MultipartOverhead = 1024 * 1024
+
+// UploadLimit - маршрут завантаження, тіло якого може бути більшим за загальний ліміт
+type UploadLimit struct {
+	Method string
+	// Path - шаблон маршруту Fiber, наприклад "/g/:token/uploads"
+	Path  string
+	Limit int64
+}
+
+// BodyLimit обмежує розмір тіла запиту: limit для всіх маршрутів і окремі ліміти для маршрутів завантаження.
+// Сервер має працювати з StreamRequestBody: тоді в пам'ять читається лише початок тіла, а решта
+// читається з з'єднання, коли обробник розбирає форму. Перевірка заявленої довжини відбувається
+// до читання, а потік тіла не віддає більше байтів, ніж заявлено, тож ліміт діє під час читання.
+// Тіла без довжини (chunked) відхиляються: їхній розмір не можна перевірити заздалегідь.
+// Відхилене тіло не читається, тому з'єднання після відповіді закривається.
+// Реєструється до маршрутів.
+func BodyLimit(limit int64, uploads ...UploadLimit) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		length := int64(c.Request().Header.ContentLength())
+		if length == -1 {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusLengthRequired).JSON(fiber.Map{
+				"error": "Content-Length is required",
+			})
+		}
+
+		allowed := limit
+		for _, upload := range uploads {
+			if c.Method() == upload.Method && fiber.RoutePatternMatch(c.Path(), upload.Path) {
+				allowed = upload.Limit
+				break
+			}
+		}
+		if length > allowed {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "Request body is too large",
+			})
+		}
+		return c.Next()
+	}
+}
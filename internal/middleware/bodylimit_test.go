@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newBodyLimitApp() *fiber.App {
+	app := fiber.New(fiber.Config{
+		BodyLimit:                    1024,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
+	app.Use(BodyLimit(1024, UploadLimit{Method: fiber.MethodPost, Path: "/g/:token/uploads", Limit: 64 * 1024}))
+	app.Post("/g/:token/uploads", func(c *fiber.Ctx) error {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.JSON(fiber.Map{"size": file.Size})
+	})
+	app.Post("/notes", func(c *fiber.Ctx) error {
+		return c.SendString(string(c.Body()))
+	})
+	return app
+}
+
+func uploadBody(t *testing.T, size int) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "photo.jpg")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write(bytes.Repeat([]byte{'x'}, size))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestBodyLimit(t *testing.T) {
+	app := newBodyLimitApp()
+
+	tests := []struct {
+		name string
+		path string
+		size int
+		want int
+	}{
+		// Файл більший за загальний ліміт, але в межах ліміту маршруту читається потоком
+		{"upload within route limit", "/g/abc/uploads", 32 * 1024, fiber.StatusOK},
+		{"upload over route limit", "/g/abc/uploads", 128 * 1024, fiber.StatusRequestEntityTooLarge},
+		{"other route over default limit", "/notes", 2048, fiber.StatusRequestEntityTooLarge},
+		{"other route within default limit", "/notes", 100, fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		body, contentType := uploadBody(t, tt.size)
+		req := httptest.NewRequest(fiber.MethodPost, tt.path, body)
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestBodyLimitRequiresLength(t *testing.T) {
+	app := newBodyLimitApp()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/notes", io.MultiReader(bytes.NewReader([]byte("note"))))
+	req.ContentLength = -1
+	req.TransferEncoding = []string{"chunked"}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusLengthRequired {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusLengthRequired)
+	}
+}
@@ -109,9 +109,13 @@ type Booking struct {
 	PricePrepayment float64        `json:"price_prepayment"`
 	TeamMembers     datatypes.JSON `json:"team_members"`
 	TeamPayments    datatypes.JSON `json:"team_payments"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       *time.Time     `json:"deleted_at,omitempty" gorm:"index"`
+	ContractFileURL string         `json:"contract_file_url"` // поточна версія договору
+	DeliveryPageURL string         `json:"delivery_page_url"` // посилання на публічну галерею
+	// IsOverdue - термін віддачі матеріалів минув, а проєкт ще в роботі; оновлюється планувальником термінів
+	IsOverdue bool       `json:"is_overdue" gorm:"not null;default:false"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" gorm:"index"`
 
 	// Зв'язки
 	User   *User   `json:"-" gorm:"foreignKey:UserID"`
@@ -144,6 +148,26 @@ func (b *Booking) ToPublic() BookingPublic {
 	}
 }
 
+// Deadline повертає термін віддачі матеріалів: дата події плюс DeadlineDays.
+// ok = false, якщо термін не задано.
+func (b *Booking) Deadline() (deadline time.Time, ok bool) {
+	if b.DeadlineDays <= 0 || b.EventDate.IsZero() {
+		return time.Time{}, false
+	}
+	return b.EventDate.AddDate(0, 0, b.DeadlineDays), true
+}
+
+// TracksDeadline перевіряє, чи проєкт ще в роботі, тобто чи стежити за терміном віддачі
+func (b *Booking) TracksDeadline() bool {
+	return b.Status == BookingStatusBooked || b.Status == BookingStatusEditing
+}
+
+// RefreshOverdue оновлює ознаку простроченого проєкту на момент now
+func (b *Booking) RefreshOverdue(now time.Time) {
+	deadline, ok := b.Deadline()
+	b.IsOverdue = ok && b.TracksDeadline() && now.After(deadline)
+}
+
 // CalculateProfit обчислює прибуток від бронювання
 func (b *Booking) CalculateProfit() float64 {
 	var teamPaymentsTotal float64
@@ -39,7 +39,10 @@ type Client struct {
 	DeletedAt    *time.Time     `json:"deleted_at,omitempty" gorm:"index"`
 	User         *User          `json:"-" gorm:"foreignKey:UserID"`
 	Bookings     []*Booking     `json:"-" gorm:"foreignKey:ClientID"`
-	Avatar       string         `json:"avatar"`
+	// AvatarFileID - файл аватара; посилання на нього підписується при видачі клієнта
+	AvatarFileID *uuid.UUID `json:"avatar_file_id,omitempty" gorm:"type:uuid"`
+	// Avatar - підписаний URL аватара з обмеженим терміном дії, у БД не зберігається
+	Avatar string `json:"avatar" gorm:"-"`
 }
 
 // ClientPublic представляє публічний вигляд клієнта
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ContractStatus визначає стан версії договору
+type ContractStatus string
+
+const (
+	// ContractStatusDraft - договір сформовано, але ще не надіслано клієнту
+	ContractStatusDraft ContractStatus = "draft"
+	// ContractStatusSent - договір доступний клієнту для підписання на публічній сторінці
+	ContractStatusSent ContractStatus = "sent"
+	// ContractStatusSigned - клієнт підписав договір
+	ContractStatusSigned ContractStatus = "signed"
+)
+
+// Contract - версія договору бронювання. Кожне формування або заміна файлу створює
+// нову версію; попередні залишаються доступними в історії.
+type Contract struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	BookingID  uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_contracts_booking_version" json:"booking_id"`
+	Version    int            `gorm:"not null;uniqueIndex:idx_contracts_booking_version" json:"version"`
+	TemplateID *uuid.UUID     `gorm:"type:uuid" json:"template_id,omitempty"`
+	FileID     uuid.UUID      `gorm:"type:uuid;not null" json:"file_id"`
+	Status     ContractStatus `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	// SignedFileID - PDF з журналом підписання, доданим після тексту договору
+	SignedFileID *uuid.UUID `gorm:"type:uuid" json:"signed_file_id,omitempty"`
+	SentAt       *time.Time `json:"sent_at,omitempty"`
+	SignedAt     *time.Time `json:"signed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Зв'язки
+	Booking    *Booking           `gorm:"foreignKey:BookingID" json:"booking,omitempty"`
+	File       *File              `gorm:"foreignKey:FileID" json:"file,omitempty"`
+	SignedFile *File              `gorm:"foreignKey:SignedFileID" json:"signed_file,omitempty"`
+	Signature  *ContractSignature `gorm:"foreignKey:ContractID" json:"signature,omitempty"`
+}
+
+// ContractListOptions містить фільтри зведеної таблиці договорів
+type ContractListOptions struct {
+	ClientID *uuid.UUID `json:"client_id"`
+	// Search - частина імені клієнта
+	Search string         `json:"search"`
+	Status ContractStatus `json:"status"`
+	// EventFrom та EventTo обмежують дату події; EventTo не включається
+	EventFrom *time.Time `json:"event_from"`
+	EventTo   *time.Time `json:"event_to"`
+}
+
+// ContractSignature - запис журналу підписання договору клієнтом
+type ContractSignature struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	ContractID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"contract_id"`
+	SignerName string    `gorm:"size:255;not null" json:"signer_name"`
+	IP         string    `gorm:"size:45;not null" json:"ip"`
+	UserAgent  string    `gorm:"type:text" json:"user_agent"`
+	// DocumentSHA256 - хеш PDF, який клієнт переглянув і підписав
+	DocumentSHA256 string `gorm:"size:64;not null" json:"document_sha256"`
+	// Image - намальований підпис у форматі PNG
+	Image     []byte    `gorm:"type:bytea;not null" json:"-"`
+	SignedAt  time.Time `gorm:"not null" json:"signed_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsSigned перевіряє, чи клієнт підписав версію договору
+func (c *Contract) IsSigned() bool {
+	return c.Status == ContractStatusSigned
+}
+
+// Document повертає файл для перегляду: підписаний примірник, якщо він є
+func (c *Contract) Document() *File {
+	if c.SignedFile != nil {
+		return c.SignedFile
+	}
+	return c.File
+}
+
+// IsValid перевіряє, чи відомий статус договору
+func (s ContractStatus) IsValid() bool {
+	switch s {
+	case ContractStatusDraft, ContractStatusSent, ContractStatusSigned:
+		return true
+	default:
+		return false
+	}
+}
+
+// FilePath повертає шлях для перегляду PDF версії в застосунку
+func (c *Contract) FilePath() string {
+	return "/app/contracts/" + c.ID.String() + "/file"
+}
+
+// BeforeCreate generates a new UUID for the contract if not set
+func (c *Contract) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeCreate generates a new UUID for the signature if not set
+func (s *ContractSignature) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeadlineReminder - надіслане нагадування про термін віддачі матеріалів.
+// Запис гарантує, що нагадування за DaysBefore днів до терміну DeadlineAt надсилається один раз;
+// DaysBefore = 0 - сповіщення про прострочення.
+type DeadlineReminder struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	BookingID  uuid.UUID `gorm:"type:uuid;not null" json:"booking_id"`
+	DaysBefore int       `gorm:"not null" json:"days_before"`
+	DeadlineAt time.Time `gorm:"not null" json:"deadline_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BeforeCreate generates a new UUID for the reminder if not set
+func (r *DeadlineReminder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
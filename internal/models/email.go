@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailAttachment - вкладення листа в черзі надсилання
+type EmailAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// OutboxEmail - лист у черзі надсилання. Листи надсилає фоновий обробник
+// з повторними спробами, тож запит, що поставив лист у чергу, не чекає на SMTP.
+type OutboxEmail struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	// UserID - студія, від імені якої надсилається лист
+	UserID      *uuid.UUID        `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Event       string            `gorm:"type:varchar(50);not null" json:"event"`
+	Recipients  []string          `gorm:"type:jsonb;serializer:json;not null" json:"recipients"`
+	Subject     string            `gorm:"type:varchar(255);not null" json:"subject"`
+	Body        string            `gorm:"type:text;not null" json:"body"`
+	HTMLBody    string            `gorm:"column:html_body;type:text" json:"-"`
+	Attachments []EmailAttachment `gorm:"type:jsonb;serializer:json" json:"-"`
+	Status      DeliveryStatus    `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts    int               `gorm:"not null;default:0" json:"attempts"`
+	LastError   string            `gorm:"type:text" json:"last_error,omitempty"`
+	// NextAttemptAt - час наступної спроби для листів у стані pending
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName повертає назву таблиці черги листів
+func (OutboxEmail) TableName() string {
+	return "email_outbox"
+}
+
+// BeforeCreate generates a new UUID for the email if not set
+func (e *OutboxEmail) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
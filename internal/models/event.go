@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// EventStatus визначає стан доменної події в outbox
+type EventStatus string
+
+const (
+	EventStatusPending EventStatus = "pending"
+	// EventStatusProcessed - усі підписники обробили подію
+	EventStatusProcessed EventStatus = "processed"
+	// EventStatusFailed - спроби вичерпано або подію неможливо розібрати
+	EventStatusFailed EventStatus = "failed"
+)
+
+// DomainEvent - доменна подія в outbox. Подія записується в тій самій транзакції, що й зміна,
+// яка її спричинила, тож не губиться й не з'являється без зміни. Події розсилає фоновий обробник.
+type DomainEvent struct {
+	ID      uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	Name    string         `gorm:"type:varchar(100);not null" json:"name"`
+	Payload datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
+	Status  EventStatus    `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	// Handled - підписники, що вже обробили подію; повторна спроба їх пропускає
+	Handled   []string `gorm:"type:jsonb;serializer:json;not null" json:"handled"`
+	Attempts  int      `gorm:"not null;default:0" json:"attempts"`
+	LastError string   `gorm:"type:text" json:"last_error,omitempty"`
+	// NextAttemptAt - час наступної спроби для подій у стані pending
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName повертає назву таблиці outbox доменних подій
+func (DomainEvent) TableName() string {
+	return "event_outbox"
+}
+
+// BeforeCreate generates a new UUID for the event if not set
+func (e *DomainEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -24,6 +26,29 @@ const (
 	FileTypeDocument FileType = "document"
 	FileTypeImage    FileType = "image"
 	FileTypeVideo    FileType = "video"
+	// FileTypeWatermark - логотип для водяного знака на превʼю галерей
+	FileTypeWatermark FileType = "watermark"
+)
+
+const (
+	// FolderFromClient - папка файлів, надісланих клієнтом з публічної сторінки бронювання
+	FolderFromClient = "from-client"
+	// FolderContracts - папка версій договорів бронювання
+	FolderContracts = "contracts"
+)
+
+// ScanStatus represents the malware scan state of a file
+type ScanStatus string
+
+const (
+	// ScanStatusPending - файл ще не перевірено, завантаження заблоковано
+	ScanStatusPending ScanStatus = "pending"
+	// ScanStatusClean - загроз не знайдено
+	ScanStatusClean ScanStatus = "clean"
+	// ScanStatusInfected - файл у карантині
+	ScanStatusInfected ScanStatus = "infected"
+	// ScanStatusFailed - перевірка не вдалася і буде повторена
+	ScanStatusFailed ScanStatus = "failed"
 )
 
 // File represents a file in the system
@@ -39,14 +64,41 @@ type File struct {
 	MimeType    string     `gorm:"not null" json:"mime_type"`
 	URL         string     `gorm:"not null" json:"url"`
 	PublicURL   string     `gorm:"not null" json:"public_url"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ContentHash string     `gorm:"size:64;index" json:"content_hash,omitempty"` // SHA-256 вмісту
+	// Folder - папка файлу в межах бронювання; порожня для матеріалів студії
+	Folder string `gorm:"size:50;not null;default:''" json:"folder,omitempty"`
+	// Metadata - метадані медіафайлу (EXIF, роздільна здатність, тривалість відео)
+	Metadata datatypes.JSON `gorm:"type:jsonb" json:"metadata,omitempty"`
+	// TakenAt - дата зйомки, винесена окремо для сортування та фільтрації
+	TakenAt *time.Time `gorm:"index" json:"taken_at,omitempty"`
+	// ScanStatus - стан перевірки на шкідливе ПЗ; завантаження дозволене лише для clean
+	ScanStatus    ScanStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"scan_status"`
+	ScanSignature string     `json:"scan_signature,omitempty"`
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	// DeletedAt - час переміщення файлу в кошик
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Зв'язки
 	User    *User    `gorm:"foreignKey:UserID" json:"-"`
 	Booking *Booking `json:"-" gorm:"foreignKey:BookingID"`
 }
 
+// FileSearchOptions визначає фільтри пошуку файлів за метаданими
+type FileSearchOptions struct {
+	BookingID   *uuid.UUID `json:"booking_id"`
+	Type        FileType   `json:"type"`
+	Folder      *string    `json:"folder"`
+	TakenFrom   *time.Time `json:"taken_from"`
+	TakenTo     *time.Time `json:"taken_to"`
+	Camera      string     `json:"camera"`
+	Orientation string     `json:"orientation"`
+	// SortBy - created_at (за замовчуванням), taken_at або name
+	SortBy   string `json:"sort_by"`
+	SortDesc bool   `json:"sort_desc"`
+}
+
 // FilePublic represents a public view of a file
 type FilePublic struct {
 	ID        uuid.UUID `json:"id"`
@@ -83,6 +135,18 @@ func (f *File) Validate() error {
 	return nil
 }
 
+// FileTypeFromMime визначає тип файлу за MIME-типом
+func FileTypeFromMime(mimeType string) FileType {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return FileTypeImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return FileTypeVideo
+	default:
+		return FileTypeDocument
+	}
+}
+
 // IsImage checks if the file is an image
 func (f *File) IsImage() bool {
 	switch f.MimeType {
@@ -103,6 +167,21 @@ func (f *File) IsVideo() bool {
 	}
 }
 
+// IsClean checks if the file passed the malware scan
+func (f *File) IsClean() bool {
+	return f.ScanStatus == ScanStatusClean
+}
+
+// IsQuarantined checks if the file was flagged by the malware scan
+func (f *File) IsQuarantined() bool {
+	return f.ScanStatus == ScanStatusInfected
+}
+
+// IsTrashed checks if the file is in the trash
+func (f *File) IsTrashed() bool {
+	return f.DeletedAt.Valid
+}
+
 // GetStorageKey returns the key for storing the file
 func (f *File) GetStorageKey() string {
 	if f.BookingID != nil {
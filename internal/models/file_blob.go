@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FileBlob представляє вміст файлу, що зберігається один раз на акаунт.
+// Файли з однаковим SHA-256 посилаються на один blob, а RefCount рахує ці посилання.
+type FileBlob struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_file_blobs_user_hash" json:"user_id"`
+	Hash      string    `gorm:"size:64;not null;uniqueIndex:idx_file_blobs_user_hash" json:"hash"`
+	Size      int64     `gorm:"not null" json:"size"`
+	Path      string    `gorm:"not null" json:"path"`
+	RefCount  int       `gorm:"not null;default:1" json:"ref_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BlobPath повертає шлях content-addressed blob у сховищі
+func BlobPath(userID uuid.UUID, hash string) string {
+	return "blobs/" + userID.String() + "/" + hash[:2] + "/" + hash
+}
+
+// BeforeCreate generates a new UUID for the blob if not set
+func (b *FileBlob) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
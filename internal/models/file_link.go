@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FileLink представляє посилання на завантаження файлу з обмеженням кількості завантажень
+type FileLink struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	FileID       uuid.UUID `gorm:"type:uuid;not null;index" json:"file_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	ExpiresAt    time.Time `gorm:"not null" json:"expires_at"`
+	MaxDownloads int       `gorm:"not null;default:0" json:"max_downloads"` // 0 - без обмежень
+	Downloads    int       `gorm:"not null;default:0" json:"downloads"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Зв'язки
+	File *File `gorm:"foreignKey:FileID" json:"-"`
+}
+
+// IsExpired перевіряє чи минув термін дії посилання
+func (l *FileLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// IsExhausted перевіряє чи вичерпано ліміт завантажень
+func (l *FileLink) IsExhausted() bool {
+	return l.MaxDownloads > 0 && l.Downloads >= l.MaxDownloads
+}
+
+// BeforeCreate generates a new UUID for the link if not set
+func (l *FileLink) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Шаблони публічної сторінки клієнта
+const (
+	GalleryTemplateClassic = "classic"
+	GalleryTemplateMinimal = "minimal"
+	GalleryTemplateDark    = "dark"
+)
+
+// GalleryTemplates містить доступні шаблони галереї
+var GalleryTemplates = []string{GalleryTemplateClassic, GalleryTemplateMinimal, GalleryTemplateDark}
+
+// IsValidGalleryTemplate перевіряє чи існує шаблон галереї
+func IsValidGalleryTemplate(name string) bool {
+	for _, t := range GalleryTemplates {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Gallery представляє публічну сторінку віддачі матеріалів клієнту для бронювання
+type Gallery struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	BookingID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"booking_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	// Token - неявна частина публічного посилання; при перегенерації старе посилання перестає працювати
+	Token   string `gorm:"size:64;not null;uniqueIndex" json:"token"`
+	Enabled bool   `gorm:"not null;default:true" json:"enabled"`
+	// PasswordHash - bcrypt-хеш пароля сторінки; порожній, якщо пароль не встановлено
+	PasswordHash string `gorm:"size:255" json:"-"`
+	// ExpiresAt - після цієї дати сторінка автоматично блокується
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	// AllowDownload - дозволити завантаження файлів в оригінальній роздільній здатності
+	AllowDownload bool `gorm:"not null;default:true" json:"allow_download"`
+	// DownloadLimit - максимальна кількість завантажень оригіналів; 0 - без обмежень
+	DownloadLimit int `gorm:"not null;default:0" json:"download_limit"`
+	DownloadCount int `gorm:"not null;default:0" json:"download_count"`
+	// ProofingEnabled - клієнт може відбирати фото для ретуші
+	ProofingEnabled bool `gorm:"not null;default:false" json:"proofing_enabled"`
+	// MaxSelections - максимальна кількість обраних фото; 0 - без обмежень
+	MaxSelections int `gorm:"not null;default:0" json:"max_selections"`
+	// SelectionSubmittedAt - час надсилання відбору; після нього зміни заблоковані
+	SelectionSubmittedAt *time.Time `json:"selection_submitted_at,omitempty"`
+	// ClientUploads - клієнт може надсилати файли (референси, тайминг, музику) зі сторінки
+	ClientUploads bool      `gorm:"not null;default:false" json:"client_uploads"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Зв'язки
+	Booking *Booking `gorm:"foreignKey:BookingID" json:"-"`
+}
+
+// PublicPath повертає шлях публічної сторінки галереї
+func (g *Gallery) PublicPath() string {
+	return "/g/" + g.Token
+}
+
+// HasPassword перевіряє, чи захищена сторінка паролем
+func (g *Gallery) HasPassword() bool {
+	return g.PasswordHash != ""
+}
+
+// IsExpired перевіряє, чи минув термін доступу до сторінки
+func (g *Gallery) IsExpired(now time.Time) bool {
+	return g.ExpiresAt != nil && !now.Before(*g.ExpiresAt)
+}
+
+// SelectionSubmitted перевіряє, чи клієнт уже надіслав відбір
+func (g *Gallery) SelectionSubmitted() bool {
+	return g.SelectionSubmittedAt != nil
+}
+
+// DownloadLimitReached перевіряє, чи вичерпано ліміт завантажень оригіналів
+func (g *Gallery) DownloadLimitReached() bool {
+	return g.DownloadLimit > 0 && g.DownloadCount >= g.DownloadLimit
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (g *Gallery) MarshalJSON() ([]byte, error) {
+	type Alias Gallery
+	return json.Marshal(&struct {
+		*Alias
+		PasswordProtected bool `json:"password_protected"`
+		Expired           bool `json:"expired"`
+	}{
+		Alias:             (*Alias)(g),
+		PasswordProtected: g.HasPassword(),
+		Expired:           g.IsExpired(time.Now()),
+	})
+}
+
+// BeforeCreate generates a new UUID for the gallery if not set
+func (g *Gallery) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// GalleryUpdate містить налаштування доступу до сторінки
+type GalleryUpdate struct {
+	// Password - новий пароль; порожній рядок знімає захист
+	Password  *string    `json:"password,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// ClearExpiry - зробити сторінку безстроковою
+	ClearExpiry     bool  `json:"clear_expiry,omitempty"`
+	AllowDownload   *bool `json:"allow_download,omitempty"`
+	DownloadLimit   *int  `json:"download_limit,omitempty"`
+	ProofingEnabled *bool `json:"proofing_enabled,omitempty"`
+	MaxSelections   *int  `json:"max_selections,omitempty"`
+	ClientUploads   *bool `json:"client_uploads,omitempty"`
+}
+
+// GalleryAction визначає тип дії відвідувача сторінки
+type GalleryAction string
+
+const (
+	// GalleryActionView - клієнт відкрив сторінку
+	GalleryActionView GalleryAction = "view"
+	// GalleryActionFileView - клієнт переглянув фото або відео
+	GalleryActionFileView GalleryAction = "file_view"
+	// GalleryActionDownload - клієнт завантажив оригінал файлу
+	GalleryActionDownload GalleryAction = "download"
+	// GalleryActionZipDownload - клієнт завантажив архів усієї галереї
+	GalleryActionZipDownload GalleryAction = "zip_download"
+	GalleryActionUnlock      GalleryAction = "unlock"
+	// GalleryActionClientUpload - клієнт надіслав файл зі сторінки
+	GalleryActionClientUpload GalleryAction = "client_upload"
+)
+
+// GalleryAccess - запис журналу переглядів та завантажень публічної сторінки
+type GalleryAccess struct {
+	ID        uuid.UUID     `gorm:"type:uuid;primary_key" json:"id"`
+	GalleryID uuid.UUID     `gorm:"type:uuid;not null;index" json:"gallery_id"`
+	FileID    *uuid.UUID    `gorm:"type:uuid" json:"file_id,omitempty"`
+	Action    GalleryAction `gorm:"type:varchar(20);not null" json:"action"`
+	// IP - анонімізована адреса (останній октет IPv4 / хвіст IPv6 обнулено)
+	IP        string    `gorm:"size:64" json:"ip"`
+	UserAgent string    `gorm:"size:512" json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName повертає назву таблиці журналу доступу
+func (GalleryAccess) TableName() string {
+	return "gallery_access_log"
+}
+
+// BeforeCreate generates a new UUID for the access record if not set
+func (a *GalleryAccess) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// GalleryStats містить зведену статистику відвідувань сторінки
+type GalleryStats struct {
+	Views          int64      `json:"views"`
+	FileViews      int64      `json:"file_views"`
+	Downloads      int64      `json:"downloads"`
+	ZipDownloads   int64      `json:"zip_downloads"`
+	UniqueVisitors int64      `json:"unique_visitors"`
+	LastViewedAt   *time.Time `json:"last_viewed_at,omitempty"`
+}
+
+// IsEmpty перевіряє, чи були якісь дії клієнтів
+func (s *GalleryStats) IsEmpty() bool {
+	return s.Views == 0 && s.FileViews == 0 && s.Downloads == 0 && s.ZipDownloads == 0
+}
+
+// BookingGalleryActivity - активність клієнтів у галереї бронювання за період
+type BookingGalleryActivity struct {
+	BookingID    uuid.UUID `json:"booking_id"`
+	BookingTitle string    `json:"booking_title"`
+	GalleryStats `gorm:"embedded"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// JobStatus визначає стан фонової задачі
+type JobStatus string
+
+const (
+	// JobStatusPending - задача чекає на виконання після RunAt
+	JobStatusPending JobStatus = "pending"
+	// JobStatusRunning - задачу взяв обробник; після LockedUntil її може взяти інший
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	// JobStatusFailed - спроби вичерпано або помилка не підлягає повтору
+	JobStatusFailed JobStatus = "failed"
+)
+
+// IsValid перевіряє допустимість стану задачі
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusRunning, JobStatusDone, JobStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Job - фонова задача в черзі
+type Job struct {
+	ID      uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	Type    string         `gorm:"type:varchar(100);not null" json:"type"`
+	Payload datatypes.JSON `gorm:"type:jsonb" json:"payload,omitempty"`
+	// UniqueKey - поки задача з ключем очікує або виконується, іншу з тим самим ключем не буде додано
+	UniqueKey   *string   `gorm:"type:varchar(255)" json:"unique_key,omitempty"`
+	Status      JobStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts    int       `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int       `gorm:"not null;default:1" json:"max_attempts"`
+	LastError   string    `gorm:"type:text" json:"last_error,omitempty"`
+	RunAt       time.Time `gorm:"not null" json:"run_at"`
+	// LockedUntil - до цього часу задачу виконує обробник, що її взяв
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate generates a new UUID for the job if not set
+func (j *Job) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// NotificationType визначає тип сповіщення
+type NotificationType string
+
+const (
+	// NotificationFileInfected - у завантаженому файлі знайдено шкідливе ПЗ
+	NotificationFileInfected NotificationType = "file_infected"
+	// NotificationSelectionSubmitted - клієнт надіслав відбір фото з галереї
+	NotificationSelectionSubmitted NotificationType = "selection_submitted"
+	// NotificationClientUpload - клієнт надіслав файли з публічної сторінки бронювання
+	NotificationClientUpload NotificationType = "client_upload"
+	// NotificationGalleryActivity - клієнт відкрив сторінку віддачі або завантажив файли
+	NotificationGalleryActivity NotificationType = "gallery_activity"
+	// NotificationGalleryDigest - колишнє окреме зведення активності в галереях; тепер воно входить у NotificationDigest
+	NotificationGalleryDigest NotificationType = "gallery_digest"
+	// NotificationContractSigned - клієнт підписав договір на публічній сторінці
+	NotificationContractSigned NotificationType = "contract_signed"
+	// NotificationDeadlineSoon - до терміну віддачі матеріалів проєкту залишилося кілька днів
+	NotificationDeadlineSoon NotificationType = "deadline_soon"
+	// NotificationDeadlineOverdue - термін віддачі матеріалів проєкту минув
+	NotificationDeadlineOverdue NotificationType = "deadline_overdue"
+	// NotificationDigest - щоденний або щотижневий звіт
+	NotificationDigest NotificationType = "digest"
+)
+
+// NotificationChannel визначає зовнішній канал доставки сповіщень.
+// Сповіщення в застосунку створюються завжди і каналом не вважаються.
+type NotificationChannel string
+
+const (
+	NotificationChannelTelegram NotificationChannel = "telegram"
+	NotificationChannelEmail    NotificationChannel = "email"
+)
+
+// IsValid перевіряє, чи відомий канал доставки
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelTelegram, NotificationChannelEmail:
+		return true
+	default:
+		return false
+	}
+}
+
+// DeliveryStatus визначає стан доставки сповіщення в канал
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending DeliveryStatus = "pending"
+	DeliveryStatusSent    DeliveryStatus = "sent"
+	// DeliveryStatusFailed - спроби вичерпано або канал недоступний для користувача
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// NotificationFrequency визначає, як часто надсилати сповіщення
+type NotificationFrequency string
+
+const (
+	NotificationFrequencyOff     NotificationFrequency = "off"
+	NotificationFrequencyInstant NotificationFrequency = "instant"
+	NotificationFrequencyDaily   NotificationFrequency = "daily"
+	NotificationFrequencyWeekly  NotificationFrequency = "weekly"
+)
+
+// IsValid перевіряє допустимість частоти сповіщень
+func (f NotificationFrequency) IsValid() bool {
+	switch f {
+	case NotificationFrequencyOff, NotificationFrequencyInstant, NotificationFrequencyDaily, NotificationFrequencyWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Period повертає період зведення; 0 - для миттєвих або вимкнених сповіщень
+func (f NotificationFrequency) Period() time.Duration {
+	switch f {
+	case NotificationFrequencyDaily:
+		return 24 * time.Hour
+	case NotificationFrequencyWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// UserNotificationSettings містить налаштування сповіщень користувача (підрядника)
+type UserNotificationSettings struct {
+	// GalleryActivity - частота сповіщень про перегляди та завантаження на сторінках віддачі
+	GalleryActivity NotificationFrequency `json:"gallery_activity"`
+	// Channels - зовнішні канали для кожного типу сповіщень; тип без запису
+	// надсилається в усі підключені канали, порожній список вимикає доставку
+	Channels map[NotificationType][]NotificationChannel `json:"channels,omitempty"`
+	// DeadlineReminders - за скільки днів до терміну віддачі нагадувати; null - типові
+	// значення, порожній список вимикає нагадування (про прострочення сповіщення надходить завжди)
+	DeadlineReminders []int `json:"deadline_reminders"`
+	// Digest - частота звітів: off, daily або weekly; щотижневий звіт надсилається в перший день тижня
+	Digest NotificationFrequency `json:"digest"`
+	// DigestHour - година надсилання звіту за часовим поясом Timezone; null - DefaultDigestHour
+	DigestHour *int `json:"digest_hour"`
+	// Timezone - часовий пояс IANA, у якому надсилаються звіти
+	Timezone string `json:"timezone"`
+}
+
+const (
+	// DefaultDigestHour - година надсилання звітів за замовчуванням
+	DefaultDigestHour = 8
+	// DefaultTimezone - часовий пояс за замовчуванням
+	DefaultTimezone = "Europe/Kyiv"
+)
+
+// DigestFrequency повертає частоту звітів (за замовчуванням звіти вимкнені)
+func (p UserNotificationSettings) DigestFrequency() NotificationFrequency {
+	if p.Digest == "" {
+		return NotificationFrequencyOff
+	}
+	return p.Digest
+}
+
+// DigestTime повертає годину надсилання звітів
+func (p UserNotificationSettings) DigestTime() int {
+	if p.DigestHour == nil {
+		return DefaultDigestHour
+	}
+	return *p.DigestHour
+}
+
+// Location повертає часовий пояс звітів; невідомий пояс замінюється DefaultTimezone
+func (p UserNotificationSettings) Location() *time.Location {
+	if p.Timezone != "" {
+		if loc, err := time.LoadLocation(p.Timezone); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DefaultDeadlineReminders - типові нагадування про термін віддачі, днів до терміну
+var DefaultDeadlineReminders = []int{7, 3, 1}
+
+// MaxDeadlineReminderDays - найраніше нагадування про термін віддачі, днів
+const MaxDeadlineReminderDays = 60
+
+// DeadlineReminderDays повертає, за скільки днів до терміну віддачі нагадувати
+func (p UserNotificationSettings) DeadlineReminderDays() []int {
+	if p.DeadlineReminders == nil {
+		return DefaultDeadlineReminders
+	}
+	return p.DeadlineReminders
+}
+
+// ChannelsFor повертає канали, в які користувач хоче отримувати сповіщення типу t
+func (p UserNotificationSettings) ChannelsFor(t NotificationType, available []NotificationChannel) []NotificationChannel {
+	chosen, ok := p.Channels[t]
+	if !ok {
+		return available
+	}
+
+	var result []NotificationChannel
+	for _, channel := range chosen {
+		for _, a := range available {
+			if channel == a {
+				result = append(result, channel)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// GalleryActivityFrequency повертає частоту сповіщень про галереї (за замовчуванням - щодня)
+func (p UserNotificationSettings) GalleryActivityFrequency() NotificationFrequency {
+	if p.GalleryActivity == "" {
+		return NotificationFrequencyDaily
+	}
+	return p.GalleryActivity
+}
+
+// Notification представляє сповіщення користувача в застосунку
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type      NotificationType `gorm:"type:varchar(50);not null" json:"type"`
+	Title     string           `gorm:"not null" json:"title"`
+	Message   string           `gorm:"type:text" json:"message"`
+	Data      datatypes.JSON   `gorm:"type:jsonb" json:"data,omitempty"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	// Markdown та HTML - форматовані версії для Telegram (MarkdownV2) та листа; порожні - використовується Message
+	Markdown string `gorm:"type:text" json:"-"`
+	HTML     string `gorm:"column:html;type:text" json:"-"`
+}
+
+// IsRead перевіряє чи прочитане сповіщення
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// BeforeCreate generates a new UUID for the notification if not set
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// NotificationDelivery - запис журналу доставки сповіщення в зовнішній канал
+type NotificationDelivery struct {
+	ID             uuid.UUID           `gorm:"type:uuid;primary_key" json:"id"`
+	NotificationID uuid.UUID           `gorm:"type:uuid;not null;index" json:"notification_id"`
+	UserID         uuid.UUID           `gorm:"type:uuid;not null;index" json:"user_id"`
+	Channel        NotificationChannel `gorm:"type:varchar(20);not null" json:"channel"`
+	Status         DeliveryStatus      `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts       int                 `gorm:"not null;default:0" json:"attempts"`
+	LastError      string              `gorm:"type:text" json:"last_error,omitempty"`
+	// NextAttemptAt - час наступної спроби для доставок у стані pending
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	// LockedUntil - до цього часу доставку надсилає обробник, що її взяв
+	LockedUntil *time.Time `json:"-"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Зв'язки
+	Notification *Notification `gorm:"foreignKey:NotificationID" json:"notification,omitempty"`
+}
+
+// BeforeCreate generates a new UUID for the delivery if not set
+func (d *NotificationDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
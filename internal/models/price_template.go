@@ -20,10 +20,12 @@ type PriceTemplate struct {
 	Duration     time.Duration  `json:"duration"`
 	TeamPayments datatypes.JSON `json:"team_payments" gorm:"type:jsonb;default:'[]'"`
 	DeadlineDays int            `json:"deadline_days" gorm:"default:180"`
-	Settings     datatypes.JSON `json:"settings" gorm:"type:jsonb;default:'{}'"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    *time.Time     `json:"deleted_at,omitempty" gorm:"index"`
+	// MaxSelections - кількість фото для ретуші, яку клієнт може обрати в галереї
+	MaxSelections int            `json:"max_selections" gorm:"default:0"`
+	Settings      datatypes.JSON `json:"settings" gorm:"type:jsonb;default:'{}'"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     *time.Time     `json:"deleted_at,omitempty" gorm:"index"`
 
 	// Зв'язки
 	User *User `json:"-" gorm:"foreignKey:UserID"`
@@ -31,27 +33,29 @@ type PriceTemplate struct {
 
 // PriceTemplatePublic представляє публічну інформацію про шаблон цін
 type PriceTemplatePublic struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	EventType    EventType `json:"event_type"`
-	Currency     string    `json:"currency"`
-	Price        float64   `json:"price"`
-	Deposit      float64   `json:"deposit"`
-	Description  string    `json:"description"`
-	DeadlineDays int       `json:"deadline_days"`
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	EventType     EventType `json:"event_type"`
+	Currency      string    `json:"currency"`
+	Price         float64   `json:"price"`
+	Deposit       float64   `json:"deposit"`
+	Description   string    `json:"description"`
+	DeadlineDays  int       `json:"deadline_days"`
+	MaxSelections int       `json:"max_selections"`
 }
 
 // ToPublic конвертує PriceTemplate в PriceTemplatePublic
 func (pt *PriceTemplate) ToPublic() PriceTemplatePublic {
 	return PriceTemplatePublic{
-		ID:           pt.ID,
-		Name:         pt.Name,
-		EventType:    pt.EventType,
-		Currency:     pt.Currency,
-		Price:        pt.Price,
-		Deposit:      pt.Deposit,
-		Description:  pt.Description,
-		DeadlineDays: pt.DeadlineDays,
+		ID:            pt.ID,
+		Name:          pt.Name,
+		EventType:     pt.EventType,
+		Currency:      pt.Currency,
+		Price:         pt.Price,
+		Deposit:       pt.Deposit,
+		Description:   pt.Description,
+		DeadlineDays:  pt.DeadlineDays,
+		MaxSelections: pt.MaxSelections,
 	}
 }
 
@@ -74,6 +78,9 @@ func (pt *PriceTemplate) Validate() error {
 	if pt.Deposit < 0 {
 		return ErrValidation{Field: "deposit", Message: "Deposit cannot be negative"}
 	}
+	if pt.MaxSelections < 0 {
+		return ErrValidation{Field: "max_selections", Message: "Max selections cannot be negative"}
+	}
 	if pt.Deposit > pt.Price {
 		return ErrValidation{Field: "deposit", Message: "Deposit cannot be greater than price"}
 	}
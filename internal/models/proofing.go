@@ -0,0 +1,76 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GalleryProof - позначка клієнта на фото в режимі відбору: обране та/або коментар
+type GalleryProof struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	GalleryID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_gallery_proofs_file" json:"gallery_id"`
+	FileID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_gallery_proofs_file" json:"file_id"`
+	Favorite  bool      `gorm:"not null;default:false" json:"favorite"`
+	Comment   string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Зв'язки
+	File *File `gorm:"foreignKey:FileID" json:"-"`
+}
+
+// BeforeCreate generates a new UUID for the proof if not set
+func (p *GalleryProof) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// ProofUpdate містить зміни позначки клієнта
+type ProofUpdate struct {
+	Favorite *bool   `json:"favorite,omitempty"`
+	Comment  *string `json:"comment,omitempty"`
+}
+
+// SelectionItem - фото з відбору клієнта для експорту
+type SelectionItem struct {
+	FileID    uuid.UUID `json:"file_id"`
+	FileName  string    `json:"file_name"`
+	Favorite  bool      `json:"favorite"`
+	Comment   string    `json:"comment,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Selection - відбір клієнта для ретуші
+type Selection struct {
+	Items         []SelectionItem `json:"items"`
+	Favorites     int             `json:"favorites"`
+	MaxSelections int             `json:"max_selections"`
+	SubmittedAt   *time.Time      `json:"submitted_at,omitempty"`
+}
+
+// FavoriteNames повертає імена обраних файлів
+func (s *Selection) FavoriteNames() []string {
+	names := make([]string, 0, s.Favorites)
+	for _, item := range s.Items {
+		if item.Favorite {
+			names = append(names, item.FileName)
+		}
+	}
+	return names
+}
+
+// LightroomFilter повертає рядок для фільтра Library > Text > Filename > Contains у Lightroom.
+// Розширення відкидаються, щоб фільтр знаходив і RAW, і JPEG з тим самим ім'ям.
+func (s *Selection) LightroomFilter() string {
+	names := s.FavoriteNames()
+	for i, name := range names {
+		names[i] = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return strings.Join(names, ", ")
+}
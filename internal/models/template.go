@@ -4,6 +4,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// TemplateTypeContract - шаблон договору з полями підстановки з бронювання
+	TemplateTypeContract = "contract"
+	// TemplateTypeEmail - шаблон транзакційного листа для події Event мовою Language
+	TemplateTypeEmail = "email"
 )
 
 // Template представляє шаблон документа або повідомлення
@@ -18,12 +26,16 @@ type Template struct {
 	IsDefault   bool              `json:"is_default"`
 	IsActive    bool              `json:"is_active" gorm:"default:true"`
 	Description string            `json:"description"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	// Event - подія, для якої надсилається лист (лише для шаблонів листів)
+	Event string `json:"event,omitempty"`
+	// Language - мова варіанта шаблону листа, як у UserSettings.Language
+	Language  string    `json:"language,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // BeforeCreate генерує UUID для нового шаблону
-func (t *Template) BeforeCreate() error {
+func (t *Template) BeforeCreate(tx *gorm.DB) error {
 	if t.ID == uuid.Nil {
 		t.ID = uuid.New()
 	}
@@ -31,7 +43,7 @@ func (t *Template) BeforeCreate() error {
 }
 
 // BeforeUpdate оновлює час модифікації перед оновленням
-func (t *Template) BeforeUpdate() error {
+func (t *Template) BeforeUpdate(tx *gorm.DB) error {
 	t.UpdatedAt = time.Now()
 	return nil
 }
@@ -45,9 +45,41 @@ type User struct {
 	Phone        string         `json:"phone"`
 	Role         string         `json:"role" gorm:"not null;default:'user'"`
 	Settings     datatypes.JSON `json:"settings" gorm:"type:jsonb"`
-	CreatedAt    time.Time      `json:"created_at" gorm:"not null"`
-	UpdatedAt    time.Time      `json:"updated_at" gorm:"not null"`
-	DeletedAt    *time.Time     `json:"-" gorm:"index"`
+	// Квота сховища: ліміт в ГБ та фактично використаний обсяг в байтах
+	StorageLimitGB   int   `json:"storage_limit_gb" gorm:"not null;default:100"`
+	StorageUsedBytes int64 `json:"storage_used_bytes" gorm:"not null;default:0"`
+	// Брендування публічної сторінки клієнта
+	GalleryTemplate    string         `json:"gallery_template"`
+	GalleryLogoURL     string         `json:"gallery_logo_url"`
+	GallerySocialLinks datatypes.JSON `json:"gallery_social_links" gorm:"type:jsonb"`
+	// TelegramChatID - чат з ботом TimeBride, підключений командою /start; порожній - бот не підключено
+	TelegramChatID string     `json:"-" gorm:"column:telegram_chat_id"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null"`
+	DeletedAt      *time.Time `json:"-" gorm:"index"`
+}
+
+// SocialLink представляє посилання студії (сайт, Instagram, Facebook)
+type SocialLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// GetSocialLinks повертає посилання студії для публічної сторінки
+func (u *User) GetSocialLinks() []SocialLink {
+	var links []SocialLink
+	if len(u.GallerySocialLinks) > 0 {
+		_ = json.Unmarshal(u.GallerySocialLinks, &links)
+	}
+	return links
+}
+
+// GetGalleryTemplate повертає шаблон галереї або шаблон за замовчуванням
+func (u *User) GetGalleryTemplate() string {
+	if IsValidGalleryTemplate(u.GalleryTemplate) {
+		return u.GalleryTemplate
+	}
+	return GalleryTemplateClassic
 }
 
 // UserSettings представляє налаштування користувача
@@ -58,6 +90,40 @@ type UserSettings struct {
 	DefaultCurrency  string            `json:"default_currency"`
 	CustomFields     map[string]string `json:"custom_fields"`
 	CalendarSettings CalendarSettings  `json:"calendar_settings"`
+	// NotificationSettings - які сповіщення і як часто надсилати
+	NotificationSettings UserNotificationSettings `json:"notification_settings"`
+	// Watermark - водяний знак на превʼю галерей до повної оплати
+	Watermark WatermarkSettings `json:"watermark"`
+	// Requisites - реквізити студії для договорів
+	Requisites StudioRequisites `json:"requisites"`
+}
+
+// StudioRequisites містить юридичні та платіжні реквізити студії
+type StudioRequisites struct {
+	LegalName string `json:"legal_name"`
+	// TaxID - ЄДРПОУ для юридичних осіб або РНОКПП для ФОП
+	TaxID   string `json:"tax_id"`
+	IBAN    string `json:"iban"`
+	Bank    string `json:"bank"`
+	Address string `json:"address"`
+}
+
+// WatermarkSettings містить налаштування водяного знака акаунта
+type WatermarkSettings struct {
+	Enabled bool `json:"enabled"`
+	// LogoFileID - файл логотипа (PNG з прозорістю або JPEG)
+	LogoFileID *uuid.UUID `json:"logo_file_id,omitempty"`
+	// Position - center, top-left, top-right, bottom-left, bottom-right або tile
+	Position string `json:"position"`
+	// Opacity - непрозорість від 0 до 1
+	Opacity float64 `json:"opacity"`
+	// Scale - ширина знака відносно ширини фото, від 0 до 1
+	Scale float64 `json:"scale"`
+}
+
+// IsActive перевіряє, чи потрібно накладати знак
+func (w *WatermarkSettings) IsActive() bool {
+	return w.Enabled && w.LogoFileID != nil
 }
 
 // CalendarSettings представляє налаштування календаря
@@ -87,6 +153,11 @@ func (u *User) ToPublic() *PublicUser {
 	}
 }
 
+// StorageLimitBytes повертає ліміт сховища користувача в байтах
+func (u *User) StorageLimitBytes() int64 {
+	return int64(u.StorageLimitGB) * 1024 * 1024 * 1024
+}
+
 // GetSettings повертає налаштування користувача
 func (u *User) GetSettings() (*UserSettings, error) {
 	if u.Settings == nil {
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// WebhookEvent визначає подію, про яку студія отримує вебхук
+type WebhookEvent string
+
+const (
+	// WebhookEventBookingCreated - створено бронювання
+	WebhookEventBookingCreated WebhookEvent = "booking.created"
+	// WebhookEventBookingStatusChanged - змінився статус бронювання
+	WebhookEventBookingStatusChanged WebhookEvent = "booking.status_changed"
+	// WebhookEventPaymentReceived - отримано оплату за бронювання
+	WebhookEventPaymentReceived WebhookEvent = "payment.received"
+	// WebhookEventGalleryDownloaded - клієнт завантажив файл або архів галереї
+	WebhookEventGalleryDownloaded WebhookEvent = "gallery.downloaded"
+	// WebhookEventPing - тестовий запит; надсилається лише вручну і не потребує підписки
+	WebhookEventPing WebhookEvent = "ping"
+)
+
+// WebhookEvents - події, на які можна підписатися
+var WebhookEvents = []WebhookEvent{
+	WebhookEventBookingCreated,
+	WebhookEventBookingStatusChanged,
+	WebhookEventPaymentReceived,
+	WebhookEventGalleryDownloaded,
+}
+
+// IsValid перевіряє, чи на подію можна підписатися
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook - підписка студії на події: запити надсилаються на URL з підписом HMAC-SHA256 секретом підписки
+type Webhook struct {
+	ID     uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	URL    string         `gorm:"type:text;not null" json:"url"`
+	Secret string         `gorm:"type:varchar(100);not null" json:"secret"`
+	Events []WebhookEvent `gorm:"type:jsonb;serializer:json;not null" json:"events"`
+	// Active - вимкнена підписка не отримує нових подій; тестовий запит надсилається завжди
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribed перевіряє, чи підписка отримує подію
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate generates a new UUID for the webhook if not set
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookDelivery - запит вебхука в черзі та журналі доставки. Доставки зі статусом failed
+// вичерпали спроби і залишаються в журналі як недоставлені, доки їх не надіслати повторно.
+type WebhookDelivery struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	WebhookID uuid.UUID `gorm:"type:uuid;not null;index" json:"webhook_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	// EventID - ідентифікатор події; однаковий для всіх підписок і повторних надсилань,
+	// тож отримувач може відкидати дублікати
+	EventID uuid.UUID `gorm:"type:uuid;not null" json:"event_id"`
+	// ReplayOf - первинна доставка, якщо це повторне надсилання з журналу
+	ReplayOf *uuid.UUID     `gorm:"type:uuid" json:"replay_of,omitempty"`
+	Event    WebhookEvent   `gorm:"type:varchar(50);not null" json:"event"`
+	Payload  datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
+	Status   DeliveryStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts int            `gorm:"not null;default:0" json:"attempts"`
+	// ResponseStatus - HTTP-статус останньої відповіді; 0 - відповіді не було
+	ResponseStatus int    `gorm:"not null;default:0" json:"response_status"`
+	LastError      string `gorm:"type:text" json:"last_error,omitempty"`
+	// NextAttemptAt - час наступної спроби для доставок у стані pending
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate generates a new UUID for the delivery if not set
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package pdf формує PDF-документи (договори, акти) з тексту.
+// Шрифти Go вбудовані в бінарний файл і містять кирилицю, тож документ
+// не залежить від шрифтів системи.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	fontFamily = "Go"
+	// lineHeight - висота рядка основного тексту, мм
+	lineHeight = 5.5
+)
+
+// Document - PDF-документ формату A4
+type Document struct {
+	pdf *fpdf.Fpdf
+	// imported - кількість сторінок, перенесених з наявного PDF; на них не додається нумерація
+	imported int
+}
+
+// New створює документ із заголовком у метаданих та нумерацією сторінок
+func New(title string) *Document {
+	d := newDocument(title)
+	d.pdf.AddPage()
+	return d
+}
+
+// newDocument створює документ без сторінок
+func newDocument(title string) *Document {
+	doc := fpdf.New("P", "mm", "A4", "")
+	d := &Document{pdf: doc}
+	doc.SetTitle(title, true)
+	doc.SetCreator("TimeBride", true)
+	doc.SetMargins(20, 20, 20)
+	doc.SetAutoPageBreak(true, 20)
+	doc.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	doc.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	doc.AliasNbPages("{nb}")
+	doc.SetFooterFunc(func() {
+		if doc.PageNo() <= d.imported {
+			return
+		}
+		doc.SetY(-15)
+		doc.SetFont(fontFamily, "", 8)
+		doc.SetTextColor(120, 120, 120)
+		doc.CellFormat(0, 10, fmt.Sprintf("%d / {nb}", doc.PageNo()), "", 0, "C", false, 0, "")
+		doc.SetTextColor(0, 0, 0)
+	})
+	return d
+}
+
+// Text додає текст з простою розміткою: рядок "# " - заголовок, "## " - підзаголовок,
+// порожній рядок - відступ між абзацами
+func (d *Document) Text(content string) {
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "):
+			d.Heading(strings.TrimPrefix(line, "# "), 14)
+		case strings.HasPrefix(line, "## "):
+			d.Heading(strings.TrimPrefix(line, "## "), 12)
+		case strings.TrimSpace(line) == "":
+			d.pdf.Ln(lineHeight / 2)
+		default:
+			d.pdf.SetFont(fontFamily, "", 11)
+			d.pdf.MultiCell(0, lineHeight, line, "", "J", false)
+		}
+	}
+}
+
+// Heading додає заголовок вказаного розміру
+func (d *Document) Heading(text string, size float64) {
+	d.pdf.Ln(lineHeight / 2)
+	d.pdf.SetFont(fontFamily, "B", size)
+	d.pdf.MultiCell(0, size*0.5, text, "", "C", false)
+	d.pdf.Ln(lineHeight / 2)
+}
+
+// Field додає рядок "назва: значення" з виділеною назвою
+func (d *Document) Field(name, value string) {
+	d.pdf.SetFont(fontFamily, "B", 10)
+	d.pdf.CellFormat(50, lineHeight, name, "", 0, "L", false, 0, "")
+	d.pdf.SetFont(fontFamily, "", 10)
+	d.pdf.MultiCell(0, lineHeight, value, "", "L", false)
+}
+
+// Image додає PNG або JPEG шириною width мм; name має бути унікальним у документі
+func (d *Document) Image(name string, data []byte, imageType string, width float64) error {
+	options := fpdf.ImageOptions{ImageType: imageType, ReadDpi: false}
+	info := d.pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(data))
+	if err := d.pdf.Error(); err != nil {
+		return fmt.Errorf("failed to embed image: %w", err)
+	}
+	height := width * info.Height() / info.Width()
+	d.pdf.ImageOptions(name, d.pdf.GetX(), d.pdf.GetY(), width, height, true, options, 0, "")
+	return nil
+}
+
+// NewPage починає нову сторінку
+func (d *Document) NewPage() {
+	d.pdf.AddPage()
+}
+
+// Bytes повертає вміст PDF
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Render перетворює текст документа на PDF
+func Render(title, content string) ([]byte, error) {
+	doc := New(title)
+	doc.Text(content)
+	return doc.Bytes()
+}
@@ -0,0 +1,59 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/gofpdi"
+)
+
+var ErrInvalidPDF = errors.New("invalid pdf document")
+
+// pointsPerMM - кількість типографських пунктів в одному міліметрі
+const pointsPerMM = 72 / 25.4
+
+// Import створює документ зі сторінок наявного PDF без змін їхнього вмісту.
+// Сторінки, додані після імпорту (NewPage), отримують нумерацію документа.
+func Import(title string, original []byte) (doc *Document, err error) {
+	// Бібліотека імпорту панікує на пошкоджених файлах
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, fmt.Errorf("%w: %v", ErrInvalidPDF, r)
+		}
+	}()
+
+	d := newDocument(title)
+	importer := gofpdi.NewImporter()
+	var source io.ReadSeeker = bytes.NewReader(original)
+
+	first := importer.ImportPageFromStream(d.pdf, &source, 1, "/MediaBox")
+	sizes := importer.GetPageSizes()
+	if len(sizes) == 0 {
+		return nil, ErrInvalidPDF
+	}
+
+	for page := 1; page <= len(sizes); page++ {
+		tpl := first
+		if page > 1 {
+			tpl = importer.ImportPageFromStream(d.pdf, &source, page, "/MediaBox")
+		}
+		box := sizes[page]["/MediaBox"]
+		width, height := box["w"]/pointsPerMM, box["h"]/pointsPerMM
+		orientation := "P"
+		if width > height {
+			orientation = "L"
+		}
+
+		d.imported = page
+		d.pdf.AddPageFormat(orientation, fpdf.SizeType{Wd: width, Ht: height})
+		importer.UseImportedTemplate(d.pdf, tpl, 0, 0, width, height)
+	}
+
+	if err := d.pdf.Error(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	return d, nil
+}
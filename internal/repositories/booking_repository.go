@@ -45,8 +45,40 @@ type BookingRepository interface {
 
 	// GetByClientID retrieves bookings by client ID
 	GetByClientID(ctx context.Context, clientID uuid.UUID) ([]*models.Booking, error)
+
+	// SetDeliveryPageURL updates the public gallery link of a booking
+	SetDeliveryPageURL(ctx context.Context, id uuid.UUID, url string) error
+
+	// Transition changes the status of a booking only if it is currently in status from.
+	// It reports whether the status was changed.
+	Transition(ctx context.Context, id uuid.UUID, from, to models.BookingStatus) (bool, error)
+
+	// ListByDeadline retrieves bookings in progress whose delivery deadline is within [from, to],
+	// with clients, ordered by deadline; uuid.Nil userID lists bookings of all users
+	ListByDeadline(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Booking, error)
+
+	// ListByEventDate retrieves bookings of a user with events within [from, to), except cancelled ones,
+	// with clients, ordered by event date
+	ListByEventDate(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Booking, error)
+
+	// ListUnpaid retrieves bookings of a user with events before the given time that still have
+	// a balance to pay, with clients, ordered by event date
+	ListUnpaid(ctx context.Context, userID uuid.UUID, before time.Time) ([]*models.Booking, error)
+
+	// ListInquiries retrieves draft and pending bookings of a user created after the given time, with clients
+	ListInquiries(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Booking, error)
+
+	// RefreshOverdue sets is_overdue for bookings in progress past their delivery deadline
+	// and clears it for the rest
+	RefreshOverdue(ctx context.Context, now time.Time) error
 }
 
+// deadlineExpr - термін віддачі матеріалів бронювання в SQL, як у models.Booking.Deadline
+const deadlineExpr = "event_date + deadline_days * INTERVAL '1 day'"
+
+// inProgressStatuses - статуси проєктів у роботі, для яких стежать за терміном віддачі
+var inProgressStatuses = []models.BookingStatus{models.BookingStatusBooked, models.BookingStatusEditing}
+
 type bookingRepository struct {
 	baseRepository[models.Booking]
 }
@@ -59,12 +91,12 @@ func NewBookingRepository(db *gorm.DB) BookingRepository {
 }
 
 func (r *bookingRepository) Create(ctx context.Context, booking *models.Booking) error {
-	return r.db.WithContext(ctx).Create(booking).Error
+	return conn(ctx, r.db).Create(booking).Error
 }
 
 func (r *bookingRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Booking, error) {
 	var booking models.Booking
-	if err := r.db.WithContext(ctx).First(&booking, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&booking, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrBookingNotFound
 		}
@@ -75,7 +107,7 @@ func (r *bookingRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 
 func (r *bookingRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&bookings).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&bookings).Error; err != nil {
 		return nil, err
 	}
 	return bookings, nil
@@ -83,7 +115,7 @@ func (r *bookingRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 func (r *bookingRepository) GetByDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("user_id = ? AND start_time BETWEEN ? AND ?", userID, start, end).
 		Find(&bookings).Error; err != nil {
 		return nil, err
@@ -93,7 +125,7 @@ func (r *bookingRepository) GetByDateRange(ctx context.Context, userID uuid.UUID
 
 func (r *bookingRepository) GetByStatus(ctx context.Context, userID uuid.UUID, status string) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("user_id = ? AND status = ?", userID, status).
 		Find(&bookings).Error; err != nil {
 		return nil, err
@@ -103,7 +135,7 @@ func (r *bookingRepository) GetByStatus(ctx context.Context, userID uuid.UUID, s
 
 func (r *bookingRepository) GetByEventType(ctx context.Context, userID uuid.UUID, eventType string) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("user_id = ? AND event_type = ?", userID, eventType).
 		Find(&bookings).Error; err != nil {
 		return nil, err
@@ -112,16 +144,16 @@ func (r *bookingRepository) GetByEventType(ctx context.Context, userID uuid.UUID
 }
 
 func (r *bookingRepository) Update(ctx context.Context, booking *models.Booking) error {
-	return r.db.WithContext(ctx).Save(booking).Error
+	return conn(ctx, r.db).Save(booking).Error
 }
 
 func (r *bookingRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Booking{}, "id = ?", id).Error
+	return conn(ctx, r.db).Delete(&models.Booking{}, "id = ?", id).Error
 }
 
 func (r *bookingRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	query := r.db.WithContext(ctx)
+	query := conn(ctx, r.db)
 
 	// Apply filters
 	for key, value := range filter {
@@ -136,7 +168,7 @@ func (r *bookingRepository) List(ctx context.Context, filter map[string]interfac
 
 func (r *bookingRepository) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(&models.Booking{})
+	query := conn(ctx, r.db).Model(&models.Booking{})
 
 	// Apply filters
 	for key, value := range filter {
@@ -152,7 +184,7 @@ func (r *bookingRepository) Count(ctx context.Context, filter map[string]interfa
 // CountUpcoming counts upcoming bookings
 func (r *bookingRepository) CountUpcoming(ctx context.Context, userID uuid.UUID) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Booking{}).
+	if err := conn(ctx, r.db).Model(&models.Booking{}).
 		Where("user_id = ? AND start_time > ?", userID, time.Now()).
 		Count(&count).Error; err != nil {
 		return 0, err
@@ -163,7 +195,7 @@ func (r *bookingRepository) CountUpcoming(ctx context.Context, userID uuid.UUID)
 // CountInDateRange counts bookings in a date range
 func (r *bookingRepository) CountInDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Booking{}).
+	if err := conn(ctx, r.db).Model(&models.Booking{}).
 		Where("user_id = ? AND start_time BETWEEN ? AND ?", userID, start, end).
 		Count(&count).Error; err != nil {
 		return 0, err
@@ -174,7 +206,7 @@ func (r *bookingRepository) CountInDateRange(ctx context.Context, userID uuid.UU
 // GetRecent retrieves recent bookings
 func (r *bookingRepository) GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -187,8 +219,81 @@ func (r *bookingRepository) GetRecent(ctx context.Context, userID uuid.UUID, lim
 // GetByClientID retrieves bookings by client ID
 func (r *bookingRepository) GetByClientID(ctx context.Context, clientID uuid.UUID) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	if err := r.db.WithContext(ctx).Where("client_id = ?", clientID).Find(&bookings).Error; err != nil {
+	if err := conn(ctx, r.db).Where("client_id = ?", clientID).Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// SetDeliveryPageURL updates the public gallery link of a booking
+func (r *bookingRepository) SetDeliveryPageURL(ctx context.Context, id uuid.UUID, url string) error {
+	return conn(ctx, r.db).Model(&models.Booking{}).
+		Where("id = ?", id).
+		Update("delivery_page_url", url).Error
+}
+
+func (r *bookingRepository) Transition(ctx context.Context, id uuid.UUID, from, to models.BookingStatus) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Booking{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *bookingRepository) ListByDeadline(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Booking, error) {
+	query := conn(ctx, r.db).
+		Preload("Client").
+		Where("deleted_at IS NULL AND status IN ? AND deadline_days > 0", inProgressStatuses).
+		Where(deadlineExpr+" BETWEEN ? AND ?", from, to)
+	if userID != uuid.Nil {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var bookings []*models.Booking
+	if err := query.Order(deadlineExpr).Find(&bookings).Error; err != nil {
 		return nil, err
 	}
 	return bookings, nil
 }
+
+func (r *bookingRepository) RefreshOverdue(ctx context.Context, now time.Time) error {
+	overdue := "(status IN ? AND deadline_days > 0 AND " + deadlineExpr + " < ?)"
+	return conn(ctx, r.db).
+		Model(&models.Booking{}).
+		Where("deleted_at IS NULL AND is_overdue <> "+overdue, inProgressStatuses, now).
+		UpdateColumn("is_overdue", gorm.Expr(overdue, inProgressStatuses, now)).Error
+}
+
+func (r *bookingRepository) ListByEventDate(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Booking, error) {
+	var bookings []*models.Booking
+	err := conn(ctx, r.db).
+		Preload("Client").
+		Where("user_id = ? AND deleted_at IS NULL AND status <> ?", userID, models.BookingStatusCancelled).
+		Where("event_date >= ? AND event_date < ?", from, to).
+		Order("event_date").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) ListUnpaid(ctx context.Context, userID uuid.UUID, before time.Time) ([]*models.Booking, error) {
+	var bookings []*models.Booking
+	err := conn(ctx, r.db).
+		Preload("Client").
+		Where("user_id = ? AND deleted_at IS NULL AND event_date < ?", userID, before).
+		Where("status NOT IN ?", []models.BookingStatus{models.BookingStatusDraft, models.BookingStatusCancelled}).
+		Where("payment_status IS DISTINCT FROM ?", models.PaymentStatusPaid).
+		Where("price_total + COALESCE(price_extra, 0) - price_prepayment > 0").
+		Order("event_date").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) ListInquiries(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Booking, error) {
+	var bookings []*models.Booking
+	err := conn(ctx, r.db).
+		Preload("Client").
+		Where("user_id = ? AND deleted_at IS NULL AND created_at > ?", userID, since).
+		Where("status IN ?", []models.BookingStatus{models.BookingStatusDraft, models.BookingStatusPending}).
+		Order("created_at").
+		Find(&bookings).Error
+	return bookings, err
+}
@@ -31,7 +31,7 @@ func NewClientRepository(db *gorm.DB) ClientRepository {
 // GetByUserID отримує список клієнтів користувача
 func (r *clientRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Client, error) {
 	var clients []*models.Client
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&clients).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&clients).Error; err != nil {
 		return nil, err
 	}
 	return clients, nil
@@ -40,7 +40,7 @@ func (r *clientRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([
 // GetCategories отримує всі категорії клієнтів
 func (r *clientRepository) GetCategories(ctx context.Context, userID uuid.UUID) ([]string, error) {
 	var categories []string
-	err := r.db.WithContext(ctx).Model(&models.Client{}).
+	err := conn(ctx, r.db).Model(&models.Client{}).
 		Where("user_id = ?", userID).
 		Distinct().
 		Pluck("category", &categories).
@@ -51,7 +51,7 @@ func (r *clientRepository) GetCategories(ctx context.Context, userID uuid.UUID)
 // GetSources отримує всі джерела клієнтів
 func (r *clientRepository) GetSources(ctx context.Context, userID uuid.UUID) ([]string, error) {
 	var sources []string
-	err := r.db.WithContext(ctx).Model(&models.Client{}).
+	err := conn(ctx, r.db).Model(&models.Client{}).
 		Where("user_id = ?", userID).
 		Distinct().
 		Pluck("source", &sources).
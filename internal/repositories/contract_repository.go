@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+var (
+	ErrContractNotFound    = errors.New("contract not found")
+	ErrContractNotSignable = errors.New("contract is not awaiting signature")
+	ErrContractHasSigned   = errors.New("contract has a signed version and cannot be deleted")
+)
+
+// ContractRepository handles versioned booking contracts
+type ContractRepository interface {
+	// CreateVersion stores the contract as the next version of its booking contract
+	// and points the booking to the new file
+	CreateVersion(ctx context.Context, contract *models.Contract) error
+
+	// GetByID retrieves a contract version with its file
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Contract, error)
+
+	// ListByBooking retrieves all versions of a booking contract, newest first
+	ListByBooking(ctx context.Context, bookingID uuid.UUID) ([]*models.Contract, error)
+
+	// GetLatest retrieves the newest version of a booking contract
+	GetLatest(ctx context.Context, bookingID uuid.UUID) (*models.Contract, error)
+
+	// MarkSent makes a draft version available to the client for signing
+	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+
+	// List retrieves the latest contract version of every booking of the user,
+	// with the booking and its client, newest events first
+	List(ctx context.Context, userID uuid.UUID, opts models.ContractListOptions) ([]*models.Contract, error)
+
+	// DeleteByBooking removes all versions of a booking contract and clears the booking link.
+	// It returns the IDs of the files the versions referenced. A contract with a signed version
+	// is kept together with its signatures and ErrContractHasSigned is returned.
+	DeleteByBooking(ctx context.Context, bookingID uuid.UUID) ([]uuid.UUID, error)
+
+	// Sign records the client's signature and the signed copy of a sent version.
+	// The booking is confirmed by the caller in the same UnitOfWork transaction.
+	Sign(ctx context.Context, contract *models.Contract, signature *models.ContractSignature) error
+}
+
+type contractRepository struct {
+	db *gorm.DB
+}
+
+// NewContractRepository creates a new instance of ContractRepository
+func NewContractRepository(db *gorm.DB) ContractRepository {
+	return &contractRepository{db: db}
+}
+
+func (r *contractRepository) CreateVersion(ctx context.Context, contract *models.Contract) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Паралельне формування впирається в унікальний індекс (booking_id, version)
+		var latest int
+		err := tx.Model(&models.Contract{}).
+			Where("booking_id = ?", contract.BookingID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		contract.Version = latest + 1
+		if err := tx.Create(contract).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Booking{}).
+			Where("id = ?", contract.BookingID).
+			Update("contract_file_url", contract.FilePath()).Error
+	})
+}
+
+func (r *contractRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Contract, error) {
+	var contract models.Contract
+	err := conn(ctx, r.db).
+		Preload("File").
+		Preload("SignedFile").
+		Preload("Signature").
+		First(&contract, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContractNotFound
+		}
+		return nil, err
+	}
+	return &contract, nil
+}
+
+func (r *contractRepository) ListByBooking(ctx context.Context, bookingID uuid.UUID) ([]*models.Contract, error) {
+	var contracts []*models.Contract
+	err := conn(ctx, r.db).
+		Preload("File").
+		Preload("SignedFile").
+		Preload("Signature").
+		Where("booking_id = ?", bookingID).
+		Order("version DESC").
+		Find(&contracts).Error
+	return contracts, err
+}
+
+func (r *contractRepository) GetLatest(ctx context.Context, bookingID uuid.UUID) (*models.Contract, error) {
+	var contract models.Contract
+	err := conn(ctx, r.db).
+		Preload("File").
+		Preload("SignedFile").
+		Preload("Signature").
+		Where("booking_id = ?", bookingID).
+		Order("version DESC").
+		First(&contract).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContractNotFound
+		}
+		return nil, err
+	}
+	return &contract, nil
+}
+
+func (r *contractRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.Contract{}).
+		Where("id = ? AND status = ?", id, models.ContractStatusDraft).
+		Updates(map[string]interface{}{
+			"status":  models.ContractStatusSent,
+			"sent_at": sentAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrContractNotFound
+	}
+	return nil
+}
+
+func (r *contractRepository) Sign(ctx context.Context, contract *models.Contract, signature *models.ContractSignature) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Умова на статус не дає підписати версію двічі при паралельних запитах
+		result := tx.Model(&models.Contract{}).
+			Where("id = ? AND status = ?", contract.ID, models.ContractStatusSent).
+			Updates(map[string]interface{}{
+				"status":         models.ContractStatusSigned,
+				"signed_file_id": contract.SignedFileID,
+				"signed_at":      signature.SignedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrContractNotSignable
+		}
+
+		signature.ContractID = contract.ID
+		if err := tx.Create(signature).Error; err != nil {
+			return err
+		}
+
+		contract.Status = models.ContractStatusSigned
+		contract.SignedAt = &signature.SignedAt
+		contract.Signature = signature
+		return nil
+	})
+}
+
+func (r *contractRepository) List(ctx context.Context, userID uuid.UUID, opts models.ContractListOptions) ([]*models.Contract, error) {
+	query := conn(ctx, r.db).
+		Joins("JOIN bookings ON bookings.id = contracts.booking_id").
+		Joins("JOIN clients ON clients.id = bookings.client_id").
+		Where("contracts.user_id = ?", userID).
+		Where("contracts.version = (SELECT MAX(v.version) FROM contracts v WHERE v.booking_id = contracts.booking_id)")
+
+	if opts.ClientID != nil {
+		query = query.Where("bookings.client_id = ?", *opts.ClientID)
+	}
+	if opts.Search != "" {
+		query = query.Where("clients.full_name ILIKE ?", "%"+opts.Search+"%")
+	}
+	if opts.Status != "" {
+		query = query.Where("contracts.status = ?", opts.Status)
+	}
+	if opts.EventFrom != nil {
+		query = query.Where("bookings.event_date >= ?", *opts.EventFrom)
+	}
+	if opts.EventTo != nil {
+		query = query.Where("bookings.event_date < ?", *opts.EventTo)
+	}
+
+	var contracts []*models.Contract
+	err := query.
+		Preload("Booking").
+		Preload("Booking.Client").
+		Preload("File").
+		Preload("SignedFile").
+		Preload("Signature", func(db *gorm.DB) *gorm.DB {
+			// Зображення підпису в таблиці не потрібне
+			return db.Omit("image")
+		}).
+		Order("bookings.event_date DESC").
+		Find(&contracts).Error
+	return contracts, err
+}
+
+func (r *contractRepository) DeleteByBooking(ctx context.Context, bookingID uuid.UUID) ([]uuid.UUID, error) {
+	var fileIDs []uuid.UUID
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Блокування не дає клієнту підписати версію, поки договір видаляється
+		var contracts []*models.Contract
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("booking_id = ?", bookingID).
+			Find(&contracts).Error; err != nil {
+			return err
+		}
+		if len(contracts) == 0 {
+			return ErrContractNotFound
+		}
+
+		for _, contract := range contracts {
+			if contract.Status == models.ContractStatusSigned {
+				return ErrContractHasSigned
+			}
+		}
+		for _, contract := range contracts {
+			fileIDs = append(fileIDs, contract.FileID)
+			if contract.SignedFileID != nil {
+				fileIDs = append(fileIDs, *contract.SignedFileID)
+			}
+		}
+		if err := tx.Where("booking_id = ?", bookingID).Delete(&models.Contract{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Booking{}).
+			Where("id = ?", bookingID).
+			Update("contract_file_url", "").Error
+	})
+	return fileIDs, err
+}
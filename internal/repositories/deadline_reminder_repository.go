@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+// DeadlineReminderRepository records reminders about booking delivery deadlines
+type DeadlineReminderRepository interface {
+	// Claim stores the reminder and reports whether it is new;
+	// false means the same reminder for the same deadline was already sent
+	Claim(ctx context.Context, reminder *models.DeadlineReminder) (bool, error)
+
+	// Release deletes a claimed reminder that could not be sent, so the next run retries it
+	Release(ctx context.Context, reminder *models.DeadlineReminder) error
+}
+
+type deadlineReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewDeadlineReminderRepository creates a new instance of DeadlineReminderRepository
+func NewDeadlineReminderRepository(db *gorm.DB) DeadlineReminderRepository {
+	return &deadlineReminderRepository{db: db}
+}
+
+func (r *deadlineReminderRepository) Claim(ctx context.Context, reminder *models.DeadlineReminder) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reminder)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *deadlineReminderRepository) Release(ctx context.Context, reminder *models.DeadlineReminder) error {
+	return conn(ctx, r.db).Delete(&models.DeadlineReminder{}, "id = ?", reminder.ID).Error
+}
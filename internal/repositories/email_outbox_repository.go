@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+// EmailOutboxRepository handles the queue of outgoing emails
+type EmailOutboxRepository interface {
+	// Create queues a new email
+	Create(ctx context.Context, email *models.OutboxEmail) error
+
+	// Claim returns pending emails whose next attempt is due and postpones them by lease,
+	// so concurrent workers do not send the same email twice
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxEmail, error)
+
+	// Update saves the status, attempts and schedule of an email
+	Update(ctx context.Context, email *models.OutboxEmail) error
+}
+
+type emailOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailOutboxRepository creates a new instance of EmailOutboxRepository
+func NewEmailOutboxRepository(db *gorm.DB) EmailOutboxRepository {
+	return &emailOutboxRepository{db: db}
+}
+
+func (r *emailOutboxRepository) Create(ctx context.Context, email *models.OutboxEmail) error {
+	return conn(ctx, r.db).Create(email).Error
+}
+
+func (r *emailOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxEmail, error) {
+	var emails []*models.OutboxEmail
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&emails).Error; err != nil {
+			return err
+		}
+		if len(emails) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(emails))
+		for i, email := range emails {
+			ids[i] = email.ID
+		}
+		return tx.Model(&models.OutboxEmail{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+func (r *emailOutboxRepository) Update(ctx context.Context, email *models.OutboxEmail) error {
+	return conn(ctx, r.db).
+		Model(&models.OutboxEmail{}).
+		Where("id = ?", email.ID).
+		Updates(map[string]interface{}{
+			"status":          email.Status,
+			"attempts":        email.Attempts,
+			"last_error":      email.LastError,
+			"next_attempt_at": email.NextAttemptAt,
+			"sent_at":         email.SentAt,
+		}).Error
+}
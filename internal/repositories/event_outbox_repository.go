@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+// EventOutboxRepository handles the outbox of domain events. Events are added in the
+// UnitOfWork transaction of the change that caused them.
+type EventOutboxRepository interface {
+	// Add records domain events; called inside UnitOfWork.Do together with the change itself
+	Add(ctx context.Context, events []*models.DomainEvent) error
+
+	// Claim returns pending events whose next attempt is due, oldest first, and postpones them
+	// by lease, so concurrent workers do not handle the same event twice
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.DomainEvent, error)
+
+	// Update saves the status, handled subscribers, attempts and schedule of an event
+	Update(ctx context.Context, event *models.DomainEvent) error
+}
+
+type eventOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewEventOutboxRepository creates a new instance of EventOutboxRepository
+func NewEventOutboxRepository(db *gorm.DB) EventOutboxRepository {
+	return &eventOutboxRepository{db: db}
+}
+
+func (r *eventOutboxRepository) Add(ctx context.Context, events []*models.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(events).Error
+}
+
+func (r *eventOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.DomainEvent, error) {
+	var events []*models.DomainEvent
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.EventStatusPending, now).
+			Order("created_at").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		return tx.Model(&models.DomainEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventOutboxRepository) Update(ctx context.Context, event *models.DomainEvent) error {
+	// Серіалізатор поля не застосовується до оновлення через map
+	handled, err := json.Marshal(event.Handled)
+	if err != nil {
+		return err
+	}
+	return conn(ctx, r.db).
+		Model(&models.DomainEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"status":          event.Status,
+			"handled":         datatypes.JSON(handled),
+			"attempts":        event.Attempts,
+			"last_error":      event.LastError,
+			"next_attempt_at": event.NextAttemptAt,
+			"processed_at":    event.ProcessedAt,
+		}).Error
+}
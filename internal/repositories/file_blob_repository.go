@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+var (
+	ErrFileBlobNotFound = errors.New("file blob not found")
+)
+
+// acquireAttempts - скільки разів Acquire повторює вставку, якщо blob видаляється паралельно
+const acquireAttempts = 3
+
+// FileBlobRepository handles reference-counted content-addressed blobs
+type FileBlobRepository interface {
+	// Acquire adds a reference to the blob with the given hash, creating it when missing.
+	// The returned flag is true when the blob was created by this call.
+	Acquire(ctx context.Context, blob *models.FileBlob) (*models.FileBlob, bool, error)
+
+	// Release removes a reference and deletes the blob row when it was the last one.
+	// The returned flag is true when the blob was deleted. The row stays locked until the
+	// surrounding UnitOfWork commits, so content can be removed safely inside it.
+	Release(ctx context.Context, userID uuid.UUID, hash string) (*models.FileBlob, bool, error)
+
+	// GetByHash retrieves a blob by owner and content hash
+	GetByHash(ctx context.Context, userID uuid.UUID, hash string) (*models.FileBlob, error)
+}
+
+type fileBlobRepository struct {
+	db *gorm.DB
+}
+
+// NewFileBlobRepository creates a new instance of FileBlobRepository
+func NewFileBlobRepository(db *gorm.DB) FileBlobRepository {
+	return &fileBlobRepository{db: db}
+}
+
+func (r *fileBlobRepository) Acquire(ctx context.Context, blob *models.FileBlob) (*models.FileBlob, bool, error) {
+	var result models.FileBlob
+	created := false
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Паралельний Release може видалити рядок між вставкою та оновленням,
+		// тоді наступна вставка створює blob заново
+		for attempt := 0; attempt < acquireAttempts; attempt++ {
+			blob.RefCount = 1
+			insert := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "hash"}},
+				DoNothing: true,
+			}).Create(blob)
+			if insert.Error != nil {
+				return insert.Error
+			}
+			if insert.RowsAffected == 1 {
+				created = true
+				result = *blob
+				return nil
+			}
+
+			// Blob вже існує - додаємо посилання
+			update := tx.Model(&models.FileBlob{}).
+				Where("user_id = ? AND hash = ?", blob.UserID, blob.Hash).
+				UpdateColumn("ref_count", gorm.Expr("ref_count + 1"))
+			if update.Error != nil {
+				return update.Error
+			}
+			if update.RowsAffected == 1 {
+				return tx.Where("user_id = ? AND hash = ?", blob.UserID, blob.Hash).First(&result).Error
+			}
+		}
+		return fmt.Errorf("blob %s is being released concurrently", blob.Hash)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &result, created, nil
+}
+
+func (r *fileBlobRepository) Release(ctx context.Context, userID uuid.UUID, hash string) (*models.FileBlob, bool, error) {
+	var blob models.FileBlob
+	removed := false
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND hash = ?", userID, hash).
+			First(&blob).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFileBlobNotFound
+			}
+			return err
+		}
+
+		if blob.RefCount <= 1 {
+			removed = true
+			return tx.Delete(&models.FileBlob{}, "id = ?", blob.ID).Error
+		}
+
+		blob.RefCount--
+		return tx.Model(&models.FileBlob{}).
+			Where("id = ?", blob.ID).
+			UpdateColumn("ref_count", blob.RefCount).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &blob, removed, nil
+}
+
+func (r *fileBlobRepository) GetByHash(ctx context.Context, userID uuid.UUID, hash string) (*models.FileBlob, error) {
+	var blob models.FileBlob
+	if err := conn(ctx, r.db).Where("user_id = ? AND hash = ?", userID, hash).First(&blob).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileBlobNotFound
+		}
+		return nil, err
+	}
+	return &blob, nil
+}
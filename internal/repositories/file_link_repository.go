@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"timebride/internal/models"
+)
+
+var (
+	ErrFileLinkNotFound  = errors.New("file link not found")
+	ErrFileLinkExhausted = errors.New("file link download limit reached")
+)
+
+// FileLinkRepository handles database operations for file download links
+type FileLinkRepository interface {
+	// Create stores a new download link
+	Create(ctx context.Context, link *models.FileLink) error
+
+	// GetByID retrieves a download link by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*models.FileLink, error)
+
+	// Consume atomically registers a download, failing when the limit is reached
+	Consume(ctx context.Context, id uuid.UUID) error
+}
+
+type fileLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewFileLinkRepository creates a new instance of FileLinkRepository
+func NewFileLinkRepository(db *gorm.DB) FileLinkRepository {
+	return &fileLinkRepository{db: db}
+}
+
+func (r *fileLinkRepository) Create(ctx context.Context, link *models.FileLink) error {
+	return conn(ctx, r.db).Create(link).Error
+}
+
+func (r *fileLinkRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.FileLink, error) {
+	var link models.FileLink
+	if err := conn(ctx, r.db).First(&link, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *fileLinkRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	// Лічильник збільшується однією умовною операцією, тож паралельні запити
+	// не можуть перевищити ліміт
+	result := conn(ctx, r.db).Model(&models.FileLink{}).
+		Where("id = ? AND expires_at > NOW() AND (max_downloads = 0 OR downloads < max_downloads)", id).
+		UpdateColumn("downloads", gorm.Expr("downloads + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFileLinkExhausted
+	}
+	return nil
+}
@@ -2,12 +2,14 @@ package repositories
 
 import (
 	"context"
-	"sync"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"timebride/internal/cache"
 	"timebride/internal/models"
 )
 
@@ -21,119 +23,151 @@ type FileRepository interface {
 	List(ctx context.Context, filter map[string]interface{}) ([]*models.File, error)
 	BatchCreate(ctx context.Context, files []*models.File) error
 	BatchDelete(ctx context.Context, ids []uuid.UUID) error
+
+	// Search повертає файли користувача за фільтрами метаданих
+	Search(ctx context.Context, userID uuid.UUID, opts models.FileSearchOptions) ([]*models.File, error)
+
+	// UpdateScanResult зберігає результат перевірки на шкідливе ПЗ
+	UpdateScanResult(ctx context.Context, id uuid.UUID, status models.ScanStatus, signature string) error
+	// ListUnscanned повертає файли, що очікують перевірки або перевірка яких не вдалася
+	ListUnscanned(ctx context.Context, before time.Time, limit int) ([]*models.File, error)
+	// ListByPath повертає файли, що посилаються на вказаний шлях у сховищі
+	ListByPath(ctx context.Context, path string) ([]*models.File, error)
+
+	// GetTrashedByID повертає файл з кошика
+	GetTrashedByID(ctx context.Context, id uuid.UUID) (*models.File, error)
+	// ListTrashed повертає файли користувача в кошику
+	ListTrashed(ctx context.Context, userID uuid.UUID) ([]*models.File, error)
+	// ListTrashedBefore повертає файли, переміщені в кошик до вказаного часу
+	ListTrashedBefore(ctx context.Context, before time.Time, limit int) ([]*models.File, error)
+	// Restore повертає файл з кошика
+	Restore(ctx context.Context, id uuid.UUID) error
+	// Purge остаточно видаляє запис про файл
+	Purge(ctx context.Context, id uuid.UUID) error
 }
 
+// fileCacheTTL обмежує час, протягом якого репліка може віддавати застарілі дані,
+// якщо кеш не спільний (in-process)
+const fileCacheTTL = 5 * time.Minute
+
 type fileRepository struct {
 	db    *gorm.DB
-	cache *fileCache
+	cache cache.Cache
 }
 
-type fileCache struct {
-	mu        sync.RWMutex
-	items     map[uuid.UUID]*models.File
-	userFiles map[uuid.UUID][]*models.File
-	expiry    time.Duration
+// NewFileRepository створює репозиторій файлів; кеш може бути спільним (Redis)
+// або локальним для процесу (cache.MemoryCache)
+func NewFileRepository(db *gorm.DB, c cache.Cache) FileRepository {
+	return &fileRepository{
+		db:    db,
+		cache: c,
+	}
 }
 
-func newFileCache() *fileCache {
-	return &fileCache{
-		items:     make(map[uuid.UUID]*models.File),
-		userFiles: make(map[uuid.UUID][]*models.File),
-		expiry:    5 * time.Minute,
-	}
+func fileCacheKey(id uuid.UUID) string {
+	return "file:" + id.String()
 }
 
-func NewFileRepository(db *gorm.DB) FileRepository {
-	return &fileRepository{
-		db:    db,
-		cache: newFileCache(),
+func userFilesCacheKey(userID uuid.UUID) string {
+	return "files:user:" + userID.String()
+}
+
+// invalidate видаляє з кешу файл та список файлів користувача.
+// У транзакції кеш очищується ще раз після фіксації: до неї паралельний запит
+// поза транзакцією міг знову закешувати старий запис.
+func (r *fileRepository) invalidate(ctx context.Context, id, userID uuid.UUID) {
+	r.dropCache(ctx, id, userID)
+	afterCommit(ctx, func() {
+		r.dropCache(context.WithoutCancel(ctx), id, userID)
+	})
+}
+
+// dropCache видаляє записи кешу.
+// Помилки кешу не повинні ламати запис в БД, тому лише логуються.
+func (r *fileRepository) dropCache(ctx context.Context, id, userID uuid.UUID) {
+	if err := r.cache.Delete(ctx, fileCacheKey(id)); err != nil {
+		log.Printf("Failed to invalidate file cache %s: %v", id, err)
+	}
+	if err := r.cache.Delete(ctx, userFilesCacheKey(userID)); err != nil {
+		log.Printf("Failed to invalidate user files cache %s: %v", userID, err)
 	}
 }
 
 func (r *fileRepository) Create(ctx context.Context, file *models.File) error {
-	if err := r.db.WithContext(ctx).Create(file).Error; err != nil {
+	if err := conn(ctx, r.db).Create(file).Error; err != nil {
 		return err
 	}
 
-	// Оновлюємо кеш
-	r.cache.mu.Lock()
-	r.cache.items[file.ID] = file
-	if files, ok := r.cache.userFiles[file.UserID]; ok {
-		r.cache.userFiles[file.UserID] = append(files, file)
-	}
-	r.cache.mu.Unlock()
-
+	r.invalidate(ctx, file.ID, file.UserID)
 	return nil
 }
 
 func (r *fileRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.File, error) {
+	// У транзакції кеш не використовуємо: вона могла змінити файл, а незафіксовані дані не можна кешувати
+	if inTx(ctx) {
+		var file models.File
+		if err := conn(ctx, r.db).First(&file, "id = ?", id).Error; err != nil {
+			return nil, err
+		}
+		return &file, nil
+	}
+
 	// Спочатку перевіряємо кеш
-	r.cache.mu.RLock()
-	if file, ok := r.cache.items[id]; ok {
-		r.cache.mu.RUnlock()
-		return file, nil
+	var cached models.File
+	if err := r.cache.Get(ctx, fileCacheKey(id), &cached); err == nil {
+		return &cached, nil
 	}
-	r.cache.mu.RUnlock()
 
 	// Якщо немає в кеші, читаємо з БД
 	var file models.File
-	if err := r.db.WithContext(ctx).First(&file, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&file, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
 	// Зберігаємо в кеш
-	r.cache.mu.Lock()
-	r.cache.items[file.ID] = &file
-	r.cache.mu.Unlock()
+	if err := r.cache.Set(ctx, fileCacheKey(id), &file, fileCacheTTL); err != nil {
+		log.Printf("Failed to cache file %s: %v", id, err)
+	}
 
 	return &file, nil
 }
 
 func (r *fileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.File, error) {
-	// Спочатку перевіряємо кеш
-	r.cache.mu.RLock()
-	if files, ok := r.cache.userFiles[userID]; ok {
-		r.cache.mu.RUnlock()
+	if inTx(ctx) {
+		var files []*models.File
+		if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&files).Error; err != nil {
+			return nil, err
+		}
 		return files, nil
 	}
-	r.cache.mu.RUnlock()
+
+	// Спочатку перевіряємо кеш
+	var cached []*models.File
+	if err := r.cache.Get(ctx, userFilesCacheKey(userID), &cached); err == nil {
+		return cached, nil
+	}
 
 	// Якщо немає в кеші, читаємо з БД
 	var files []*models.File
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&files).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&files).Error; err != nil {
 		return nil, err
 	}
 
 	// Зберігаємо в кеш
-	r.cache.mu.Lock()
-	r.cache.userFiles[userID] = files
-	for _, file := range files {
-		r.cache.items[file.ID] = file
+	if err := r.cache.Set(ctx, userFilesCacheKey(userID), files, fileCacheTTL); err != nil {
+		log.Printf("Failed to cache user files %s: %v", userID, err)
 	}
-	r.cache.mu.Unlock()
 
 	return files, nil
 }
 
 func (r *fileRepository) Update(ctx context.Context, file *models.File) error {
 	// Оновлюємо в БД
-	if err := r.db.WithContext(ctx).Model(&models.File{}).Where("id = ?", file.ID).Updates(file).Error; err != nil {
+	if err := conn(ctx, r.db).Model(&models.File{}).Where("id = ?", file.ID).Updates(file).Error; err != nil {
 		return err
 	}
 
-	// Оновлюємо кеш
-	r.cache.mu.Lock()
-	r.cache.items[file.ID] = file
-	if files, ok := r.cache.userFiles[file.UserID]; ok {
-		for i, f := range files {
-			if f.ID == file.ID {
-				files[i] = file
-				break
-			}
-		}
-	}
-	r.cache.mu.Unlock()
-
+	r.invalidate(ctx, file.ID, file.UserID)
 	return nil
 }
 
@@ -145,30 +179,17 @@ func (r *fileRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	// Видаляємо з БД
-	if err := r.db.WithContext(ctx).Delete(&models.File{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).Delete(&models.File{}, "id = ?", id).Error; err != nil {
 		return err
 	}
 
-	// Видаляємо з кешу
-	r.cache.mu.Lock()
-	delete(r.cache.items, id)
-	if files, ok := r.cache.userFiles[file.UserID]; ok {
-		newFiles := make([]*models.File, 0, len(files)-1)
-		for _, f := range files {
-			if f.ID != id {
-				newFiles = append(newFiles, f)
-			}
-		}
-		r.cache.userFiles[file.UserID] = newFiles
-	}
-	r.cache.mu.Unlock()
-
+	r.invalidate(ctx, id, file.UserID)
 	return nil
 }
 
 func (r *fileRepository) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(&models.File{})
+	query := conn(ctx, r.db).Model(&models.File{})
 
 	for key, value := range filter {
 		query = query.Where(key+" = ?", value)
@@ -179,7 +200,7 @@ func (r *fileRepository) Count(ctx context.Context, filter map[string]interface{
 
 func (r *fileRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.File, error) {
 	var files []*models.File
-	query := r.db.WithContext(ctx)
+	query := conn(ctx, r.db)
 
 	for key, value := range filter {
 		query = query.Where(key+" = ?", value)
@@ -198,7 +219,7 @@ func (r *fileRepository) BatchCreate(ctx context.Context, files []*models.File)
 	}
 
 	// Створюємо записи в транзакції
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		for _, file := range files {
 			if err := tx.Create(file).Error; err != nil {
 				return err
@@ -211,17 +232,9 @@ func (r *fileRepository) BatchCreate(ctx context.Context, files []*models.File)
 		return err
 	}
 
-	// Оновлюємо кеш
-	r.cache.mu.Lock()
 	for _, file := range files {
-		r.cache.items[file.ID] = file
-		if existingFiles, ok := r.cache.userFiles[file.UserID]; ok {
-			r.cache.userFiles[file.UserID] = append(existingFiles, file)
-		} else {
-			r.cache.userFiles[file.UserID] = []*models.File{file}
-		}
+		r.invalidate(ctx, file.ID, file.UserID)
 	}
-	r.cache.mu.Unlock()
 
 	return nil
 }
@@ -233,30 +246,169 @@ func (r *fileRepository) BatchDelete(ctx context.Context, ids []uuid.UUID) error
 
 	// Отримуємо файли для визначення userID
 	var files []*models.File
-	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&files).Error; err != nil {
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&files).Error; err != nil {
 		return err
 	}
 
 	// Видаляємо записи в транзакції
-	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.File{}).Error; err != nil {
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Delete(&models.File{}).Error; err != nil {
 		return err
 	}
 
-	// Оновлюємо кеш
-	r.cache.mu.Lock()
 	for _, file := range files {
-		delete(r.cache.items, file.ID)
-		if userFiles, ok := r.cache.userFiles[file.UserID]; ok {
-			newFiles := make([]*models.File, 0, len(userFiles))
-			for _, f := range userFiles {
-				if f.ID != file.ID {
-					newFiles = append(newFiles, f)
-				}
-			}
-			r.cache.userFiles[file.UserID] = newFiles
-		}
+		r.invalidate(ctx, file.ID, file.UserID)
+	}
+
+	return nil
+}
+
+func (r *fileRepository) GetTrashedByID(ctx context.Context, id uuid.UUID) (*models.File, error) {
+	var file models.File
+	if err := conn(ctx, r.db).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&file).Error; err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *fileRepository) ListTrashed(ctx context.Context, userID uuid.UUID) ([]*models.File, error) {
+	var files []*models.File
+	if err := conn(ctx, r.db).Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (r *fileRepository) ListTrashedBefore(ctx context.Context, before time.Time, limit int) ([]*models.File, error) {
+	var files []*models.File
+	if err := conn(ctx, r.db).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (r *fileRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	file, err := r.GetTrashedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Unscoped().Model(&models.File{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, id, file.UserID)
+
+	return nil
+}
+
+func (r *fileRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	file, err := r.GetTrashedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Unscoped().Delete(&models.File{}, "id = ?", id).Error; err != nil {
+		return err
 	}
-	r.cache.mu.Unlock()
 
+	r.invalidate(ctx, id, file.UserID)
 	return nil
 }
+
+func (r *fileRepository) Search(ctx context.Context, userID uuid.UUID, opts models.FileSearchOptions) ([]*models.File, error) {
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+
+	if opts.BookingID != nil {
+		query = query.Where("booking_id = ?", *opts.BookingID)
+	}
+	if opts.Type != "" {
+		query = query.Where("type = ?", opts.Type)
+	}
+	if opts.Folder != nil {
+		query = query.Where("folder = ?", *opts.Folder)
+	}
+	if opts.TakenFrom != nil {
+		query = query.Where("taken_at >= ?", *opts.TakenFrom)
+	}
+	if opts.TakenTo != nil {
+		query = query.Where("taken_at < ?", *opts.TakenTo)
+	}
+	if opts.Camera != "" {
+		camera := "%" + opts.Camera + "%"
+		query = query.Where("(metadata->>'camera_model' ILIKE ? OR metadata->>'camera_make' ILIKE ?)", camera, camera)
+	}
+	if opts.Orientation != "" {
+		// @> використовує GIN-індекс по metadata
+		query = query.Where("metadata @> ?", fmt.Sprintf(`{"orientation":%q}`, opts.Orientation))
+	}
+
+	direction := "ASC"
+	if opts.SortDesc {
+		direction = "DESC"
+	}
+	switch opts.SortBy {
+	case "taken_at":
+		// Файли без дати зйомки йдуть в кінці, щоб не розривати хронологію
+		query = query.Order("taken_at " + direction + " NULLS LAST").Order("created_at " + direction)
+	case "name":
+		query = query.Order("name " + direction)
+	default:
+		query = query.Order("created_at " + direction)
+	}
+
+	var files []*models.File
+	if err := query.Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (r *fileRepository) UpdateScanResult(ctx context.Context, id uuid.UUID, status models.ScanStatus, signature string) error {
+	file, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Model(&models.File{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"scan_status":    status,
+			"scan_signature": signature,
+			"scanned_at":     time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, id, file.UserID)
+	return nil
+}
+
+func (r *fileRepository) ListUnscanned(ctx context.Context, before time.Time, limit int) ([]*models.File, error) {
+	var files []*models.File
+	if err := conn(ctx, r.db).
+		Where("scan_status IN ? AND updated_at < ?", []models.ScanStatus{models.ScanStatusPending, models.ScanStatusFailed}, before).
+		Order("created_at").
+		Limit(limit).
+		Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (r *fileRepository) ListByPath(ctx context.Context, path string) ([]*models.File, error) {
+	var files []*models.File
+	if err := conn(ctx, r.db).Where("path = ?", path).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
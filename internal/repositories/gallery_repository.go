@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"timebride/internal/models"
+)
+
+var ErrGalleryNotFound = errors.New("gallery not found")
+
+// GalleryRepository handles database operations for public booking galleries
+type GalleryRepository interface {
+	// Create stores a new gallery
+	Create(ctx context.Context, gallery *models.Gallery) error
+
+	// Update saves gallery changes
+	Update(ctx context.Context, gallery *models.Gallery) error
+
+	// GetByToken retrieves a gallery by its public token
+	GetByToken(ctx context.Context, token string) (*models.Gallery, error)
+
+	// GetByBookingID retrieves the gallery of a booking
+	GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*models.Gallery, error)
+
+	// IncrementDownloads atomically counts a download unless the limit is reached.
+	// Returns false when the limit was already exhausted.
+	IncrementDownloads(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// LogAccess records a view or download of the public page
+	LogAccess(ctx context.Context, access *models.GalleryAccess) error
+
+	// ListAccess retrieves the latest access records of a gallery
+	ListAccess(ctx context.Context, galleryID uuid.UUID, limit int) ([]*models.GalleryAccess, error)
+
+	// AccessStats aggregates views, downloads and unique visitors of a gallery
+	AccessStats(ctx context.Context, galleryID uuid.UUID) (*models.GalleryStats, error)
+
+	// CountAccessSince counts actions of a visitor in a gallery after the given time
+	CountAccessSince(ctx context.Context, galleryID uuid.UUID, action models.GalleryAction, ip string, since time.Time) (int64, error)
+
+	// ActivityByUser aggregates access of all galleries of a user per booking after the given time
+	ActivityByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.BookingGalleryActivity, error)
+}
+
+type galleryRepository struct {
+	db *gorm.DB
+}
+
+// NewGalleryRepository creates a new instance of GalleryRepository
+func NewGalleryRepository(db *gorm.DB) GalleryRepository {
+	return &galleryRepository{db: db}
+}
+
+func (r *galleryRepository) Create(ctx context.Context, gallery *models.Gallery) error {
+	return conn(ctx, r.db).Create(gallery).Error
+}
+
+func (r *galleryRepository) Update(ctx context.Context, gallery *models.Gallery) error {
+	return conn(ctx, r.db).Save(gallery).Error
+}
+
+func (r *galleryRepository) GetByToken(ctx context.Context, token string) (*models.Gallery, error) {
+	return r.first(ctx, "token = ?", token)
+}
+
+func (r *galleryRepository) GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*models.Gallery, error) {
+	return r.first(ctx, "booking_id = ?", bookingID)
+}
+
+func (r *galleryRepository) IncrementDownloads(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&models.Gallery{}).
+		Where("id = ? AND (download_limit = 0 OR download_count < download_limit)", id).
+		UpdateColumn("download_count", gorm.Expr("download_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *galleryRepository) LogAccess(ctx context.Context, access *models.GalleryAccess) error {
+	return conn(ctx, r.db).Create(access).Error
+}
+
+func (r *galleryRepository) ListAccess(ctx context.Context, galleryID uuid.UUID, limit int) ([]*models.GalleryAccess, error) {
+	var records []*models.GalleryAccess
+	err := conn(ctx, r.db).
+		Where("gallery_id = ?", galleryID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+func (r *galleryRepository) AccessStats(ctx context.Context, galleryID uuid.UUID) (*models.GalleryStats, error) {
+	var stats models.GalleryStats
+	err := conn(ctx, r.db).
+		Model(&models.GalleryAccess{}).
+		Select(statsColumns("")).
+		Where("gallery_id = ?", galleryID).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *galleryRepository) CountAccessSince(ctx context.Context, galleryID uuid.UUID, action models.GalleryAction, ip string, since time.Time) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.GalleryAccess{}).
+		Where("gallery_id = ? AND action = ? AND ip = ? AND created_at > ?", galleryID, action, ip, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *galleryRepository) ActivityByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.BookingGalleryActivity, error) {
+	var activity []*models.BookingGalleryActivity
+	err := conn(ctx, r.db).
+		Table("gallery_access_log AS a").
+		Select("g.booking_id, b.title AS booking_title, "+statsColumns("a.")).
+		Joins("JOIN galleries g ON g.id = a.gallery_id").
+		Joins("JOIN bookings b ON b.id = g.booking_id").
+		Where("g.user_id = ? AND a.created_at > ?", userID, since).
+		Group("g.booking_id, b.title").
+		Order("MAX(a.created_at) DESC").
+		Scan(&activity).Error
+	return activity, err
+}
+
+// statsColumns builds the aggregate columns of GalleryStats over access log rows
+func statsColumns(prefix string) string {
+	count := func(action models.GalleryAction) string {
+		return "COUNT(*) FILTER (WHERE " + prefix + "action = '" + string(action) + "')"
+	}
+	return count(models.GalleryActionView) + " AS views, " +
+		count(models.GalleryActionFileView) + " AS file_views, " +
+		count(models.GalleryActionDownload) + " AS downloads, " +
+		count(models.GalleryActionZipDownload) + " AS zip_downloads, " +
+		"COUNT(DISTINCT " + prefix + "ip) AS unique_visitors, " +
+		"MAX(" + prefix + "created_at) FILTER (WHERE " + prefix + "action = '" + string(models.GalleryActionView) + "') AS last_viewed_at"
+}
+
+func (r *galleryRepository) first(ctx context.Context, query string, args ...interface{}) (*models.Gallery, error) {
+	var gallery models.Gallery
+	if err := conn(ctx, r.db).Where(query, args...).First(&gallery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGalleryNotFound
+		}
+		return nil, err
+	}
+	return &gallery, nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobLeaseExpired - задачу після завершення оренди взяв інший обробник
+	ErrJobLeaseExpired = errors.New("job lease expired")
+)
+
+// JobRepository handles the queue of background jobs
+type JobRepository interface {
+	// Enqueue adds a job; it returns false without error when a pending or running job
+	// with the same unique key already exists
+	Enqueue(ctx context.Context, job *models.Job) (bool, error)
+
+	// Claim returns jobs of the given types that are due, or whose worker lease has expired with
+	// attempts left, marks them running until now+lease and counts the attempt. Jobs whose lease
+	// expired on the last attempt are marked failed.
+	Claim(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]*models.Job, error)
+
+	// Update saves the status, attempts, schedule and result of a job claimed until lockedUntil;
+	// it returns ErrJobLeaseExpired when the job has been claimed again since
+	Update(ctx context.Context, job *models.Job, lockedUntil time.Time) error
+
+	// GetByID retrieves a job by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error)
+
+	// ListByStatus returns the latest jobs with the given status, most recently updated first
+	ListByStatus(ctx context.Context, status models.JobStatus, limit int) ([]*models.Job, error)
+
+	// Requeue moves a failed job back to pending with a fresh set of attempts. The unique key is
+	// cleared: a manual retry must not conflict with a job queued with the same key since the failure.
+	Requeue(ctx context.Context, id uuid.UUID, runAt time.Time) error
+
+	// DeleteFinished removes jobs with the given status finished before the given time
+	DeleteFinished(ctx context.Context, status models.JobStatus, before time.Time) (int64, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new instance of JobRepository
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Enqueue(ctx context.Context, job *models.Job) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "unique_key"}},
+			// Має збігатися з умовою часткового індексу idx_jobs_unique_key
+			TargetWhere: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "status IN ('pending', 'running')"},
+			}},
+			DoNothing: true,
+		}).
+		Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *jobRepository) Claim(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Обробник не завершив останню спробу - задача більше не повторюється
+		if err := tx.Model(&models.Job{}).
+			Where("type IN ? AND status = ? AND locked_until <= ? AND attempts >= max_attempts",
+				types, models.JobStatusRunning, now).
+			Updates(map[string]interface{}{
+				"status":       models.JobStatusFailed,
+				"last_error":   "worker lease expired",
+				"locked_until": nil,
+				"finished_at":  now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("type IN ?", types).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ? AND attempts < max_attempts)",
+				models.JobStatusPending, now, models.JobStatusRunning, now).
+			Order("run_at").
+			Limit(limit).
+			Find(&jobs).Error; err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		// Точність як у колонці бази, щоб Update міг порівняти оренду
+		lockedUntil := now.Add(lease).Truncate(time.Microsecond)
+		ids := make([]uuid.UUID, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+			job.Status = models.JobStatusRunning
+			job.Attempts++
+			job.LockedUntil = &lockedUntil
+		}
+		return tx.Model(&models.Job{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       models.JobStatusRunning,
+				"attempts":     gorm.Expr("attempts + 1"),
+				"locked_until": lockedUntil,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Update(ctx context.Context, job *models.Job, lockedUntil time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.Job{}).
+		Where("id = ? AND locked_until = ?", job.ID, lockedUntil).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"attempts":     job.Attempts,
+			"last_error":   job.LastError,
+			"run_at":       job.RunAt,
+			"locked_until": job.LockedUntil,
+			"finished_at":  job.FinishedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLeaseExpired
+	}
+	return nil
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	var job models.Job
+	if err := conn(ctx, r.db).First(&job, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) ListByStatus(ctx context.Context, status models.JobStatus, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	err := conn(ctx, r.db).
+		Where("status = ?", status).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *jobRepository) Requeue(ctx context.Context, id uuid.UUID, runAt time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobStatusFailed).
+		Updates(map[string]interface{}{
+			"status":       models.JobStatusPending,
+			"unique_key":   nil,
+			"attempts":     0,
+			"run_at":       runAt,
+			"locked_until": nil,
+			"finished_at":  nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+func (r *jobRepository) DeleteFinished(ctx context.Context, status models.JobStatus, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("status = ? AND finished_at < ?", status, before).
+		Delete(&models.Job{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"timebride/internal/models"
+)
+
+func TestJobClaimStopsAfterLastAttempt(t *testing.T) {
+	db := openTestDB(t)
+	createTable(t, db, &models.Job{})
+	repo := NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	job := &models.Job{Type: "files.scan", Status: models.JobStatusPending, MaxAttempts: 2, RunAt: now}
+	if err := db.Create(job).Error; err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	// Обробник, що взяв задачу, зупинився до кінця оренди - задачу бере наступний
+	for attempt := 1; attempt <= 2; attempt++ {
+		claimed, err := repo.Claim(ctx, []string{job.Type}, now, time.Minute, 10)
+		if err != nil || len(claimed) != 1 || claimed[0].Attempts != attempt {
+			t.Fatalf("attempt %d: Claim = %+v, %v", attempt, claimed, err)
+		}
+		now = now.Add(time.Minute)
+	}
+
+	claimed, err := repo.Claim(ctx, []string{job.Type}, now, time.Minute, 10)
+	if err != nil || len(claimed) != 0 {
+		t.Fatalf("Claim after last attempt = %d jobs, %v; want 0, nil", len(claimed), err)
+	}
+	got, err := repo.GetByID(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if got.Status != models.JobStatusFailed || got.Attempts != 2 || got.LockedUntil != nil || got.FinishedAt == nil {
+		t.Fatalf("job after expired last attempt: %+v", got)
+	}
+}
+
+func TestJobUpdateRequiresLease(t *testing.T) {
+	db := openTestDB(t)
+	createTable(t, db, &models.Job{})
+	repo := NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	job := &models.Job{Type: "files.scan", Status: models.JobStatusPending, MaxAttempts: 3, RunAt: now}
+	if err := db.Create(job).Error; err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	first, err := repo.Claim(ctx, []string{job.Type}, now, time.Minute, 10)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("first Claim = %d jobs, %v", len(first), err)
+	}
+	// Оренда першого обробника завершилась, задачу взяв другий
+	second, err := repo.Claim(ctx, []string{job.Type}, now.Add(time.Minute), time.Minute, 10)
+	if err != nil || len(second) != 1 {
+		t.Fatalf("second Claim = %d jobs, %v", len(second), err)
+	}
+
+	finish := func(job *models.Job) error {
+		lockedUntil := *job.LockedUntil
+		job.Status = models.JobStatusDone
+		job.LockedUntil = nil
+		return repo.Update(ctx, job, lockedUntil)
+	}
+	if err := finish(first[0]); !errors.Is(err, ErrJobLeaseExpired) {
+		t.Fatalf("Update by the first worker = %v, want %v", err, ErrJobLeaseExpired)
+	}
+	if err := finish(second[0]); err != nil {
+		t.Fatalf("Update by the second worker: %v", err)
+	}
+	if got, _ := repo.GetByID(ctx, job.ID); got.Status != models.JobStatusDone || got.Attempts != 2 {
+		t.Fatalf("job = %+v, want done after 2 attempts", got)
+	}
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+// NotificationDeliveryRepository handles the log of notification deliveries to external channels
+type NotificationDeliveryRepository interface {
+	// Create stores a new delivery
+	Create(ctx context.Context, delivery *models.NotificationDelivery) error
+
+	// Update saves the status, attempts and schedule of a delivery and releases its lease
+	Update(ctx context.Context, delivery *models.NotificationDelivery) error
+
+	// Claim returns pending deliveries whose next attempt is due, oldest first, with their notifications,
+	// and locks them for lease, so concurrent workers do not send the same notification twice
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.NotificationDelivery, error)
+
+	// ListByUser returns the latest deliveries of a user with their notifications
+	ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*models.NotificationDelivery, error)
+}
+
+type notificationDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationDeliveryRepository creates a new instance of NotificationDeliveryRepository
+func NewNotificationDeliveryRepository(db *gorm.DB) NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{db: db}
+}
+
+func (r *notificationDeliveryRepository) Create(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return conn(ctx, r.db).Omit("Notification").Create(delivery).Error
+}
+
+func (r *notificationDeliveryRepository) Update(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return conn(ctx, r.db).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"locked_until":    nil,
+			"sent_at":         delivery.SentAt,
+		}).Error
+}
+
+func (r *notificationDeliveryRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.NotificationDelivery, error) {
+	var ids []uuid.UUID
+	lockedUntil := now.Add(lease)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.NotificationDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&models.NotificationDelivery{}).
+			Where("id IN ?", ids).
+			Update("locked_until", lockedUntil).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []*models.NotificationDelivery
+	err = conn(ctx, r.db).
+		Preload("Notification").
+		Where("id IN ?", ids).
+		Order("next_attempt_at").
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *notificationDeliveryRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*models.NotificationDelivery, error) {
+	var deliveries []*models.NotificationDelivery
+	err := conn(ctx, r.db).
+		Preload("Notification").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+func TestNotificationDeliveryClaimLeasesDeliveries(t *testing.T) {
+	db := openTestDB(t)
+	createTable(t, db, &models.Notification{})
+	createTable(t, db, &models.NotificationDelivery{})
+	repo := NewNotificationDeliveryRepository(db)
+	ctx := context.Background()
+
+	notification := &models.Notification{ID: uuid.New(), UserID: uuid.New(), Title: "Термін віддачі"}
+	if err := db.Create(notification).Error; err != nil {
+		t.Fatalf("create notification: %v", err)
+	}
+	now := time.Now()
+	delivery := &models.NotificationDelivery{
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		Channel:        models.NotificationChannelTelegram,
+		Status:         models.DeliveryStatusPending,
+		NextAttemptAt:  &now,
+	}
+	if err := repo.Create(ctx, delivery); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Claim = %d deliveries, %v; want 1, nil", len(claimed), err)
+	}
+	if claimed[0].Notification == nil || claimed[0].Notification.Title != notification.Title {
+		t.Fatalf("claimed delivery without its notification: %+v", claimed[0])
+	}
+
+	// Доставку вже взято - до кінця оренди її не отримує інший обробник
+	if again, err := repo.Claim(ctx, now.Add(30*time.Second), time.Minute, 10); err != nil || len(again) != 0 {
+		t.Fatalf("Claim within lease = %d deliveries, %v; want 0, nil", len(again), err)
+	}
+	if again, err := repo.Claim(ctx, now.Add(time.Minute), time.Minute, 10); err != nil || len(again) != 1 {
+		t.Fatalf("Claim after lease = %d deliveries, %v; want 1, nil", len(again), err)
+	}
+
+	// Збереження результату спроби знімає оренду
+	next := now.Add(time.Minute)
+	delivery.Attempts = 1
+	delivery.NextAttemptAt = &next
+	if err := repo.Update(ctx, delivery); err != nil {
+		t.Fatalf("update delivery: %v", err)
+	}
+	if due, err := repo.Claim(ctx, next, time.Minute, 10); err != nil || len(due) != 1 {
+		t.Fatalf("Claim after update = %d deliveries, %v; want 1, nil", len(due), err)
+	}
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"timebride/internal/models"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationRepository handles database operations for in-app notifications
+type NotificationRepository interface {
+	// Create stores a new notification
+	Create(ctx context.Context, notification *models.Notification) error
+
+	// ListByUser returns the latest notifications of a user
+	ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*models.Notification, error)
+
+	// CountUnread returns the number of unread notifications of a user
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// MarkRead marks a notification of the user as read
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+
+	// MarkAllRead marks all notifications of the user as read
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+
+	// LatestOfType returns the most recent notification of the given type, or nil if there is none
+	LatestOfType(ctx context.Context, userID uuid.UUID, notificationType models.NotificationType) (*models.Notification, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return conn(ctx, r.db).Create(notification).Error
+}
+
+func (r *notificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*models.Notification, error) {
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []*models.Notification
+	if err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	result := conn(ctx, r.db).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return conn(ctx, r.db).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+func (r *notificationRepository) LatestOfType(ctx context.Context, userID uuid.UUID, notificationType models.NotificationType) (*models.Notification, error) {
+	var notifications []*models.Notification
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND type = ?", userID, notificationType).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&notifications).Error
+	if err != nil || len(notifications) == 0 {
+		return nil, err
+	}
+	return notifications[0], nil
+}
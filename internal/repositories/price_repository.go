@@ -30,7 +30,7 @@ func NewPriceRepository(db *gorm.DB) PriceRepository {
 // GetByUserID отримує всі шаблони цін користувача
 func (r *priceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.PriceTemplate, error) {
 	var templates []*models.PriceTemplate
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&templates).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&templates).Error; err != nil {
 		return nil, err
 	}
 	return templates, nil
@@ -39,7 +39,7 @@ func (r *priceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 // GetActive отримує всі активні шаблони цін
 func (r *priceRepository) GetActive(ctx context.Context) ([]*models.PriceTemplate, error) {
 	var templates []*models.PriceTemplate
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("deleted_at IS NULL").
 		Find(&templates).Error; err != nil {
 		return nil, err
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+// GalleryProofRepository handles client proofing marks on gallery photos
+type GalleryProofRepository interface {
+	// Save creates or updates the mark of a file in a gallery
+	Save(ctx context.Context, proof *models.GalleryProof) error
+
+	// Get retrieves the mark of a file, or nil if the file is not marked
+	Get(ctx context.Context, galleryID, fileID uuid.UUID) (*models.GalleryProof, error)
+
+	// ListByGallery retrieves all marks of a gallery with their files
+	ListByGallery(ctx context.Context, galleryID uuid.UUID) ([]*models.GalleryProof, error)
+
+	// CountFavorites counts files marked as favorite in a gallery
+	CountFavorites(ctx context.Context, galleryID uuid.UUID) (int64, error)
+}
+
+type galleryProofRepository struct {
+	db *gorm.DB
+}
+
+// NewGalleryProofRepository creates a new instance of GalleryProofRepository
+func NewGalleryProofRepository(db *gorm.DB) GalleryProofRepository {
+	return &galleryProofRepository{db: db}
+}
+
+func (r *galleryProofRepository) Save(ctx context.Context, proof *models.GalleryProof) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "gallery_id"}, {Name: "file_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"favorite", "comment", "updated_at"}),
+		}).
+		Create(proof).Error
+}
+
+func (r *galleryProofRepository) Get(ctx context.Context, galleryID, fileID uuid.UUID) (*models.GalleryProof, error) {
+	var proofs []*models.GalleryProof
+	err := conn(ctx, r.db).
+		Where("gallery_id = ? AND file_id = ?", galleryID, fileID).
+		Limit(1).
+		Find(&proofs).Error
+	if err != nil || len(proofs) == 0 {
+		return nil, err
+	}
+	return proofs[0], nil
+}
+
+func (r *galleryProofRepository) ListByGallery(ctx context.Context, galleryID uuid.UUID) ([]*models.GalleryProof, error) {
+	var proofs []*models.GalleryProof
+	err := conn(ctx, r.db).
+		Preload("File").
+		Where("gallery_id = ?", galleryID).
+		Order("created_at").
+		Find(&proofs).Error
+	return proofs, err
+}
+
+func (r *galleryProofRepository) CountFavorites(ctx context.Context, galleryID uuid.UUID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.GalleryProof{}).
+		Where("gallery_id = ? AND favorite", galleryID).
+		Count(&count).Error
+	return count, err
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"timebride/internal/cache"
 )
 
 // Repository визначає базовий інтерфейс для всіх репозиторіїв
@@ -26,10 +28,28 @@ type Repositories struct {
 	Price    PriceRepository
 	Template TemplateRepository
 	File     FileRepository
+	FileLink FileLinkRepository
+	FileBlob FileBlobRepository
+
+	Notification NotificationRepository
+	Delivery     NotificationDeliveryRepository
+	Gallery      GalleryRepository
+	GalleryProof GalleryProofRepository
+	Contract     ContractRepository
+	EmailOutbox  EmailOutboxRepository
+	Reminder     DeadlineReminderRepository
+	Webhook      WebhookRepository
+	WebhookLog   WebhookDeliveryRepository
+	EventOutbox  EventOutboxRepository
+	Job          JobRepository
+
+	// Tx - спільна транзакція для змін у кількох репозиторіях
+	Tx UnitOfWork
 }
 
-// NewRepositories створює нову структуру репозиторіїв
-func NewRepositories(db *gorm.DB) *Repositories {
+// NewRepositories створює нову структуру репозиторіїв.
+// Кеш використовується репозиторіями, що кешують читання (зараз - файли).
+func NewRepositories(db *gorm.DB, c cache.Cache) *Repositories {
 	return &Repositories{
 		User:     NewUserRepository(db),
 		Booking:  NewBookingRepository(db),
@@ -37,7 +57,23 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		Team:     NewTeamRepository(db),
 		Price:    NewPriceRepository(db),
 		Template: NewTemplateRepository(db),
-		File:     NewFileRepository(db),
+		File:     NewFileRepository(db, c),
+		FileLink: NewFileLinkRepository(db),
+		FileBlob: NewFileBlobRepository(db),
+
+		Notification: NewNotificationRepository(db),
+		Delivery:     NewNotificationDeliveryRepository(db),
+		Gallery:      NewGalleryRepository(db),
+		GalleryProof: NewGalleryProofRepository(db),
+		Contract:     NewContractRepository(db),
+		EmailOutbox:  NewEmailOutboxRepository(db),
+		Reminder:     NewDeadlineReminderRepository(db),
+		Webhook:      NewWebhookRepository(db),
+		WebhookLog:   NewWebhookDeliveryRepository(db),
+		EventOutbox:  NewEventOutboxRepository(db),
+		Job:          NewJobRepository(db),
+
+		Tx: NewUnitOfWork(db),
 	}
 }
 
@@ -48,23 +84,23 @@ type baseRepository[T any] struct {
 
 // Create створює новий запис
 func (r *baseRepository[T]) Create(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Create(entity).Error
+	return conn(ctx, r.db).Create(entity).Error
 }
 
 // Update оновлює існуючий запис
 func (r *baseRepository[T]) Update(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Save(entity).Error
+	return conn(ctx, r.db).Save(entity).Error
 }
 
 // Delete видаляє запис
 func (r *baseRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(new(T), "id = ?", id).Error
+	return conn(ctx, r.db).Delete(new(T), "id = ?", id).Error
 }
 
 // GetByID отримує запис за ID
 func (r *baseRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (*T, error) {
 	var entity T
-	if err := r.db.WithContext(ctx).First(&entity, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&entity, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &entity, nil
@@ -73,7 +109,7 @@ func (r *baseRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (*T, erro
 // List отримує список записів за фільтром
 func (r *baseRepository[T]) List(ctx context.Context, filter map[string]interface{}) ([]*T, error) {
 	var entities []*T
-	query := r.db.WithContext(ctx)
+	query := conn(ctx, r.db)
 	for key, value := range filter {
 		query = query.Where(key+" = ?", value)
 	}
@@ -86,7 +122,7 @@ func (r *baseRepository[T]) List(ctx context.Context, filter map[string]interfac
 // Count підраховує кількість записів за фільтром
 func (r *baseRepository[T]) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(new(T))
+	query := conn(ctx, r.db).Model(new(T))
 	for key, value := range filter {
 		query = query.Where(key+" = ?", value)
 	}
@@ -29,7 +29,7 @@ func NewTeamRepository(db *gorm.DB) TeamRepository {
 // GetByUserID отримує список членів команди користувача
 func (r *teamRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.TeamMember, error) {
 	var members []*models.TeamMember
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&members).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&members).Error; err != nil {
 		return nil, err
 	}
 	return members, nil
@@ -43,12 +43,12 @@ func NewTemplateRepository(db *gorm.DB) TemplateRepository {
 }
 
 func (r *templateRepository) Create(ctx context.Context, template *models.Template) error {
-	return r.db.WithContext(ctx).Create(template).Error
+	return conn(ctx, r.db).Create(template).Error
 }
 
 func (r *templateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Template, error) {
 	var template models.Template
-	if err := r.db.WithContext(ctx).First(&template, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&template, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTemplateNotFound
 		}
@@ -60,7 +60,7 @@ func (r *templateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 // GetByUserID отримує шаблони користувача
 func (r *templateRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Template, error) {
 	var templates []*models.Template
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&templates).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&templates).Error; err != nil {
 		return nil, err
 	}
 	return templates, nil
@@ -68,7 +68,7 @@ func (r *templateRepository) GetByUserID(ctx context.Context, userID uuid.UUID)
 
 func (r *templateRepository) GetByEventType(ctx context.Context, userID uuid.UUID, eventType string) ([]*models.Template, error) {
 	var templates []*models.Template
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("user_id = ? AND event_type = ?", userID, eventType).
 		Find(&templates).Error; err != nil {
 		return nil, err
@@ -77,16 +77,16 @@ func (r *templateRepository) GetByEventType(ctx context.Context, userID uuid.UUI
 }
 
 func (r *templateRepository) Update(ctx context.Context, template *models.Template) error {
-	return r.db.WithContext(ctx).Save(template).Error
+	return conn(ctx, r.db).Save(template).Error
 }
 
 func (r *templateRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Template{}, "id = ?", id).Error
+	return conn(ctx, r.db).Delete(&models.Template{}, "id = ?", id).Error
 }
 
 func (r *templateRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.Template, error) {
 	var templates []*models.Template
-	query := r.db.WithContext(ctx)
+	query := conn(ctx, r.db)
 
 	// Apply filters
 	for key, value := range filter {
@@ -102,7 +102,7 @@ func (r *templateRepository) List(ctx context.Context, filter map[string]interfa
 // Count повертає кількість шаблонів за фільтром
 func (r *templateRepository) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(&models.Template{})
+	query := conn(ctx, r.db).Model(&models.Template{})
 
 	if filter != nil {
 		for key, value := range filter {
@@ -119,7 +119,7 @@ func (r *templateRepository) Count(ctx context.Context, filter map[string]interf
 // GetByType отримує всі шаблони певного типу
 func (r *templateRepository) GetByType(ctx context.Context, templateType string) ([]*models.Template, error) {
 	var templates []*models.Template
-	if err := r.db.WithContext(ctx).Where("type = ?", templateType).Find(&templates).Error; err != nil {
+	if err := conn(ctx, r.db).Where("type = ?", templateType).Find(&templates).Error; err != nil {
 		return nil, err
 	}
 	return templates, nil
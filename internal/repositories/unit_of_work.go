@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork groups writes to several repositories into one transaction. The transaction is
+// carried in the context, so repositories join it without changes in their signatures.
+type UnitOfWork interface {
+	// Do runs fn in a transaction. Every repository called with the context passed to fn uses
+	// that transaction; an error or panic in fn rolls back all of their writes. A nested Do joins
+	// the outer transaction through a savepoint.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey - ключ контексту, під яким UnitOfWork передає відкриту транзакцію
+type txKey struct{}
+
+// txState - відкрита транзакція та дії, відкладені до її фіксації
+type txState struct {
+	tx *gorm.DB
+	// afterCommit спільний для вкладених транзакцій: дії виконуються після фіксації зовнішньої
+	afterCommit *[]func()
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a new instance of UnitOfWork
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if outer, ok := ctx.Value(txKey{}).(*txState); ok {
+		// Дії відкоченої точки збереження не виконуються
+		mark := len(*outer.afterCommit)
+		err := outer.tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, &txState{tx: tx, afterCommit: outer.afterCommit}))
+		})
+		if err != nil {
+			*outer.afterCommit = (*outer.afterCommit)[:mark]
+		}
+		return err
+	}
+
+	var hooks []func()
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, &txState{tx: tx, afterCommit: &hooks}))
+	})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// conn повертає транзакцію UnitOfWork з контексту, а поза нею - з'єднання репозиторію.
+// Усі запити репозиторіїв мають проходити через conn, щоб брати участь у спільній транзакції.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// inTx перевіряє, чи виконується запит у транзакції UnitOfWork
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// afterCommit відкладає fn до фіксації транзакції UnitOfWork; після відкату fn не виконується.
+// Поза транзакцією нічого не робить: зміни вже зафіксовані.
+func afterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		*state.afterCommit = append(*state.afterCommit, fn)
+	}
+}
@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+
+	"timebride/internal/models"
+)
+
+// note - найпростіша таблиця для перевірки транзакцій
+type note struct {
+	ID   uint
+	Text string
+}
+
+// openTestDB відкриває окрему базу SQLite в пам'яті з таблицями, потрібними тестам
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := "file:" + strings.ReplaceAll(t.Name(), "/", "_") + "?mode=memory&cache=shared"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sqlite pool: %v", err)
+	}
+	// Одне з'єднання: база в пам'яті живе, поки воно відкрите
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&note{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	createTable(t, db, &models.Booking{})
+	createTable(t, db, &models.DomainEvent{})
+	return db
+}
+
+// createTable створює таблицю моделі лише з її колонками. AutoMigrate тут не підходить:
+// він іде за зв'язками моделей і переносить типові значення Postgres, яких немає в SQLite.
+func createTable(t *testing.T, db *gorm.DB, model interface{}) {
+	t.Helper()
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		t.Fatalf("parse %T: %v", model, err)
+	}
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		column := `"` + field.DBName + `"`
+		// Драйвер sqlite повертає time.Time лише для колонок, оголошених як datetime
+		if field.DataType == schema.Time {
+			column += " DATETIME"
+		}
+		if field.PrimaryKey {
+			column += " PRIMARY KEY"
+		}
+		columns = append(columns, column)
+	}
+	ddl := `CREATE TABLE "` + stmt.Schema.Table + `" (` + strings.Join(columns, ", ") + `)`
+	if err := db.Exec(ddl).Error; err != nil {
+		t.Fatalf("create %s: %v", stmt.Schema.Table, err)
+	}
+}
+
+func countNotes(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var texts []string
+	if err := db.Model(&note{}).Order("id").Pluck("text", &texts).Error; err != nil {
+		t.Fatalf("list notes: %v", err)
+	}
+	return texts
+}
+
+func addNote(ctx context.Context, db *gorm.DB, text string) error {
+	return conn(ctx, db).Create(&note{Text: text}).Error
+}
+
+func TestUnitOfWorkCommits(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+
+	committed := false
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if !inTx(ctx) {
+			t.Fatal("context passed to fn carries no transaction")
+		}
+		afterCommit(ctx, func() { committed = true })
+		if err := addNote(ctx, db, "a"); err != nil {
+			return err
+		}
+		return addNote(ctx, db, "b")
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if got := countNotes(t, db); len(got) != 2 {
+		t.Fatalf("notes after commit = %v, want [a b]", got)
+	}
+	if !committed {
+		t.Fatal("after-commit hook did not run")
+	}
+}
+
+func TestUnitOfWorkRollsBackOnError(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+	errFail := errors.New("fail")
+
+	hookRan := false
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		afterCommit(ctx, func() { hookRan = true })
+		if err := addNote(ctx, db, "a"); err != nil {
+			return err
+		}
+		if err := addNote(ctx, db, "b"); err != nil {
+			return err
+		}
+		return errFail
+	})
+	if !errors.Is(err, errFail) {
+		t.Fatalf("Do error = %v, want %v", err, errFail)
+	}
+	if got := countNotes(t, db); len(got) != 0 {
+		t.Fatalf("notes after rollback = %v, want none", got)
+	}
+	if hookRan {
+		t.Fatal("after-commit hook ran after rollback")
+	}
+}
+
+func TestUnitOfWorkRollsBackOnPanic(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		_ = uow.Do(context.Background(), func(ctx context.Context) error {
+			if err := addNote(ctx, db, "a"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	if got := countNotes(t, db); len(got) != 0 {
+		t.Fatalf("notes after panic = %v, want none", got)
+	}
+}
+
+func TestUnitOfWorkNestedSavepoint(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+	errInner := errors.New("inner")
+
+	var hooks []string
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		afterCommit(ctx, func() { hooks = append(hooks, "outer") })
+		if err := addNote(ctx, db, "outer"); err != nil {
+			return err
+		}
+
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			afterCommit(ctx, func() { hooks = append(hooks, "failed") })
+			if err := addNote(ctx, db, "failed"); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			t.Fatalf("nested Do error = %v, want %v", err, errInner)
+		}
+
+		return uow.Do(ctx, func(ctx context.Context) error {
+			afterCommit(ctx, func() { hooks = append(hooks, "inner") })
+			return addNote(ctx, db, "inner")
+		})
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	got := countNotes(t, db)
+	if strings.Join(got, ",") != "outer,inner" {
+		t.Fatalf("notes = %v, want [outer inner]", got)
+	}
+	if strings.Join(hooks, ",") != "outer,inner" {
+		t.Fatalf("after-commit hooks = %v, want [outer inner]", hooks)
+	}
+}
+
+func TestUnitOfWorkBookingWithOutbox(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+	bookings := NewBookingRepository(db)
+	outbox := NewEventOutboxRepository(db)
+
+	newBooking := func() *models.Booking {
+		now := time.Now()
+		return &models.Booking{
+			ID:        uuid.New(),
+			UserID:    uuid.New(),
+			ClientID:  uuid.New(),
+			Title:     "Wedding",
+			Status:    models.BookingStatusDraft,
+			EventDate: now,
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+		}
+	}
+	newEvent := func(id uuid.UUID) *models.DomainEvent {
+		now := time.Now()
+		return &models.DomainEvent{
+			ID:            id,
+			Name:          "booking.created",
+			Payload:       []byte(`{}`),
+			Status:        models.EventStatusPending,
+			Handled:       []string{},
+			NextAttemptAt: &now,
+		}
+	}
+	create := func(booking *models.Booking, events []*models.DomainEvent) error {
+		return uow.Do(context.Background(), func(ctx context.Context) error {
+			if err := bookings.Create(ctx, booking); err != nil {
+				return err
+			}
+			return outbox.Add(ctx, events)
+		})
+	}
+	count := func(model interface{}) int64 {
+		var n int64
+		if err := db.Model(model).Count(&n).Error; err != nil {
+			t.Fatalf("count: %v", err)
+		}
+		return n
+	}
+
+	// Дублікат ідентифікатора події ламає вставку в outbox вже після створення бронювання
+	duplicate := uuid.New()
+	failed := newBooking()
+	if err := create(failed, []*models.DomainEvent{newEvent(duplicate), newEvent(duplicate)}); err == nil {
+		t.Fatal("expected outbox insert to fail")
+	}
+	if n := count(&models.Booking{}); n != 0 {
+		t.Fatalf("bookings after failed outbox insert = %d, want 0", n)
+	}
+	if n := count(&models.DomainEvent{}); n != 0 {
+		t.Fatalf("events after failed outbox insert = %d, want 0", n)
+	}
+
+	if err := create(newBooking(), []*models.DomainEvent{newEvent(uuid.New())}); err != nil {
+		t.Fatalf("create booking with event: %v", err)
+	}
+	if n := count(&models.Booking{}); n != 1 {
+		t.Fatalf("bookings = %d, want 1", n)
+	}
+	if n := count(&models.DomainEvent{}); n != 1 {
+		t.Fatalf("events = %d, want 1", n)
+	}
+}
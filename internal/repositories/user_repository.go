@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
 )
 
 // UserRepository handles database operations for users
@@ -26,6 +27,23 @@ type UserRepository interface {
 
 	// GetSubUsers retrieves all users under a specific admin
 	GetSubUsers(ctx context.Context, adminID uuid.UUID) ([]*models.User, error)
+
+	// ReserveStorage charges bytes against the user's quota, failing when the limit would be exceeded
+	ReserveStorage(ctx context.Context, userID uuid.UUID, bytes int64) error
+
+	// ReleaseStorage returns bytes to the user's quota
+	ReleaseStorage(ctx context.Context, userID uuid.UUID, bytes int64) error
+
+	// ListByNotificationSetting retrieves users whose notification setting key has one of the values.
+	// Users without the setting are matched by defaultValue.
+	ListByNotificationSetting(ctx context.Context, key string, values []string, defaultValue string) ([]*models.User, error)
+
+	// SetTelegramChatID links the user to a Telegram chat; an empty chatID unlinks it.
+	// The chat is unlinked from any other user first, so one chat belongs to a single account.
+	SetTelegramChatID(ctx context.Context, userID uuid.UUID, chatID string) error
+
+	// UnlinkTelegramChat unlinks a Telegram chat from whichever user it belongs to
+	UnlinkTelegramChat(ctx context.Context, chatID string) error
 }
 
 type userRepository struct {
@@ -40,12 +58,12 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&user, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
 		}
@@ -56,7 +74,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
 		}
@@ -67,7 +85,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 func (r *userRepository) GetByDomain(ctx context.Context, domain string) (*models.User, error) {
 	var user models.User
-	if err := r.db.WithContext(ctx).First(&user, "domain = ?", domain).Error; err != nil {
+	if err := conn(ctx, r.db).First(&user, "domain = ?", domain).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
 		}
@@ -78,23 +96,23 @@ func (r *userRepository) GetByDomain(ctx context.Context, domain string) (*model
 
 func (r *userRepository) GetSubUsers(ctx context.Context, adminID uuid.UUID) ([]*models.User, error) {
 	var users []*models.User
-	if err := r.db.WithContext(ctx).Where("parent_admin_id = ?", adminID).Find(&users).Error; err != nil {
+	if err := conn(ctx, r.db).Where("parent_admin_id = ?", adminID).Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return conn(ctx, r.db).Save(user).Error
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.User{}, "id = ?", id).Error
+	return conn(ctx, r.db).Delete(&models.User{}, "id = ?", id).Error
 }
 
 func (r *userRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.User, error) {
 	var users []*models.User
-	query := r.db.WithContext(ctx)
+	query := conn(ctx, r.db)
 
 	// Apply filters
 	for key, value := range filter {
@@ -106,3 +124,57 @@ func (r *userRepository) List(ctx context.Context, filter map[string]interface{}
 	}
 	return users, nil
 }
+
+func (r *userRepository) ReserveStorage(ctx context.Context, userID uuid.UUID, bytes int64) error {
+	result := conn(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND storage_used_bytes + ? <= storage_limit_gb::BIGINT * 1024 * 1024 * 1024", userID, bytes).
+		UpdateColumn("storage_used_bytes", gorm.Expr("storage_used_bytes + ?", bytes))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStorageQuotaExceeded
+	}
+	return nil
+}
+
+func (r *userRepository) ReleaseStorage(ctx context.Context, userID uuid.UUID, bytes int64) error {
+	return conn(ctx, r.db).Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumn("storage_used_bytes", gorm.Expr("GREATEST(storage_used_bytes - ?, 0)", bytes)).Error
+}
+
+func (r *userRepository) ListByNotificationSetting(ctx context.Context, key string, values []string, defaultValue string) ([]*models.User, error) {
+	var users []*models.User
+	err := conn(ctx, r.db).
+		Where("COALESCE(NULLIF(settings->'notification_settings'->>?, ''), ?) IN ?", key, defaultValue, values).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) SetTelegramChatID(ctx context.Context, userID uuid.UUID, chatID string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if chatID != "" {
+			err := tx.Model(&models.User{}).
+				Where("telegram_chat_id = ? AND id <> ?", chatID, userID).
+				Update("telegram_chat_id", nil).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var value interface{}
+		if chatID != "" {
+			value = chatID
+		}
+		return tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			Update("telegram_chat_id", value).Error
+	})
+}
+
+func (r *userRepository) UnlinkTelegramChat(ctx context.Context, chatID string) error {
+	return conn(ctx, r.db).Model(&models.User{}).
+		Where("telegram_chat_id = ?", chatID).
+		Update("telegram_chat_id", nil).Error
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"timebride/internal/models"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// WebhookRepository handles webhook subscriptions of users
+type WebhookRepository interface {
+	// Create stores a new subscription
+	Create(ctx context.Context, webhook *models.Webhook) error
+
+	// GetByID retrieves a subscription by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+
+	// ListByUser retrieves all subscriptions of a user, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error)
+
+	// ListActive retrieves active subscriptions of a user to the given event
+	ListActive(ctx context.Context, userID uuid.UUID, event models.WebhookEvent) ([]*models.Webhook, error)
+
+	// Update saves the URL, events and state of a subscription
+	Update(ctx context.Context, webhook *models.Webhook) error
+
+	// Delete removes a subscription together with its delivery log
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	return conn(ctx, r.db).Create(webhook).Error
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := conn(ctx, r.db).First(&webhook, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) ListActive(ctx context.Context, userID uuid.UUID, event models.WebhookEvent) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND active", userID).
+		Where("events @> ?", `["`+string(event)+`"]`).
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	// Серіалізатор поля не застосовується до оновлення через map
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+	return conn(ctx, r.db).
+		Model(&models.Webhook{}).
+		Where("id = ?", webhook.ID).
+		Updates(map[string]interface{}{
+			"url":    webhook.URL,
+			"events": datatypes.JSON(events),
+			"active": webhook.Active,
+		}).Error
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := conn(ctx, r.db).Delete(&models.Webhook{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// WebhookDeliveryRepository handles the queue and log of webhook requests
+type WebhookDeliveryRepository interface {
+	// Create queues a new delivery
+	Create(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// Enqueue queues a delivery of an event unless the event is already queued for the webhook;
+	// false means the delivery exists and nothing was stored
+	Enqueue(ctx context.Context, delivery *models.WebhookDelivery) (bool, error)
+
+	// GetByID retrieves a delivery by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+
+	// ListByWebhook returns the latest deliveries of a subscription, newest first
+	ListByWebhook(ctx context.Context, webhookID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+
+	// Claim returns pending deliveries whose next attempt is due and postpones them by lease,
+	// so concurrent workers do not send the same request twice
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error)
+
+	// Update saves the status, attempts, response and schedule of a delivery
+	Update(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return conn(ctx, r.db).Create(delivery).Error
+}
+
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "event_id"}, {Name: "webhook_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "replay_of IS NULL"}}},
+			DoNothing:   true,
+		}).
+		Create(delivery)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *webhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := conn(ctx, r.db).First(&delivery, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := conn(ctx, r.db).
+		Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return conn(ctx, r.db).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"sent_at":         delivery.SentAt,
+		}).Error
+}
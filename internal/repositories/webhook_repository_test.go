@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+func TestWebhookDeliveryEnqueueOncePerEvent(t *testing.T) {
+	db := openTestDB(t)
+	createTable(t, db, &models.WebhookDelivery{})
+	if err := db.Exec(`CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(event_id, webhook_id) WHERE replay_of IS NULL`).Error; err != nil {
+		t.Fatalf("create index: %v", err)
+	}
+	repo := NewWebhookDeliveryRepository(db)
+	ctx := context.Background()
+
+	eventID, webhookID := uuid.New(), uuid.New()
+	newDelivery := func(webhookID uuid.UUID) *models.WebhookDelivery {
+		now := time.Now()
+		return &models.WebhookDelivery{
+			WebhookID:     webhookID,
+			UserID:        uuid.New(),
+			EventID:       eventID,
+			Event:         models.WebhookEventBookingCreated,
+			Payload:       []byte(`{}`),
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: &now,
+		}
+	}
+	count := func() int64 {
+		var n int64
+		if err := db.Model(&models.WebhookDelivery{}).Count(&n).Error; err != nil {
+			t.Fatalf("count: %v", err)
+		}
+		return n
+	}
+
+	first := newDelivery(webhookID)
+	if queued, err := repo.Enqueue(ctx, first); err != nil || !queued {
+		t.Fatalf("first Enqueue = %v, %v; want true, nil", queued, err)
+	}
+	// Повторна обробка тієї ж події шиною
+	if queued, err := repo.Enqueue(ctx, newDelivery(webhookID)); err != nil || queued {
+		t.Fatalf("repeated Enqueue = %v, %v; want false, nil", queued, err)
+	}
+	// Та сама подія для іншої підписки
+	if queued, err := repo.Enqueue(ctx, newDelivery(uuid.New())); err != nil || !queued {
+		t.Fatalf("Enqueue for another webhook = %v, %v; want true, nil", queued, err)
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("deliveries = %d, want 2", n)
+	}
+
+	// Повторне надсилання з журналу зберігається окремою доставкою
+	replay := newDelivery(webhookID)
+	replay.ReplayOf = &first.ID
+	if err := repo.Create(ctx, replay); err != nil {
+		t.Fatalf("create replay: %v", err)
+	}
+	if n := count(); n != 3 {
+		t.Fatalf("deliveries after replay = %d, want 3", n)
+	}
+}
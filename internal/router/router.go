@@ -1,6 +1,8 @@
 package router
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -9,18 +11,31 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/gofiber/template/html/v2"
 
+	"timebride/internal/config"
 	"timebride/internal/handlers"
 	"timebride/internal/middleware"
+	"timebride/internal/models"
 	"timebride/internal/utils"
 )
 
+// Обмеження кількості запитів до публічних сторінок з однієї IP-адреси за хвилину
+const (
+	galleryPageRateLimit   = 60
+	galleryFileRateLimit   = 120
+	galleryThumbRateLimit  = 1200
+	galleryUnlockRateLimit = 10
+	galleryUploadRateLimit = 30
+	gallerySignRateLimit   = 10
+)
+
 type Router struct {
 	app          *fiber.App
 	sessionStore *session.Store
 	handlers     *handlers.Handlers
+	config       *config.Config
 }
 
-func New(h *handlers.Handlers) *Router {
+func New(h *handlers.Handlers, cfg *config.Config) *Router {
 	// Ініціалізуємо HTML шаблонізатор
 	engine := html.New("./web/templates", ".html")
 	engine.Reload(true) // Enable reload in development
@@ -29,8 +44,13 @@ func New(h *handlers.Handlers) *Router {
 	engine.AddFuncMap(utils.TemplateFunctions())
 
 	// Створюємо додаток Fiber
+	// Тіло понад BodyLimit не буферизується, а читається потоком; розмір обмежує middleware.BodyLimit.
+	// Попередній розбір multipart вимкнено: він читав би форму до перевірки ліміту.
 	app := fiber.New(fiber.Config{
-		Views: engine,
+		Views:                        engine,
+		BodyLimit:                    fiber.DefaultBodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
 	// Ініціалізуємо сесії
@@ -39,6 +59,18 @@ func New(h *handlers.Handlers) *Router {
 	// Додаємо middleware
 	app.Use(logger.New())
 	app.Use(recover.New())
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit,
+		middleware.UploadLimit{
+			Method: fiber.MethodPost,
+			Path:   "/app/storage/upload",
+			Limit:  cfg.Storage.GetUploadMaxSize() + middleware.MultipartOverhead,
+		},
+		middleware.UploadLimit{
+			Method: fiber.MethodPost,
+			Path:   "/g/:token/uploads",
+			Limit:  cfg.Storage.GetClientUploadMaxSize() + middleware.MultipartOverhead,
+		},
+	))
 	app.Use(compress.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
@@ -59,6 +91,7 @@ func New(h *handlers.Handlers) *Router {
 		app:          app,
 		sessionStore: store,
 		handlers:     h,
+		config:       cfg,
 	}
 }
 
@@ -74,6 +107,22 @@ func (r *Router) SetupRoutes() {
 	r.app.Get("/oauth/:provider", r.handlers.Auth.OAuthRedirect)
 	r.app.Get("/oauth/:provider/callback", r.handlers.Auth.OAuthCallback)
 
+	// Файли за підписаними посиланнями
+	r.app.Get("/files/*", r.handlers.Storage.ServeSigned)
+
+	// Публічні сторінки клієнтів
+	r.app.Get("/g/:token", middleware.RateLimit(galleryPageRateLimit, time.Minute), r.handlers.Galleries.Show)
+	r.app.Post("/g/:token/unlock", middleware.RateLimit(galleryUnlockRateLimit, time.Minute), r.handlers.Galleries.Unlock)
+	r.app.Get("/g/:token/files/:file", middleware.RateLimit(galleryFileRateLimit, time.Minute), r.handlers.Galleries.File)
+	r.app.Get("/g/:token/thumbs/:file", middleware.RateLimit(galleryThumbRateLimit, time.Minute), r.handlers.Galleries.Thumbnail)
+	r.app.Get("/g/:token/archive", middleware.RateLimit(galleryFileRateLimit, time.Minute), r.handlers.Galleries.Archive)
+	r.app.Get("/g/:token/proofing", middleware.RateLimit(galleryPageRateLimit, time.Minute), r.handlers.Galleries.Proofs)
+	r.app.Post("/g/:token/proofing/submit", middleware.RateLimit(galleryPageRateLimit, time.Minute), r.handlers.Galleries.SubmitSelection)
+	r.app.Put("/g/:token/proofing/:file", middleware.RateLimit(galleryFileRateLimit, time.Minute), r.handlers.Galleries.MarkProof)
+	r.app.Post("/g/:token/uploads", middleware.RateLimit(galleryUploadRateLimit, time.Minute), r.handlers.Galleries.Upload)
+	r.app.Get("/g/:token/contract", middleware.RateLimit(galleryFileRateLimit, time.Minute), r.handlers.Galleries.Contract)
+	r.app.Post("/g/:token/contract/sign", middleware.RateLimit(gallerySignRateLimit, time.Minute), r.handlers.Galleries.SignContract)
+
 	// Захищені маршрути
 	app := r.app.Group("/app")
 
@@ -90,6 +139,7 @@ func (r *Router) SetupRoutes() {
 	app.Get("/bookings/:id", r.handlers.Bookings.Get)
 	app.Put("/bookings/:id", r.handlers.Bookings.Update)
 	app.Delete("/bookings/:id", r.handlers.Bookings.Delete)
+	app.Get("/deadlines", r.handlers.Bookings.Deadlines)
 
 	// Клієнти
 	app.Get("/clients", r.handlers.Clients.List)
@@ -112,16 +162,89 @@ func (r *Router) SetupRoutes() {
 	app.Put("/prices/:id", r.handlers.Prices.Update)
 	app.Delete("/prices/:id", r.handlers.Prices.Delete)
 
+	// Шаблони документів
+	app.Get("/templates", r.handlers.Templates.List)
+	app.Post("/templates", r.handlers.Templates.Create)
+	app.Get("/templates/fields", r.handlers.Templates.Fields)
+	app.Get("/templates/email-events", r.handlers.Templates.EmailEvents)
+	app.Post("/templates/preview", r.handlers.Templates.Preview)
+	app.Get("/templates/:id", r.handlers.Templates.Get)
+	app.Put("/templates/:id", r.handlers.Templates.Update)
+	app.Delete("/templates/:id", r.handlers.Templates.Delete)
+	app.Get("/templates/:id/render", r.handlers.Templates.Render)
+
+	// Договори
+	app.Get("/contracts", r.handlers.Contracts.List)
+	app.Get("/bookings/:id/contracts", r.handlers.Contracts.Versions)
+	app.Post("/bookings/:id/contracts", r.handlers.Contracts.Generate)
+	app.Delete("/bookings/:id/contracts", r.handlers.Contracts.Delete)
+	app.Post("/bookings/:id/contracts/file", r.handlers.Contracts.Replace)
+	app.Post("/bookings/:id/contracts/send", r.handlers.Contracts.Send)
+	app.Get("/contracts/:id/file", r.handlers.Contracts.File)
+
 	// Файли
 	app.Get("/storage", r.handlers.Storage.List)
 	app.Post("/storage/upload", r.handlers.Storage.Upload)
+	app.Get("/storage/archive", r.handlers.Storage.Archive)
+	app.Post("/storage/archive", r.handlers.Storage.Archive)
+	app.Get("/storage/trash", r.handlers.Storage.Trash)
+	app.Delete("/storage/trash", r.handlers.Storage.EmptyTrash)
+	app.Post("/storage/trash/:id/restore", r.handlers.Storage.Restore)
+	app.Delete("/storage/trash/:id", r.handlers.Storage.Purge)
 	app.Get("/storage/:id", r.handlers.Storage.Download)
+	app.Post("/storage/:id/link", r.handlers.Storage.CreateLink)
 	app.Delete("/storage/:id", r.handlers.Storage.Delete)
 
+	// Публічні галереї бронювань
+	app.Get("/bookings/:id/gallery", r.handlers.Galleries.Get)
+	app.Post("/bookings/:id/gallery", r.handlers.Galleries.Generate)
+	app.Put("/bookings/:id/gallery", r.handlers.Galleries.Update)
+	app.Delete("/bookings/:id/gallery", r.handlers.Galleries.Disable)
+	app.Post("/bookings/:id/gallery/extend", r.handlers.Galleries.Extend)
+	app.Get("/bookings/:id/gallery/activity", r.handlers.Galleries.Activity)
+	app.Get("/bookings/:id/gallery/selection", r.handlers.Galleries.Selection)
+	app.Get("/bookings/:id/gallery/selection.csv", r.handlers.Galleries.SelectionCSV)
+	app.Post("/bookings/:id/gallery/selection/reopen", r.handlers.Galleries.ReopenSelection)
+	app.Get("/bookings/:id/gallery/uploads", r.handlers.Galleries.ClientUploads)
+	app.Put("/gallery/settings", r.handlers.Galleries.UpdateBranding)
+	app.Get("/gallery/watermark", r.handlers.Galleries.Watermark)
+	app.Put("/gallery/watermark", r.handlers.Galleries.UpdateWatermark)
+	app.Post("/gallery/watermark/logo", r.handlers.Galleries.UploadWatermarkLogo)
+	app.Get("/galleries/activity", r.handlers.Galleries.ActivitySummary)
+
+	// Сповіщення
+	app.Get("/notifications", r.handlers.Notifications.List)
+	app.Post("/notifications/read", r.handlers.Notifications.MarkAllRead)
+	app.Get("/notifications/deliveries", r.handlers.Notifications.Deliveries)
+	app.Post("/notifications/:id/read", r.handlers.Notifications.MarkRead)
+
+	// Вебхуки
+	app.Get("/webhooks", r.handlers.Webhooks.List)
+	app.Post("/webhooks", r.handlers.Webhooks.Create)
+	app.Get("/webhooks/events", r.handlers.Webhooks.Events)
+	app.Post("/webhooks/deliveries/:id/replay", r.handlers.Webhooks.Replay)
+	app.Put("/webhooks/:id", r.handlers.Webhooks.Update)
+	app.Delete("/webhooks/:id", r.handlers.Webhooks.Delete)
+	app.Get("/webhooks/:id/deliveries", r.handlers.Webhooks.Deliveries)
+	app.Post("/webhooks/:id/ping", r.handlers.Webhooks.Ping)
+
+	// Черга фонових задач (адміністратор)
+	admin := app.Group("/admin", middleware.RequireRole(models.RoleAdmin))
+	admin.Get("/jobs", r.handlers.Jobs.List)
+	admin.Post("/jobs/:id/retry", r.handlers.Jobs.Retry)
+
 	// Профіль користувача
 	app.Get("/profile", r.handlers.Users.Get)
 	app.Put("/profile", r.handlers.Users.Update)
 	app.Get("/settings", r.handlers.Settings)
+	app.Get("/settings/notifications", r.handlers.Users.NotificationSettings)
+	app.Put("/settings/notifications", r.handlers.Users.UpdateNotificationSettings)
+	app.Get("/settings/telegram", r.handlers.Notifications.TelegramStatus)
+	app.Post("/settings/telegram/link", r.handlers.Notifications.TelegramLink)
+	app.Delete("/settings/telegram", r.handlers.Notifications.UnlinkTelegram)
+	app.Get("/settings/digest/preview", r.handlers.Notifications.DigestPreview)
+	app.Get("/settings/requisites", r.handlers.Users.Requisites)
+	app.Put("/settings/requisites", r.handlers.Users.UpdateRequisites)
 }
 
 func (r *Router) Start(addr string) error {
@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize - розмір частини потоку INSTREAM
+const clamdChunkSize = 64 * 1024
+
+var ErrClamdResponse = errors.New("unexpected clamd response")
+
+// ClamdScanner перевіряє файли через демон ClamAV за протоколом INSTREAM
+type ClamdScanner struct {
+	network string
+	addr    string
+	timeout time.Duration
+}
+
+// NewClamdScanner створює сканер для clamd; addr може бути "host:port"
+// або "unix:/path/to/clamd.sock"
+func NewClamdScanner(addr string, timeout time.Duration) *ClamdScanner {
+	network := "tcp"
+	if strings.HasPrefix(addr, "unix:") {
+		network = "unix"
+		addr = strings.TrimPrefix(addr, "unix:")
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	return &ClamdScanner{network: network, addr: addr, timeout: timeout}
+}
+
+// Scan передає вміст у clamd та розбирає відповідь
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, s.network, s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if err := s.stream(conn, r); err != nil {
+		return nil, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read clamd response: %w", err)
+	}
+	return parseClamdReply(reply)
+}
+
+// stream надсилає команду INSTREAM: частини з 4-байтовою довжиною та нульовий блок в кінці
+func (s *ClamdScanner) stream(w io.Writer, r io.Reader) error {
+	if _, err := w.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("failed to send clamd command: %w", err)
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	var size [4]byte
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := w.Write(size[:]); err != nil {
+				return fmt.Errorf("failed to stream to clamd: %w", err)
+			}
+			if _, err := w.Write(buf[:n]); err != nil {
+				return fmt.Errorf("failed to stream to clamd: %w", err)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := w.Write(size[:]); err != nil {
+		return fmt.Errorf("failed to stream to clamd: %w", err)
+	}
+	return nil
+}
+
+// parseClamdReply розбирає відповіді виду "stream: OK" та "stream: <signature> FOUND"
+func parseClamdReply(reply string) (*Result, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	reply = strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case reply == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return &Result{
+			Infected:  true,
+			Signature: strings.TrimSuffix(reply, " FOUND"),
+		}, nil
+	default:
+		// "... ERROR", наприклад "INSTREAM size limit exceeded. ERROR"
+		return nil, fmt.Errorf("%w: %q", ErrClamdResponse, reply)
+	}
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// EICAR - стандартний тестовий рядок, який антивіруси розпізнають як загрозу
+const EICAR = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!H+H*`
+
+// FakeScanner позначає як заражені файли, що містять тестовий рядок EICAR.
+// Призначений для тестів та локальної розробки без clamd.
+type FakeScanner struct {
+	mu sync.Mutex
+	// Err, якщо задано, повертається замість результату
+	Err     error
+	scanned int
+}
+
+// NewFakeScanner створює фейковий сканер
+func NewFakeScanner() *FakeScanner {
+	return &FakeScanner{}
+}
+
+// Scan шукає рядок EICAR у вмісті
+func (s *FakeScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	s.mu.Lock()
+	s.scanned++
+	err := s.Err
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(data, []byte(EICAR)) {
+		return &Result{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return &Result{}, nil
+}
+
+// Scanned повертає кількість перевірених файлів
+func (s *FakeScanner) Scanned() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scanned
+}
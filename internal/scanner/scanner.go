@@ -0,0 +1,33 @@
+// Package scanner містить перевірку завантажених файлів на шкідливе ПЗ
+package scanner
+
+import (
+	"context"
+	"io"
+)
+
+// Result містить результат перевірки файлу
+type Result struct {
+	// Infected - чи знайдено загрозу
+	Infected bool
+	// Signature - назва знайденої загрози
+	Signature string
+}
+
+// Scanner перевіряє вміст файлу на шкідливе ПЗ
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+// NoopScanner вважає чистими всі файли; використовується, коли антивірус не налаштований
+type NoopScanner struct{}
+
+// NewNoopScanner створює сканер, що нічого не перевіряє
+func NewNoopScanner() NoopScanner {
+	return NoopScanner{}
+}
+
+// Scan повертає чистий результат без читання вмісту
+func (NoopScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	return &Result{}, nil
+}
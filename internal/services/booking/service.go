@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"timebride/internal/events"
 	"timebride/internal/models"
 	"timebride/internal/repositories"
 )
@@ -14,16 +15,23 @@ import (
 type Service struct {
 	bookingRepo repositories.BookingRepository
 	clientRepo  repositories.ClientRepository
+	outboxRepo  repositories.EventOutboxRepository
+	tx          repositories.UnitOfWork
 }
 
-// NewService створює новий екземпляр сервісу бронювань
+// NewService створює новий екземпляр сервісу бронювань.
+// Бронювання та його доменні події записуються в одній транзакції tx.
 func NewService(
 	bookingRepo repositories.BookingRepository,
 	clientRepo repositories.ClientRepository,
+	outboxRepo repositories.EventOutboxRepository,
+	tx repositories.UnitOfWork,
 ) IBookingService {
 	return &Service{
 		bookingRepo: bookingRepo,
 		clientRepo:  clientRepo,
+		outboxRepo:  outboxRepo,
+		tx:          tx,
 	}
 }
 
@@ -60,9 +68,25 @@ func (s *Service) Create(ctx context.Context, input *models.BookingCreate) (*mod
 		DeadlineDays: input.DeadlineDays,
 		PriceTotal:   input.Amount,
 		TeamMembers:  input.TeamMembers,
-	}
 
-	if err := s.bookingRepo.Create(ctx, booking); err != nil {
+		PricePrepayment: input.Prepayment,
+	}
+	booking.RefreshOverdue(time.Now())
+
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.bookingRepo.Create(ctx, booking); err != nil {
+			return err
+		}
+		// Подія описує збережене бронювання разом з полями, які заповнює база
+		changes := []events.Event{&events.BookingCreated{Booking: booking}}
+		changes = appendPayment(changes, booking, 0)
+		records, err := events.Record(changes...)
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, records)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -75,6 +99,8 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, input *models.Bookin
 	if err != nil {
 		return nil, err
 	}
+	previousStatus := booking.Status
+	previousPaid := booking.PricePrepayment
 
 	if input.Title != nil {
 		booking.Title = *input.Title
@@ -82,6 +108,9 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, input *models.Bookin
 	if input.EventType != nil {
 		booking.EventType = *input.EventType
 	}
+	if input.EventDate != nil {
+		booking.EventDate = *input.EventDate
+	}
 	if input.StartTime != nil {
 		booking.StartTime = *input.StartTime
 	}
@@ -100,6 +129,9 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, input *models.Bookin
 	if input.Amount != nil {
 		booking.PriceTotal = *input.Amount
 	}
+	if input.Prepayment != nil {
+		booking.PricePrepayment = *input.Prepayment
+	}
 	if input.PackageName != nil {
 		booking.PackageName = *input.PackageName
 	}
@@ -109,14 +141,47 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, input *models.Bookin
 	if input.TeamMembers != nil {
 		booking.TeamMembers = *input.TeamMembers
 	}
+	// Статус, дата події або термін віддачі могли змінитися
+	booking.RefreshOverdue(time.Now())
 
-	if err := s.bookingRepo.Update(ctx, booking); err != nil {
+	var changes []events.Event
+	if booking.Status != previousStatus {
+		changes = append(changes, &events.BookingStatusChanged{Booking: booking, PreviousStatus: previousStatus})
+	}
+	changes = appendPayment(changes, booking, previousPaid)
+	records, err := events.Record(changes...)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.bookingRepo.Update(ctx, booking); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, records)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return booking, nil
 }
 
+// appendPayment додає подію оплати, якщо отримана сума бронювання зросла
+func appendPayment(changes []events.Event, booking *models.Booking, previousPaid float64) []events.Event {
+	if booking.PricePrepayment <= previousPaid {
+		return changes
+	}
+	return append(changes, &events.PaymentReceived{
+		BookingID:    booking.ID,
+		BookingTitle: booking.Title,
+		UserID:       booking.UserID,
+		ClientID:     booking.ClientID,
+		Amount:       booking.PricePrepayment - previousPaid,
+		TotalPaid:    booking.PricePrepayment,
+		Balance:      booking.PriceTotal + booking.PriceExtra - booking.PricePrepayment,
+	})
+}
+
 // Delete видаляє бронювання
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.bookingRepo.Delete(ctx, id)
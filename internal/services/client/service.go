@@ -3,8 +3,8 @@ package client
 import (
 	"context"
 	"errors"
+	"log"
 	"mime/multipart"
-	"path/filepath"
 
 	"github.com/google/uuid"
 
@@ -16,26 +16,28 @@ import (
 // Service реалізує інтерфейс IClientService
 type Service struct {
 	clientRepo repositories.ClientRepository
-	fileRepo   repositories.FileRepository
 	storage    storage.IStorageService
 }
 
 // NewService створює новий екземпляр сервісу клієнтів
 func NewService(
 	clientRepo repositories.ClientRepository,
-	fileRepo repositories.FileRepository,
 	storage storage.IStorageService,
 ) *Service {
 	return &Service{
 		clientRepo: clientRepo,
-		fileRepo:   fileRepo,
 		storage:    storage,
 	}
 }
 
 // Get отримує клієнта за ID
 func (s *Service) Get(ctx context.Context, id uuid.UUID) (*models.Client, error) {
-	return s.clientRepo.GetByID(ctx, id)
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.signAvatars(ctx, client)
+	return client, nil
 }
 
 // Create створює нового клієнта
@@ -84,6 +86,7 @@ func (s *Service) List(ctx context.Context, userID uuid.UUID, page, pageSize int
 		}
 		clients = clients[start:end]
 	}
+	s.signAvatars(ctx, clients...)
 
 	return &models.ClientListResult{
 		Items:      clients,
@@ -95,10 +98,16 @@ func (s *Service) List(ctx context.Context, userID uuid.UUID, page, pageSize int
 
 // GetByUserID отримує всіх клієнтів користувача
 func (s *Service) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Client, error) {
-	return s.clientRepo.GetByUserID(ctx, userID)
+	clients, err := s.clientRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.signAvatars(ctx, clients...)
+	return clients, nil
 }
 
-// UploadAvatar завантажує аватар для клієнта
+// UploadAvatar завантажує аватар для клієнта і повертає підписаний URL.
+// Клієнт зберігає ідентифікатор файлу, бо підписані посилання мають обмежений термін дії.
 func (s *Service) UploadAvatar(ctx context.Context, clientID uuid.UUID, file *multipart.FileHeader) (string, error) {
 	if file == nil {
 		return "", errors.New("file is required")
@@ -109,40 +118,31 @@ func (s *Service) UploadAvatar(ctx context.Context, clientID uuid.UUID, file *mu
 		return "", err
 	}
 
-	// Генеруємо унікальне ім'я файлу
-	ext := filepath.Ext(file.Filename)
-	filename := "avatars/" + uuid.New().String() + ext
-
 	// Завантажуємо файл
-	url, err := s.storage.UploadFile(ctx, file, filename)
+	uploaded, err := s.storage.UploadFile(ctx, &storage.UploadInput{
+		UserID: client.UserID,
+		Type:   models.FileTypeAvatar,
+		File:   file,
+	})
 	if err != nil {
 		return "", err
 	}
 
-	// Створюємо запис про файл
-	fileModel := &models.File{
-		UserID:      client.UserID,
-		Name:        file.Filename,
-		Path:        filename,
-		URL:         url,
-		Size:        file.Size,
-		ContentType: file.Header.Get("Content-Type"),
-		Type:        models.FileTypeAvatar,
-	}
-
-	if err := s.fileRepo.Create(ctx, fileModel); err != nil {
-		// Якщо не вдалося створити запис, видаляємо файл
-		_ = s.storage.DeleteFile(ctx, filename)
-		return "", err
-	}
-
 	// Оновлюємо аватар клієнта
-	client.Avatar = url
+	previous := client.AvatarFileID
+	client.AvatarFileID = &uploaded.ID
 	if err := s.clientRepo.Update(ctx, client); err != nil {
 		return "", err
 	}
 
-	return url, nil
+	// Попередній аватар більше ніде не використовується
+	if previous != nil && *previous != uploaded.ID {
+		if err := s.storage.DeleteFile(ctx, *previous); err != nil {
+			log.Printf("Failed to delete previous avatar %s of client %s: %v", *previous, client.ID, err)
+		}
+	}
+
+	return s.storage.GetFileURL(ctx, uploaded), nil
 }
 
 // DeleteAvatar видаляє аватар клієнта
@@ -152,29 +152,32 @@ func (s *Service) DeleteAvatar(ctx context.Context, clientID uuid.UUID) error {
 		return err
 	}
 
-	if client.Avatar == "" {
+	if client.AvatarFileID == nil {
 		return nil
 	}
 
-	// Знаходимо файл за URL
-	filter := map[string]interface{}{"url": client.Avatar}
-	files, err := s.fileRepo.List(ctx, filter)
-	if err != nil {
+	fileID := *client.AvatarFileID
+	client.AvatarFileID = nil
+	if err := s.clientRepo.Update(ctx, client); err != nil {
 		return err
 	}
+	return s.storage.DeleteFile(ctx, fileID)
+}
 
-	if len(files) > 0 {
-		file := files[0]
-		if err := s.storage.DeleteFile(ctx, file.Path); err != nil {
-			return err
+// signAvatars заповнює підписані URL аватарів клієнтів.
+// Клієнт без доступного файлу аватара віддається без нього.
+func (s *Service) signAvatars(ctx context.Context, clients ...*models.Client) {
+	for _, client := range clients {
+		if client.AvatarFileID == nil {
+			continue
 		}
-		if err := s.fileRepo.Delete(ctx, file.ID); err != nil {
-			return err
+		file, err := s.storage.GetFile(ctx, *client.AvatarFileID)
+		if err != nil {
+			log.Printf("Failed to load avatar %s of client %s: %v", *client.AvatarFileID, client.ID, err)
+			continue
 		}
+		client.Avatar = s.storage.GetFileURL(ctx, file)
 	}
-
-	client.Avatar = ""
-	return s.clientRepo.Update(ctx, client)
 }
 
 // GetCategories отримує всі категорії клієнтів
@@ -231,6 +234,7 @@ func (s *Service) ListClients(ctx context.Context, userID uuid.UUID, options mod
 		}
 		clients = clients[start:end]
 	}
+	s.signAvatars(ctx, clients...)
 
 	return &models.ClientListResult{
 		Items:      clients,
@@ -251,6 +255,7 @@ func (s *Service) GetClient(ctx context.Context, userID uuid.UUID, clientID uuid
 		return nil, errors.New("client does not belong to user")
 	}
 
+	s.signAvatars(ctx, client)
 	return client, nil
 }
 
@@ -273,9 +278,12 @@ func (s *Service) UpdateClient(ctx context.Context, client *models.Client) (*mod
 		return nil, errors.New("client does not belong to user")
 	}
 
+	// Аватар змінюється лише через UploadAvatar та DeleteAvatar
+	client.AvatarFileID = existing.AvatarFileID
 	if err := s.clientRepo.Update(ctx, client); err != nil {
 		return nil, err
 	}
+	s.signAvatars(ctx, client)
 	return client, nil
 }
 
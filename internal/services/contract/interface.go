@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"context"
+	"mime/multipart"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// IContractService визначає інтерфейс сервісу договорів бронювань
+type IContractService interface {
+	// Generate формує PDF договору з шаблону та зберігає його як нову версію
+	Generate(ctx context.Context, userID, bookingID, templateID uuid.UUID) (*models.Contract, error)
+
+	// Versions повертає всі версії договору бронювання, від найновішої
+	Versions(ctx context.Context, userID, bookingID uuid.UUID) ([]*models.Contract, error)
+
+	// Get повертає версію договору користувача
+	Get(ctx context.Context, userID, contractID uuid.UUID) (*models.Contract, error)
+
+	// List повертає останні версії договорів усіх бронювань користувача за фільтрами
+	List(ctx context.Context, userID uuid.UUID, opts models.ContractListOptions) ([]*models.Contract, error)
+
+	// Replace зберігає завантажений PDF як нову версію договору бронювання
+	Replace(ctx context.Context, userID, bookingID uuid.UUID, header *multipart.FileHeader) (*models.Contract, error)
+
+	// Delete видаляє договір бронювання з усіма версіями, якщо жодну з них не підписано
+	Delete(ctx context.Context, userID, bookingID uuid.UUID) error
+
+	// Send відкриває останню версію договору для підписання на публічній сторінці
+	Send(ctx context.Context, userID, bookingID uuid.UUID) (*models.Contract, error)
+
+	// ForClient повертає надіслану клієнту або підписану версію договору бронювання
+	ForClient(ctx context.Context, bookingID uuid.UUID) (*models.Contract, error)
+
+	// Sign фіксує підпис клієнта, формує підписаний примірник з журналом підписання,
+	// підтверджує бронювання та надсилає примірник обом сторонам
+	Sign(ctx context.Context, bookingID uuid.UUID, input *SignInput) (*models.Contract, error)
+}
+
+// SignInput містить дані підписання договору клієнтом
+type SignInput struct {
+	// ContractID - версія, яку клієнт переглянув
+	ContractID uuid.UUID
+	Name       string
+	// Signature - намальований підпис у форматі PNG
+	Signature []byte
+	IP        string
+	UserAgent string
+}
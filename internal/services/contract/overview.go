@@ -0,0 +1,111 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/pdf"
+	"timebride/internal/services/storage"
+)
+
+var (
+	ErrNotPDF            = errors.New("contract file must be a pdf document")
+	ErrInvalidListFilter = errors.New("unknown contract status")
+)
+
+// List повертає останні версії договорів усіх бронювань користувача з бронюванням та клієнтом
+func (s *contractService) List(ctx context.Context, userID uuid.UUID, opts models.ContractListOptions) ([]*models.Contract, error) {
+	if opts.Status != "" && !opts.Status.IsValid() {
+		return nil, ErrInvalidListFilter
+	}
+	opts.Search = strings.TrimSpace(opts.Search)
+	return s.contractRepo.List(ctx, userID, opts)
+}
+
+// Replace зберігає завантажений PDF як нову версію договору бронювання.
+// Попередні версії, зокрема підписані, залишаються в історії.
+func (s *contractService) Replace(ctx context.Context, userID, bookingID uuid.UUID, header *multipart.FileHeader) (*models.Contract, error) {
+	booking, err := s.ownedBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(filepath.Ext(header.Filename), ".pdf") {
+		return nil, ErrNotPDF
+	}
+
+	content, err := readUpload(header)
+	if err != nil {
+		return nil, err
+	}
+	// Файл має відкриватися імпортом, інакше до нього не вдасться додати журнал підписання
+	if _, err := pdf.Import(header.Filename, content); err != nil {
+		return nil, ErrNotPDF
+	}
+
+	file, err := s.storage.UploadFile(ctx, &storage.UploadInput{
+		UserID:      userID,
+		BookingID:   &booking.ID,
+		Type:        models.FileTypeDocument,
+		Folder:      models.FolderContracts,
+		Content:     bytes.NewReader(content),
+		Name:        filepath.Base(header.Filename),
+		ContentType: "application/pdf",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store contract: %w", err)
+	}
+
+	contract := &models.Contract{
+		UserID:    userID,
+		BookingID: booking.ID,
+		FileID:    file.ID,
+		Status:    models.ContractStatusDraft,
+		File:      file,
+	}
+	if err := s.contractRepo.CreateVersion(ctx, contract); err != nil {
+		if delErr := s.storage.DeleteFile(ctx, file.ID); delErr != nil {
+			log.Printf("Failed to remove unused contract file %s: %v", file.ID, delErr)
+		}
+		return nil, fmt.Errorf("failed to save contract version: %w", err)
+	}
+	return contract, nil
+}
+
+// Delete видаляє договір бронювання з усіма версіями; файли переміщуються в кошик.
+// Підписаний договір разом із підписами клієнта не видаляється.
+func (s *contractService) Delete(ctx context.Context, userID, bookingID uuid.UUID) error {
+	if _, err := s.ownedBooking(ctx, userID, bookingID); err != nil {
+		return err
+	}
+
+	fileIDs, err := s.contractRepo.DeleteByBooking(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	for _, id := range fileIDs {
+		if err := s.storage.DeleteFile(ctx, id); err != nil {
+			log.Printf("Failed to move contract file %s to trash: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// readUpload читає завантажений файл у пам'ять
+func readUpload(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload: %w", err)
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
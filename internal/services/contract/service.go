@@ -0,0 +1,155 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/pdf"
+	"timebride/internal/repositories"
+	"timebride/internal/services/email"
+	"timebride/internal/services/notification"
+	"timebride/internal/services/storage"
+	"timebride/internal/services/template"
+)
+
+var ErrNotContractTemplate = errors.New("template is not a contract template")
+
+type contractService struct {
+	contractRepo repositories.ContractRepository
+	bookingRepo  repositories.BookingRepository
+	clientRepo   repositories.ClientRepository
+	userRepo     repositories.UserRepository
+	templates    template.ITemplateService
+	storage      storage.IStorageService
+	notifier     notification.INotificationService
+	email        email.IEmailService
+	outboxRepo   repositories.EventOutboxRepository
+	tx           repositories.UnitOfWork
+}
+
+// NewContractService створює новий сервіс договорів
+func NewContractService(
+	contractRepo repositories.ContractRepository,
+	bookingRepo repositories.BookingRepository,
+	clientRepo repositories.ClientRepository,
+	userRepo repositories.UserRepository,
+	templates template.ITemplateService,
+	storageService storage.IStorageService,
+	notifier notification.INotificationService,
+	emailService email.IEmailService,
+	outboxRepo repositories.EventOutboxRepository,
+	tx repositories.UnitOfWork,
+) IContractService {
+	return &contractService{
+		contractRepo: contractRepo,
+		bookingRepo:  bookingRepo,
+		clientRepo:   clientRepo,
+		userRepo:     userRepo,
+		templates:    templates,
+		storage:      storageService,
+		notifier:     notifier,
+		email:        emailService,
+		outboxRepo:   outboxRepo,
+		tx:           tx,
+	}
+}
+
+// Generate заповнює шаблон даними бронювання, перетворює його на PDF та зберігає
+// в сховищі як документ бронювання. Попередні версії залишаються в історії.
+func (s *contractService) Generate(ctx context.Context, userID, bookingID, templateID uuid.UUID) (*models.Contract, error) {
+	booking, err := s.ownedBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.templates.GetByID(ctx, templateID)
+	if err != nil || tmpl.UserID != userID {
+		return nil, repositories.ErrTemplateNotFound
+	}
+	if tmpl.Type != models.TemplateTypeContract {
+		return nil, ErrNotContractTemplate
+	}
+
+	rendered, err := s.templates.Render(ctx, userID, templateID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := rendered.Subject
+	if title == "" {
+		title = "Договір - " + booking.Title
+	}
+	content, err := pdf.Render(title, rendered.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.storage.UploadFile(ctx, &storage.UploadInput{
+		UserID:      userID,
+		BookingID:   &booking.ID,
+		Type:        models.FileTypeDocument,
+		Folder:      models.FolderContracts,
+		Content:     bytes.NewReader(content),
+		Name:        strings.ReplaceAll(title, "/", "-") + ".pdf",
+		ContentType: "application/pdf",
+		Generated:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store contract: %w", err)
+	}
+
+	contract := &models.Contract{
+		UserID:     userID,
+		BookingID:  booking.ID,
+		TemplateID: &tmpl.ID,
+		FileID:     file.ID,
+		Status:     models.ContractStatusDraft,
+		File:       file,
+	}
+	if err := s.contractRepo.CreateVersion(ctx, contract); err != nil {
+		if delErr := s.storage.DeleteFile(ctx, file.ID); delErr != nil {
+			log.Printf("Failed to remove unused contract file %s: %v", file.ID, delErr)
+		}
+		return nil, fmt.Errorf("failed to save contract version: %w", err)
+	}
+	return contract, nil
+}
+
+// Versions повертає всі версії договору бронювання, від найновішої
+func (s *contractService) Versions(ctx context.Context, userID, bookingID uuid.UUID) ([]*models.Contract, error) {
+	if _, err := s.ownedBooking(ctx, userID, bookingID); err != nil {
+		return nil, err
+	}
+	return s.contractRepo.ListByBooking(ctx, bookingID)
+}
+
+// Get повертає версію договору, якщо вона належить користувачу
+func (s *contractService) Get(ctx context.Context, userID, contractID uuid.UUID) (*models.Contract, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.UserID != userID {
+		return nil, repositories.ErrContractNotFound
+	}
+	return contract, nil
+}
+
+// ownedBooking повертає бронювання, якщо воно належить користувачу
+func (s *contractService) ownedBooking(ctx context.Context, userID, bookingID uuid.UUID) (*models.Booking, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != userID {
+		return nil, repositories.ErrBookingNotFound
+	}
+	return booking, nil
+}
@@ -0,0 +1,308 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/events"
+	"timebride/internal/models"
+	"timebride/internal/pdf"
+	"timebride/internal/repositories"
+	"timebride/internal/services/email"
+	"timebride/internal/services/storage"
+	"timebride/internal/utils"
+)
+
+const (
+	// maxSignatureBytes - максимальний розмір PNG з підписом
+	maxSignatureBytes = 512 * 1024
+	// maxSignatureSide - максимальна ширина та висота полотна підпису, пікселів
+	maxSignatureSide = 2000
+	// signatureWidth - ширина підпису в журналі підписання, мм
+	signatureWidth = 70.0
+)
+
+var (
+	ErrContractSigned    = errors.New("contract is already signed")
+	ErrContractChanged   = errors.New("contract has been updated, review the new version")
+	ErrInvalidSignerName = errors.New("signer name must be between 2 and 255 characters")
+	ErrInvalidSignature  = errors.New("signature must be a non-empty png drawing")
+)
+
+// Send відкриває останню версію договору для підписання на публічній сторінці.
+// Попередні надіслані версії замінюються нею: клієнт бачить лише останню.
+func (s *contractService) Send(ctx context.Context, userID, bookingID uuid.UUID) (*models.Contract, error) {
+	if _, err := s.ownedBooking(ctx, userID, bookingID); err != nil {
+		return nil, err
+	}
+
+	contract, err := s.contractRepo.GetLatest(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	switch contract.Status {
+	case models.ContractStatusSigned:
+		return nil, ErrContractSigned
+	case models.ContractStatusSent:
+		return contract, nil
+	}
+
+	now := time.Now()
+	if err := s.contractRepo.MarkSent(ctx, contract.ID, now); err != nil {
+		return nil, err
+	}
+	contract.Status = models.ContractStatusSent
+	contract.SentAt = &now
+	return contract, nil
+}
+
+// ForClient повертає останню версію договору, якщо її надіслано клієнту або підписано.
+// Чернетки клієнту не показуються.
+func (s *contractService) ForClient(ctx context.Context, bookingID uuid.UUID) (*models.Contract, error) {
+	contract, err := s.contractRepo.GetLatest(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Status == models.ContractStatusDraft {
+		return nil, repositories.ErrContractNotFound
+	}
+	return contract, nil
+}
+
+// Sign фіксує підпис клієнта. Підписаний примірник - це переглянутий клієнтом PDF без змін
+// із доданою сторінкою журналу: ПІБ, підпис, IP, браузер, час та SHA-256 переглянутого документа.
+func (s *contractService) Sign(ctx context.Context, bookingID uuid.UUID, input *SignInput) (*models.Contract, error) {
+	name := strings.Join(strings.Fields(input.Name), " ")
+	if n := utf8.RuneCountInString(name); n < 2 || n > 255 {
+		return nil, ErrInvalidSignerName
+	}
+	if err := validateSignature(input.Signature); err != nil {
+		return nil, err
+	}
+
+	contract, err := s.ForClient(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.ID != input.ContractID {
+		return nil, ErrContractChanged
+	}
+	if contract.IsSigned() {
+		return nil, ErrContractSigned
+	}
+
+	original, err := s.readFile(ctx, contract.File)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(original)
+
+	signature := &models.ContractSignature{
+		SignerName:     name,
+		IP:             input.IP,
+		UserAgent:      input.UserAgent,
+		DocumentSHA256: hex.EncodeToString(sum[:]),
+		Image:          input.Signature,
+		SignedAt:       time.Now().UTC().Truncate(time.Second),
+	}
+	content, err := signedDocument(contract, original, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.storage.UploadFile(ctx, &storage.UploadInput{
+		UserID:      contract.UserID,
+		BookingID:   &contract.BookingID,
+		Type:        models.FileTypeDocument,
+		Folder:      models.FolderContracts,
+		Content:     bytes.NewReader(content),
+		Name:        strings.TrimSuffix(contract.File.Name, ".pdf") + " (підписано).pdf",
+		ContentType: "application/pdf",
+		Generated:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store signed contract: %w", err)
+	}
+
+	contract.SignedFileID = &file.ID
+	if err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.contractRepo.Sign(ctx, contract, signature); err != nil {
+			return err
+		}
+		return s.confirmBooking(ctx, contract.BookingID)
+	}); err != nil {
+		if delErr := s.storage.DeleteFile(ctx, file.ID); delErr != nil {
+			log.Printf("Failed to remove unused signed copy %s: %v", file.ID, delErr)
+		}
+		return nil, err
+	}
+	contract.SignedFile = file
+
+	s.notifySigned(ctx, contract)
+	// Листи надсилає обробник черги, тож відповідь клієнту не чекає на SMTP
+	s.queueSignedCopy(ctx, contract, content)
+	return contract, nil
+}
+
+// confirmBooking підтверджує бронювання, що очікувало підпису, і записує зміну статусу в outbox
+func (s *contractService) confirmBooking(ctx context.Context, bookingID uuid.UUID) error {
+	confirmed, err := s.bookingRepo.Transition(ctx, bookingID, models.BookingStatusPending, models.BookingStatusBooked)
+	if err != nil || !confirmed {
+		return err
+	}
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	records, err := events.Record(&events.BookingStatusChanged{Booking: booking, PreviousStatus: models.BookingStatusPending})
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.Add(ctx, records)
+}
+
+// signedDocument додає до договору сторінку журналу підписання
+func signedDocument(contract *models.Contract, original []byte, signature *models.ContractSignature) ([]byte, error) {
+	doc, err := pdf.Import(contract.File.Name, original)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.NewPage()
+	doc.Heading("Журнал електронного підписання", 14)
+	doc.Field("Документ", contract.File.Name)
+	doc.Field("Версія договору", strconv.Itoa(contract.Version))
+	doc.Field("SHA-256 документа", signature.DocumentSHA256)
+	doc.Field("Підписант", signature.SignerName)
+	doc.Field("Дата та час (UTC)", utils.FormatDateTime(signature.SignedAt))
+	doc.Field("IP-адреса", signature.IP)
+	doc.Field("Браузер", signature.UserAgent)
+	doc.Heading("Підпис", 11)
+	if err := doc.Image("signature", signature.Image, "PNG", signatureWidth); err != nil {
+		return nil, err
+	}
+	return doc.Bytes()
+}
+
+// validateSignature перевіряє, що підпис - PNG прийнятного розміру з хоча б одним видимим штрихом
+func validateSignature(data []byte) error {
+	if len(data) == 0 || len(data) > maxSignatureBytes {
+		return ErrInvalidSignature
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width > maxSignatureSide || cfg.Height > maxSignatureSide {
+		return ErrInvalidSignature
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil || isBlank(img) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// isBlank перевіряє, чи полотно не містить жодного непрозорого пікселя
+func isBlank(img image.Image) bool {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// readFile читає вміст файлу зі сховища
+func (s *contractService) readFile(ctx context.Context, file *models.File) ([]byte, error) {
+	content, err := s.storage.OpenFile(ctx, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open contract file: %w", err)
+	}
+	defer content.Reader.Close()
+	return io.ReadAll(content.Reader)
+}
+
+// notifySigned повідомляє власника про підписаний договір
+func (s *contractService) notifySigned(ctx context.Context, contract *models.Contract) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"booking_id":  contract.BookingID,
+		"contract_id": contract.ID,
+		"version":     contract.Version,
+	})
+
+	err := s.notifier.Notify(ctx, &models.Notification{
+		UserID:  contract.UserID,
+		Type:    models.NotificationContractSigned,
+		Title:   "Клієнт підписав договір",
+		Message: fmt.Sprintf("%s підписав(ла) договір (версія %d).", contract.Signature.SignerName, contract.Version),
+		Data:    data,
+	})
+	if err != nil {
+		log.Printf("Failed to notify about signed contract %s: %v", contract.ID, err)
+	}
+}
+
+// queueSignedCopy ставить у чергу листи з підписаним примірником клієнту та студії, кожному - його мовою
+func (s *contractService) queueSignedCopy(ctx context.Context, contract *models.Contract, content []byte) {
+	booking, err := s.bookingRepo.GetByID(ctx, contract.BookingID)
+	if err != nil {
+		log.Printf("Failed to load booking for signed contract %s: %v", contract.ID, err)
+		return
+	}
+	studio, err := s.userRepo.GetByID(ctx, contract.UserID)
+	if err != nil {
+		log.Printf("Failed to load studio for signed contract %s: %v", contract.ID, err)
+		return
+	}
+
+	values := map[string]string{
+		"booking.title": booking.Title,
+		"signer.name":   contract.Signature.SignerName,
+		"signed_at":     utils.FormatDateTime(*contract.SignedAt),
+	}
+	attachments := []models.EmailAttachment{{
+		Name:        contract.SignedFile.Name,
+		ContentType: "application/pdf",
+		Data:        content,
+	}}
+	queue := func(to, language string) {
+		if to == "" {
+			return
+		}
+		err := s.email.Queue(ctx, &email.Request{
+			UserID:      contract.UserID,
+			Event:       email.EventContractSigned,
+			Language:    language,
+			To:          []string{to},
+			Values:      values,
+			Attachments: attachments,
+		})
+		if err != nil {
+			log.Printf("Failed to queue signed contract %s email: %v", contract.ID, err)
+		}
+	}
+
+	if client, err := s.clientRepo.GetByID(ctx, booking.ClientID); err == nil {
+		values["client.name"] = client.FullName
+		queue(client.Email, client.ToPublic().Settings.Language)
+	}
+	if settings, err := studio.GetSettings(); err == nil {
+		queue(studio.Email, settings.Language)
+	}
+}
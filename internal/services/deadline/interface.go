@@ -0,0 +1,32 @@
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// Deadline - термін віддачі матеріалів проєкту
+type Deadline struct {
+	BookingID  uuid.UUID            `json:"booking_id"`
+	Title      string               `json:"title"`
+	ClientName string               `json:"client_name"`
+	Status     models.BookingStatus `json:"status"`
+	DeadlineAt time.Time            `json:"deadline_at"`
+	// DaysLeft - днів до терміну з округленням угору; від'ємне - днів прострочення
+	DaysLeft int  `json:"days_left"`
+	Overdue  bool `json:"overdue"`
+}
+
+// IDeadlineService визначає інтерфейс планувальника термінів віддачі матеріалів
+type IDeadlineService interface {
+	// Check позначає прострочені проєкти та надсилає нагадування про терміни віддачі;
+	// повертає кількість надісланих нагадувань
+	Check(ctx context.Context) (int, error)
+
+	// Upcoming повертає прострочені проєкти користувача та проєкти з терміном у найближчі days днів
+	Upcoming(ctx context.Context, userID uuid.UUID, days int) ([]*Deadline, error)
+}
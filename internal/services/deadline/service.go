@@ -0,0 +1,204 @@
+package deadline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/notification"
+	"timebride/internal/utils"
+)
+
+const (
+	day = 24 * time.Hour
+	// overdueNoticeWindow - про прострочення сповіщається, лише якщо термін минув нещодавно;
+	// давно прострочені проєкти лише позначаються в списку бронювань
+	overdueNoticeWindow = 7 * day
+)
+
+var ErrInvalidPeriod = errors.New("period must be between 1 and 60 days")
+
+type deadlineService struct {
+	bookingRepo  repositories.BookingRepository
+	reminderRepo repositories.DeadlineReminderRepository
+	userRepo     repositories.UserRepository
+	notifier     notification.INotificationService
+}
+
+// NewDeadlineService створює планувальник термінів віддачі матеріалів
+func NewDeadlineService(
+	bookingRepo repositories.BookingRepository,
+	reminderRepo repositories.DeadlineReminderRepository,
+	userRepo repositories.UserRepository,
+	notifier notification.INotificationService,
+) IDeadlineService {
+	return &deadlineService{
+		bookingRepo:  bookingRepo,
+		reminderRepo: reminderRepo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+	}
+}
+
+// Check позначає прострочені проєкти та надсилає нагадування, час яких настав.
+// Кожне нагадування надсилається один раз для терміну; зміна дати події чи терміну
+// віддачі дає новий термін і нові нагадування.
+func (s *deadlineService) Check(ctx context.Context) (int, error) {
+	now := time.Now()
+	if err := s.bookingRepo.RefreshOverdue(ctx, now); err != nil {
+		return 0, err
+	}
+
+	bookings, err := s.bookingRepo.ListByDeadline(ctx, uuid.Nil,
+		now.Add(-overdueNoticeWindow), now.AddDate(0, 0, models.MaxDeadlineReminderDays))
+	if err != nil {
+		return 0, err
+	}
+
+	reminders := make(map[uuid.UUID][]int)
+	sent := 0
+	for _, booking := range bookings {
+		days, ok := reminders[booking.UserID]
+		if !ok {
+			days = s.reminderDays(ctx, booking.UserID)
+			reminders[booking.UserID] = days
+		}
+
+		ok, err := s.remind(ctx, booking, days, now)
+		if err != nil {
+			log.Printf("Failed to send deadline reminder for booking %s: %v", booking.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// Upcoming повертає прострочені проєкти користувача та проєкти з терміном у найближчі days днів
+func (s *deadlineService) Upcoming(ctx context.Context, userID uuid.UUID, days int) ([]*Deadline, error) {
+	if days < 1 || days > models.MaxDeadlineReminderDays {
+		return nil, ErrInvalidPeriod
+	}
+
+	now := time.Now()
+	bookings, err := s.bookingRepo.ListByDeadline(ctx, userID, time.Time{}, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+
+	deadlines := make([]*Deadline, 0, len(bookings))
+	for _, booking := range bookings {
+		deadline, _ := booking.Deadline()
+		item := &Deadline{
+			BookingID:  booking.ID,
+			Title:      booking.Title,
+			Status:     booking.Status,
+			DeadlineAt: deadline,
+			DaysLeft:   daysLeft(deadline, now),
+			Overdue:    now.After(deadline),
+		}
+		if booking.Client != nil {
+			item.ClientName = booking.Client.FullName
+		}
+		deadlines = append(deadlines, item)
+	}
+	return deadlines, nil
+}
+
+// remind надсилає нагадування про термін бронювання, якщо його час настав і воно ще не надсилалося
+func (s *deadlineService) remind(ctx context.Context, booking *models.Booking, days []int, now time.Time) (bool, error) {
+	deadline, ok := booking.Deadline()
+	if !ok {
+		return false, nil
+	}
+	daysBefore, due := dueReminder(deadline, days, now)
+	if !due {
+		return false, nil
+	}
+
+	reminder := &models.DeadlineReminder{
+		BookingID:  booking.ID,
+		DaysBefore: daysBefore,
+		DeadlineAt: deadline,
+	}
+	claimed, err := s.reminderRepo.Claim(ctx, reminder)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"booking_id":  booking.ID,
+		"deadline_at": deadline,
+	})
+	msg := &models.Notification{
+		UserID: booking.UserID,
+		Type:   models.NotificationDeadlineSoon,
+		Title:  fmt.Sprintf("Термін віддачі: «%s»", booking.Title),
+		Data:   data,
+	}
+	if daysBefore == 0 {
+		msg.Type = models.NotificationDeadlineOverdue
+		msg.Title = fmt.Sprintf("Прострочено: «%s»", booking.Title)
+		msg.Message = fmt.Sprintf("Термін віддачі матеріалів проєкту «%s» минув %s.",
+			booking.Title, utils.FormatDate(deadline))
+	} else {
+		left := daysLeft(deadline, now)
+		msg.Message = fmt.Sprintf("До терміну віддачі матеріалів проєкту «%s» залишилось %d %s (%s).",
+			booking.Title, left, utils.PluralizeDays(left), utils.FormatDate(deadline))
+	}
+
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		// Нагадування не надіслано - знімаємо позначку, щоб наступний запуск повторив його
+		if releaseErr := s.reminderRepo.Release(context.WithoutCancel(ctx), reminder); releaseErr != nil {
+			log.Printf("Failed to release deadline reminder %s: %v", reminder.ID, releaseErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// reminderDays повертає налаштовані користувачем нагадування або типові, якщо налаштування недоступні
+func (s *deadlineService) reminderDays(ctx context.Context, userID uuid.UUID) []int {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load user %s for deadline reminders: %v", userID, err)
+		return models.DefaultDeadlineReminders
+	}
+	settings, err := user.GetSettings()
+	if err != nil {
+		return models.DefaultDeadlineReminders
+	}
+	return settings.NotificationSettings.DeadlineReminderDays()
+}
+
+// dueReminder повертає найближче до терміну нагадування, час якого настав;
+// 0 - термін уже минув
+func dueReminder(deadline time.Time, days []int, now time.Time) (int, bool) {
+	left := deadline.Sub(now)
+	if left < 0 {
+		return 0, true
+	}
+
+	nearest := 0
+	for _, d := range days {
+		if left <= time.Duration(d)*day && (nearest == 0 || d < nearest) {
+			nearest = d
+		}
+	}
+	return nearest, nearest > 0
+}
+
+// daysLeft повертає кількість днів до терміну з округленням угору
+func daysLeft(deadline, now time.Time) int {
+	return int(math.Ceil(deadline.Sub(now).Hours() / 24))
+}
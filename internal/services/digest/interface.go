@@ -0,0 +1,28 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Rendered - звіт, підготовлений для застосунку, Telegram та листа
+type Rendered struct {
+	Title string `json:"title"`
+	// Text - звичайний текст для застосунку та текстової версії листа
+	Text string `json:"text"`
+	// Markdown - текст у розмітці Telegram MarkdownV2
+	Markdown string `json:"markdown"`
+	// HTML - тіло листа
+	HTML string `json:"html"`
+}
+
+// IDigestService визначає інтерфейс щоденних і щотижневих звітів
+type IDigestService interface {
+	// SendDue надсилає звіти користувачам, для яких настав час звіту за їхнім часовим поясом;
+	// повертає кількість надісланих звітів
+	SendDue(ctx context.Context) (int, error)
+
+	// Preview формує звіт користувача за поточний період без надсилання
+	Preview(ctx context.Context, userID uuid.UUID) (*Rendered, error)
+}
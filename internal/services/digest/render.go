@@ -0,0 +1,81 @@
+package digest
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+)
+
+// markdownSpecial - символи, які потрібно екранувати в Telegram MarkdownV2
+const markdownSpecial = "_*[]()~`>#+-=|{}.!\\"
+
+// emailTemplate - HTML-тіло листа зі звітом; стилі вбудовані, бо поштові клієнти ігнорують <style>
+var emailTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html lang="uk">
+<body style="margin:0;padding:24px;background:#f5f7fb;font-family:Arial,sans-serif;color:#1d273b;">
+<div style="max-width:600px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px;">
+<h1 style="font-size:20px;margin:0 0 16px;">{{.Title}}</h1>
+{{range .Sections}}<h2 style="font-size:16px;margin:20px 0 8px;">{{.Title}}</h2>
+<ul style="margin:0;padding-left:20px;line-height:1.5;">
+{{range .Items}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}<p style="margin:24px 0 0;font-size:12px;color:#667382;">TimeBride. Частоту звітів можна змінити в налаштуваннях сповіщень.</p>
+</div>
+</body>
+</html>
+`))
+
+// render готує звіт для застосунку, Telegram та листа
+func render(r *report) (*Rendered, error) {
+	title := r.title()
+	sections := r.sections()
+
+	var text, markdown strings.Builder
+	markdown.WriteString("*" + escapeMarkdown(title) + "*\n")
+	for i, s := range sections {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		text.WriteString(s.title + ":\n")
+		markdown.WriteString("\n*" + escapeMarkdown(s.title) + "*\n")
+		for _, item := range s.items {
+			text.WriteString("• " + item + "\n")
+			markdown.WriteString("• " + escapeMarkdown(item) + "\n")
+		}
+	}
+
+	type htmlSection struct {
+		Title string
+		Items []string
+	}
+	data := struct {
+		Title    string
+		Sections []htmlSection
+	}{Title: title}
+	for _, s := range sections {
+		data.Sections = append(data.Sections, htmlSection{Title: s.title, Items: s.items})
+	}
+	var html bytes.Buffer
+	if err := emailTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &Rendered{
+		Title:    title,
+		Text:     strings.TrimSpace(text.String()),
+		Markdown: markdown.String(),
+		HTML:     html.String(),
+	}, nil
+}
+
+// escapeMarkdown екранує текст для Telegram MarkdownV2
+func escapeMarkdown(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(markdownSpecial, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
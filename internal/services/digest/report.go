@@ -0,0 +1,170 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"timebride/internal/models"
+	"timebride/internal/services/deadline"
+	"timebride/internal/utils"
+)
+
+// horizon - на скільки днів уперед звіт показує зйомки та терміни віддачі
+type horizon struct {
+	shoots    int
+	deadlines int
+}
+
+var horizons = map[models.NotificationFrequency]horizon{
+	models.NotificationFrequencyDaily:  {shoots: 1, deadlines: 7},
+	models.NotificationFrequencyWeekly: {shoots: 7, deadlines: 14},
+}
+
+// report - дані звіту користувача
+type report struct {
+	frequency models.NotificationFrequency
+	location  *time.Location
+	currency  string
+	// from, to - період, за який звіт показує нову активність
+	from, to time.Time
+
+	shoots    []*models.Booking
+	deadlines []*deadline.Deadline
+	unpaid    []*models.Booking
+	activity  []*models.BookingGalleryActivity
+	inquiries []*models.Booking
+}
+
+// section - розділ звіту зі списком рядків
+type section struct {
+	title string
+	items []string
+}
+
+// collect збирає дані звіту: нову активність за [from, to) та найближчі події після to
+func (s *digestService) collect(ctx context.Context, user *models.User, settings *models.UserSettings, from, to time.Time) (*report, error) {
+	frequency := digestFrequency(settings)
+	h, ok := horizons[frequency]
+	if !ok {
+		h = horizons[models.NotificationFrequencyDaily]
+		frequency = models.NotificationFrequencyDaily
+	}
+	r := &report{
+		frequency: frequency,
+		location:  settings.NotificationSettings.Location(),
+		currency:  settings.DefaultCurrency,
+		from:      from,
+		to:        to,
+	}
+
+	var err error
+	if settings.NotificationSettings.GalleryActivityFrequency() != models.NotificationFrequencyOff {
+		if r.activity, err = s.galleryRepo.ActivityByUser(ctx, user.ID, from); err != nil {
+			return nil, err
+		}
+	}
+	// Повний звіт вимкнено - надсилається лише зведення активності в галереях
+	if settings.NotificationSettings.DigestFrequency().Period() == 0 {
+		return r, nil
+	}
+
+	// Зйомки - до кінця дня через h.shoots днів за часом користувача
+	local := to.In(r.location)
+	until := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, r.location).AddDate(0, 0, h.shoots+1)
+	if r.shoots, err = s.bookingRepo.ListByEventDate(ctx, user.ID, to, until); err != nil {
+		return nil, err
+	}
+	if r.deadlines, err = s.deadlines.Upcoming(ctx, user.ID, h.deadlines); err != nil {
+		return nil, err
+	}
+	if r.unpaid, err = s.bookingRepo.ListUnpaid(ctx, user.ID, to); err != nil {
+		return nil, err
+	}
+	if r.inquiries, err = s.bookingRepo.ListInquiries(ctx, user.ID, from); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// isEmpty перевіряє, чи у звіті немає жодного пункту
+func (r *report) isEmpty() bool {
+	return len(r.shoots) == 0 && len(r.deadlines) == 0 && len(r.unpaid) == 0 &&
+		len(r.activity) == 0 && len(r.inquiries) == 0
+}
+
+// title повертає заголовок звіту з датою або періодом
+func (r *report) title() string {
+	to := r.to.In(r.location)
+	if r.frequency == models.NotificationFrequencyWeekly {
+		from := r.from.In(r.location)
+		return fmt.Sprintf("Щотижневий звіт за %s - %s", from.Format("02.01"), utils.FormatDate(to))
+	}
+	return "Щоденний звіт за " + utils.FormatDate(to)
+}
+
+// sections перетворює дані звіту на розділи; порожні розділи пропускаються
+func (r *report) sections() []section {
+	date := func(t time.Time) string { return utils.FormatDate(t.In(r.location)) }
+	client := func(b *models.Booking) string {
+		if b.Client == nil || b.Client.FullName == "" {
+			return ""
+		}
+		return " (" + b.Client.FullName + ")"
+	}
+
+	var sections []section
+	add := func(title string, items []string) {
+		if len(items) > 0 {
+			sections = append(sections, section{title: title, items: items})
+		}
+	}
+
+	var items []string
+	for _, b := range r.shoots {
+		item := utils.FormatDateTime(b.EventDate.In(r.location)) + " - " + b.Title + client(b)
+		if b.Location != "" {
+			item += ", " + b.Location
+		}
+		items = append(items, item)
+	}
+	add("Найближчі зйомки", items)
+
+	items = nil
+	for _, d := range r.deadlines {
+		var when string
+		switch {
+		case d.Overdue:
+			when = "прострочено"
+		case d.DaysLeft <= 1:
+			when = "завтра"
+		default:
+			when = fmt.Sprintf("через %d %s", d.DaysLeft, utils.PluralizeDays(d.DaysLeft))
+		}
+		items = append(items, fmt.Sprintf("%s - %s (%s)", d.Title, when, date(d.DeadlineAt)))
+	}
+	add("Терміни віддачі", items)
+
+	items = nil
+	for _, b := range r.unpaid {
+		balance := b.PriceTotal + b.PriceExtra - b.PricePrepayment
+		items = append(items, fmt.Sprintf("%s%s - до сплати %s %s, подія %s",
+			b.Title, client(b), utils.FormatMoney(balance), r.currency, date(b.EventDate)))
+	}
+	add("Неоплачені проєкти", items)
+
+	items = nil
+	for _, a := range r.activity {
+		items = append(items, fmt.Sprintf("%s: відкриттів %d, переглядів фото %d, завантажень %d, архівів %d",
+			a.BookingTitle, a.Views, a.FileViews, a.Downloads, a.ZipDownloads))
+	}
+	add("Активність у галереях", items)
+
+	items = nil
+	for _, b := range r.inquiries {
+		items = append(items, fmt.Sprintf("%s%s - подія %s", b.Title, client(b), date(b.EventDate)))
+	}
+	add("Нові запити", items)
+
+	return sections
+}
@@ -0,0 +1,211 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/deadline"
+	"timebride/internal/services/notification"
+)
+
+// digestWindow - скільки часу після запланованої години звіт ще можна надіслати;
+// якщо планувальник не працював довше, звіт за цей період пропускається
+const digestWindow = 3 * time.Hour
+
+type digestService struct {
+	bookingRepo repositories.BookingRepository
+	galleryRepo repositories.GalleryRepository
+	userRepo    repositories.UserRepository
+	deadlines   deadline.IDeadlineService
+	notifier    notification.INotificationService
+}
+
+// NewDigestService створює сервіс щоденних і щотижневих звітів
+func NewDigestService(
+	bookingRepo repositories.BookingRepository,
+	galleryRepo repositories.GalleryRepository,
+	userRepo repositories.UserRepository,
+	deadlines deadline.IDeadlineService,
+	notifier notification.INotificationService,
+) IDigestService {
+	return &digestService{
+		bookingRepo: bookingRepo,
+		galleryRepo: galleryRepo,
+		userRepo:    userRepo,
+		deadlines:   deadlines,
+		notifier:    notifier,
+	}
+}
+
+// SendDue надсилає звіти користувачам, які їх увімкнули, якщо настала година звіту
+// за їхнім часовим поясом і звіт за поточний період ще не надсилався
+func (s *digestService) SendDue(ctx context.Context) (int, error) {
+	periodic := []string{string(models.NotificationFrequencyDaily), string(models.NotificationFrequencyWeekly)}
+	users, err := s.userRepo.ListByNotificationSetting(ctx, "digest", periodic, string(models.NotificationFrequencyOff))
+	if err != nil {
+		return 0, err
+	}
+	// Зведення активності в галереях надсилається в тому ж звіті, навіть якщо решту звіту вимкнено
+	galleryUsers, err := s.userRepo.ListByNotificationSetting(ctx, "gallery_activity", periodic, string(models.NotificationFrequencyDaily))
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[uuid.UUID]bool, len(users))
+	for _, user := range users {
+		seen[user.ID] = true
+	}
+	for _, user := range galleryUsers {
+		if !seen[user.ID] {
+			users = append(users, user)
+		}
+	}
+
+	sent := 0
+	now := time.Now()
+	for _, user := range users {
+		ok, err := s.send(ctx, user, now)
+		if err != nil {
+			log.Printf("Failed to send digest to user %s: %v", user.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// Preview формує звіт за період, що минув з останнього звіту (або за день чи тиждень)
+func (s *digestService) Preview(ctx context.Context, userID uuid.UUID) (*Rendered, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := user.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from, err := s.since(ctx, user.ID, now, digestPeriod(settings))
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.collect(ctx, user, settings, from, now)
+	if err != nil {
+		return nil, err
+	}
+	return render(r)
+}
+
+// send надсилає звіт користувачу, якщо його час настав; порожні звіти не надсилаються
+func (s *digestService) send(ctx context.Context, user *models.User, now time.Time) (bool, error) {
+	settings, err := user.GetSettings()
+	if err != nil {
+		return false, err
+	}
+	period := digestPeriod(settings)
+	if period == 0 {
+		return false, nil
+	}
+
+	scheduled := scheduledAt(settings, now)
+	if now.Sub(scheduled) > digestWindow {
+		return false, nil
+	}
+	last, err := s.notifier.LatestOfType(ctx, user.ID, models.NotificationDigest)
+	if err != nil {
+		return false, err
+	}
+	if last != nil && !last.CreatedAt.Before(scheduled) {
+		return false, nil
+	}
+
+	from := scheduled.Add(-period)
+	if last != nil && last.CreatedAt.After(from) {
+		from = last.CreatedAt
+	}
+	r, err := s.collect(ctx, user, settings, from, now)
+	if err != nil {
+		return false, err
+	}
+	if r.isEmpty() {
+		return false, nil
+	}
+
+	rendered, err := render(r)
+	if err != nil {
+		return false, err
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"frequency": r.frequency,
+		"since":     from,
+	})
+	err = s.notifier.Notify(ctx, &models.Notification{
+		UserID:   user.ID,
+		Type:     models.NotificationDigest,
+		Title:    rendered.Title,
+		Message:  rendered.Text,
+		Markdown: rendered.Markdown,
+		HTML:     rendered.HTML,
+		Data:     data,
+	})
+	return err == nil, err
+}
+
+// since повертає початок періоду звіту: час останнього звіту, але не раніше ніж period тому
+func (s *digestService) since(ctx context.Context, userID uuid.UUID, now time.Time, period time.Duration) (time.Time, error) {
+	if period == 0 {
+		period = models.NotificationFrequencyDaily.Period()
+	}
+	from := now.Add(-period)
+	last, err := s.notifier.LatestOfType(ctx, userID, models.NotificationDigest)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if last != nil && last.CreatedAt.After(from) {
+		from = last.CreatedAt
+	}
+	return from, nil
+}
+
+// digestFrequency повертає частоту звіту: частоту повного звіту, а якщо його вимкнено -
+// частоту зведення активності в галереях
+func digestFrequency(settings *models.UserSettings) models.NotificationFrequency {
+	if frequency := settings.NotificationSettings.DigestFrequency(); frequency.Period() > 0 {
+		return frequency
+	}
+	if frequency := settings.NotificationSettings.GalleryActivityFrequency(); frequency.Period() > 0 {
+		return frequency
+	}
+	return models.NotificationFrequencyOff
+}
+
+// digestPeriod повертає тривалість періоду звіту; 0 - звіти вимкнені
+func digestPeriod(settings *models.UserSettings) time.Duration {
+	return digestFrequency(settings).Period()
+}
+
+// scheduledAt повертає останній запланований час звіту, що не пізніше now:
+// година DigestTime за часовим поясом користувача, для щотижневого звіту - у перший день тижня
+func scheduledAt(settings *models.UserSettings, now time.Time) time.Time {
+	loc := settings.NotificationSettings.Location()
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), settings.NotificationSettings.DigestTime(), 0, 0, 0, loc)
+	if at.After(local) {
+		at = at.AddDate(0, 0, -1)
+	}
+	if digestFrequency(settings) == models.NotificationFrequencyWeekly {
+		startOfWeek := time.Weekday(settings.CalendarSettings.StartOfWeek % 7)
+		for at.Weekday() != startOfWeek {
+			at = at.AddDate(0, 0, -1)
+		}
+	}
+	return at
+}
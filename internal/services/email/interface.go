@@ -0,0 +1,39 @@
+package email
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/channels"
+	"timebride/internal/models"
+)
+
+// Request - лист за шаблоном події
+type Request struct {
+	// UserID - студія, чиїм шаблоном заповнюється лист
+	UserID uuid.UUID
+	Event  string
+	// Language - мова листа; невідома мова замінюється мовою за замовчуванням
+	Language string
+	To       []string
+	Values   map[string]string
+	// HTML - HTML-версія листа; текстова версія заповнюється з шаблону
+	HTML        string
+	Attachments []models.EmailAttachment
+}
+
+// IEmailService визначає інтерфейс сервісу транзакційних листів
+type IEmailService interface {
+	// Events повертає події, для яких надсилаються листи, з полями підстановки
+	Events() []Event
+
+	// Queue заповнює шаблон листа події та ставить лист у чергу надсилання
+	Queue(ctx context.Context, req *Request) error
+
+	// QueueNotification ставить у чергу лист зі сповіщенням користувачу
+	QueueNotification(ctx context.Context, user *models.User, msg *channels.Message) error
+
+	// SendDue надсилає листи з черги, час яких настав; повертає кількість надісланих
+	SendDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,195 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/channels"
+	"timebride/internal/mailer"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+const (
+	// sendLease - час на надсилання взятого з черги листа; після нього лист знову доступний обробнику
+	sendLease = 5 * time.Minute
+	// sendBatch - кількість листів, що надсилаються за один запуск
+	sendBatch = 50
+)
+
+// retryDelays - паузи перед повторними спробами; після останньої лист вважається ненадісланим
+var retryDelays = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	12 * time.Hour,
+}
+
+var ErrUnknownEvent = errors.New("unknown email event")
+
+type emailService struct {
+	outboxRepo   repositories.EmailOutboxRepository
+	templateRepo repositories.TemplateRepository
+	userRepo     repositories.UserRepository
+	mailer       mailer.Mailer
+}
+
+// NewEmailService створює сервіс транзакційних листів
+func NewEmailService(
+	outboxRepo repositories.EmailOutboxRepository,
+	templateRepo repositories.TemplateRepository,
+	userRepo repositories.UserRepository,
+	mail mailer.Mailer,
+) IEmailService {
+	return &emailService{
+		outboxRepo:   outboxRepo,
+		templateRepo: templateRepo,
+		userRepo:     userRepo,
+		mailer:       mail,
+	}
+}
+
+// Events повертає події, для яких надсилаються листи, з полями підстановки
+func (s *emailService) Events() []Event {
+	return Events
+}
+
+// Queue заповнює шаблон листа події та ставить лист у чергу надсилання.
+// Поле studio.name заповнюється автоматично, якщо його не передано.
+func (s *emailService) Queue(ctx context.Context, req *Request) error {
+	if _, ok := LookupEvent(req.Event); !ok {
+		return ErrUnknownEvent
+	}
+	if len(req.To) == 0 {
+		return mailer.ErrNoRecipients
+	}
+
+	values := make(map[string]string, len(req.Values)+1)
+	for name, value := range req.Values {
+		values[name] = value
+	}
+	if _, ok := values["studio.name"]; !ok && req.UserID != uuid.Nil {
+		if studio, err := s.userRepo.GetByID(ctx, req.UserID); err == nil {
+			values["studio.name"] = studioName(studio)
+		}
+	}
+
+	subject, body, err := s.render(ctx, req.UserID, req.Event, req.Language, values)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", req.Event, err)
+	}
+
+	now := time.Now()
+	email := &models.OutboxEmail{
+		Event:         req.Event,
+		Recipients:    req.To,
+		Subject:       subject,
+		Body:          body,
+		HTMLBody:      req.HTML,
+		Attachments:   req.Attachments,
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}
+	if req.UserID != uuid.Nil {
+		email.UserID = &req.UserID
+	}
+	return s.outboxRepo.Create(ctx, email)
+}
+
+// QueueNotification ставить у чергу лист зі сповіщенням мовою користувача
+func (s *emailService) QueueNotification(ctx context.Context, user *models.User, msg *channels.Message) error {
+	settings, err := user.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	return s.Queue(ctx, &Request{
+		UserID:   user.ID,
+		Event:    EventNotification,
+		Language: settings.Language,
+		To:       []string{user.Email},
+		Values: map[string]string{
+			"notification.title": msg.Title,
+			"notification.text":  msg.Text,
+			"studio.name":        studioName(user),
+		},
+		HTML: msg.HTML,
+	})
+}
+
+// SendDue надсилає листи з черги, час яких настав; повертає кількість надісланих
+func (s *emailService) SendDue(ctx context.Context) (int, error) {
+	due, err := s.outboxRepo.Claim(ctx, time.Now(), sendLease, sendBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.send(ctx, email) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// send надсилає лист і записує результат спроби; повертає true при успіху
+func (s *emailService) send(ctx context.Context, email *models.OutboxEmail) bool {
+	msg := &mailer.Message{
+		To:      email.Recipients,
+		Subject: email.Subject,
+		Body:    email.Body,
+		HTML:    email.HTMLBody,
+	}
+	for _, attachment := range email.Attachments {
+		msg.Attachments = append(msg.Attachments, mailer.Attachment{
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Data:        attachment.Data,
+		})
+	}
+	err := s.mailer.Send(ctx, msg)
+
+	email.Attempts++
+	now := time.Now()
+	switch {
+	case err == nil:
+		email.Status = models.DeliveryStatusSent
+		email.SentAt = &now
+		email.NextAttemptAt = nil
+		email.LastError = ""
+	case mailer.IsPermanent(err) || email.Attempts > len(retryDelays):
+		email.Status = models.DeliveryStatusFailed
+		email.NextAttemptAt = nil
+		email.LastError = err.Error()
+	default:
+		next := now.Add(retryDelays[email.Attempts-1])
+		email.NextAttemptAt = &next
+		email.LastError = err.Error()
+	}
+
+	if err != nil {
+		log.Printf("Failed to send email %s (attempt %d): %v", email.ID, email.Attempts, err)
+	}
+	if err := s.outboxRepo.Update(ctx, email); err != nil {
+		log.Printf("Failed to update outbox email %s: %v", email.ID, err)
+	}
+	return email.Status == models.DeliveryStatusSent
+}
+
+// studioName повертає назву студії або ім'я фотографа
+func studioName(user *models.User) string {
+	if user.CompanyName != "" {
+		return user.CompanyName
+	}
+	return user.FullName
+}
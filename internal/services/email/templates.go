@@ -0,0 +1,165 @@
+package email
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/merge"
+	"timebride/internal/models"
+)
+
+const (
+	// EventNotification - сповіщення користувачу листом (канал email)
+	EventNotification = "notification"
+	// EventContractSigned - підписаний примірник договору клієнту та студії
+	EventContractSigned = "contract_signed"
+)
+
+// DefaultLanguage - мова листів, якщо мова адресата невідома
+const DefaultLanguage = "uk"
+
+// Languages - мови, для яких можна створити варіант шаблону листа
+var Languages = []string{"uk", "en"}
+
+// Event описує подію, для якої надсилається лист
+type Event struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Fields      []merge.Field `json:"fields"`
+}
+
+// content - тема та текст листа
+type content struct {
+	Subject string
+	Content string
+}
+
+// Events - події транзакційних листів з полями підстановки
+var Events = []Event{
+	{
+		Name:        EventNotification,
+		Description: "Сповіщення з застосунку, надіслане листом",
+		Fields: []merge.Field{
+			{Name: "notification.title", Description: "Заголовок сповіщення"},
+			{Name: "notification.text", Description: "Текст сповіщення"},
+			{Name: "studio.name", Description: "Назва студії або імʼя фотографа"},
+		},
+	},
+	{
+		Name:        EventContractSigned,
+		Description: "Підписаний примірник договору, надісланий клієнту та студії",
+		Fields: []merge.Field{
+			{Name: "booking.title", Description: "Назва бронювання"},
+			{Name: "client.name", Description: "ПІБ клієнта"},
+			{Name: "signer.name", Description: "Імʼя, яким підписано договір"},
+			{Name: "signed_at", Description: "Дата й час підписання (UTC)"},
+			{Name: "studio.name", Description: "Назва студії або імʼя фотографа"},
+		},
+	},
+}
+
+// defaults - вбудовані шаблони листів, якщо студія не створила власний варіант для мови
+var defaults = map[string]map[string]content{
+	EventNotification: {
+		"uk": {
+			Subject: "{{notification.title}}",
+			Content: "{{notification.text}}\n\n--\nTimeBride. Налаштувати сповіщення можна в налаштуваннях застосунку.",
+		},
+		"en": {
+			Subject: "{{notification.title}}",
+			Content: "{{notification.text}}\n\n--\nTimeBride. You can change notifications in the app settings.",
+		},
+	},
+	EventContractSigned: {
+		"uk": {
+			Subject: "Підписаний договір - {{booking.title}}",
+			Content: "Договір до бронювання «{{booking.title}}» підписано {{signed_at}} (UTC) особою {{signer.name}}.\n\n" +
+				"Підписаний примірник із журналом підписання - у вкладенні.\n\n{{studio.name}}",
+		},
+		"en": {
+			Subject: "Signed contract - {{booking.title}}",
+			Content: "The contract for “{{booking.title}}” was signed on {{signed_at}} (UTC) by {{signer.name}}.\n\n" +
+				"The signed copy with the signing log is attached.\n\n{{studio.name}}",
+		},
+	},
+}
+
+// LookupEvent повертає подію листа за назвою
+func LookupEvent(name string) (Event, bool) {
+	for _, event := range Events {
+		if event.Name == name {
+			return event, true
+		}
+	}
+	return Event{}, false
+}
+
+// IsValidLanguage перевіряє мову варіанта шаблону
+func IsValidLanguage(language string) bool {
+	for _, l := range Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// render заповнює шаблон листа події: власний варіант студії для мови або вбудований
+func (s *emailService) render(ctx context.Context, userID uuid.UUID, event, language string, values map[string]string) (subject, body string, err error) {
+	if !IsValidLanguage(language) {
+		language = DefaultLanguage
+	}
+
+	source := defaults[event][language]
+	if custom := s.customTemplate(ctx, userID, event, language); custom != nil {
+		source = content{Subject: custom.Subject, Content: custom.Content}
+	}
+
+	data := merge.Data{Values: values}
+	subjectTmpl, err := merge.Parse(source.Subject)
+	if err != nil {
+		return "", "", err
+	}
+	bodyTmpl, err := merge.Parse(source.Content)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Тема - один рядок заголовка листа
+	subject = strings.Join(strings.Fields(subjectTmpl.Render(data)), " ")
+	return subject, bodyTmpl.Render(data), nil
+}
+
+// customTemplate повертає активний шаблон листа студії для події та мови або nil
+func (s *emailService) customTemplate(ctx context.Context, userID uuid.UUID, event, language string) *models.Template {
+	if userID == uuid.Nil {
+		return nil
+	}
+
+	templates, err := s.templateRepo.List(ctx, map[string]interface{}{
+		"user_id":   userID,
+		"type":      models.TemplateTypeEmail,
+		"event":     event,
+		"language":  language,
+		"is_active": true,
+	})
+	if err != nil {
+		log.Printf("Failed to load email template %s/%s of user %s: %v", event, language, userID, err)
+		return nil
+	}
+	for _, tmpl := range templates {
+		if merge.ValidateFields(tmpl.Subject, eventFields(event)) == nil && merge.ValidateFields(tmpl.Content, eventFields(event)) == nil {
+			return tmpl
+		}
+	}
+	return nil
+}
+
+// eventFields повертає поля підстановки події
+func eventFields(name string) []merge.Field {
+	event, _ := LookupEvent(name)
+	return event.Fields
+}
@@ -0,0 +1,225 @@
+package gallery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"timebride/internal/models"
+)
+
+// defaultExpiryDays - термін доступу до сторінки, якщо в бронюванні не вказано DeadlineDays
+const defaultExpiryDays = 180
+
+var (
+	ErrGalleryExpired       = errors.New("gallery access has expired")
+	ErrPasswordRequired     = errors.New("gallery password required")
+	ErrInvalidPassword      = errors.New("invalid gallery password")
+	ErrDownloadsDisabled    = errors.New("downloads are disabled for this gallery")
+	ErrDownloadLimitReached = errors.New("gallery download limit reached")
+	ErrInvalidDownloadLimit = errors.New("download limit cannot be negative")
+)
+
+// Download повертає оригінал файлу для завантаження з урахуванням налаштувань та ліміту
+func (s *galleryService) Download(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor) (*models.File, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if !gallery.AllowDownload {
+		return nil, ErrDownloadsDisabled
+	}
+	locked, err := s.originalsLocked(ctx, gallery)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, ErrOriginalsLocked
+	}
+
+	file, err := s.galleryFile(ctx, gallery, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.galleryRepo.IncrementDownloads(ctx, gallery.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDownloadLimitReached
+	}
+
+	s.logAccess(ctx, gallery, models.GalleryActionDownload, file, visitor)
+	return file, nil
+}
+
+// Unlock перевіряє пароль сторінки та повертає токен доступу для cookie
+func (s *galleryService) Unlock(ctx context.Context, token, password string, visitor *Visitor) (string, error) {
+	gallery, err := s.enabledGallery(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if !gallery.HasPassword() {
+		return "", nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(gallery.PasswordHash), []byte(password)); err != nil {
+		return "", ErrInvalidPassword
+	}
+
+	s.logAccess(ctx, gallery, models.GalleryActionUnlock, nil, visitor)
+	return s.accessToken(gallery), nil
+}
+
+// Update змінює налаштування доступу до сторінки
+func (s *galleryService) Update(ctx context.Context, userID, bookingID uuid.UUID, input *models.GalleryUpdate) (*models.Gallery, error) {
+	gallery, err := s.GetByBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Password != nil {
+		gallery.PasswordHash = ""
+		if *input.Password != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(*input.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash password: %w", err)
+			}
+			gallery.PasswordHash = string(hash)
+		}
+	}
+	switch {
+	case input.ClearExpiry:
+		gallery.ExpiresAt = nil
+	case input.ExpiresAt != nil:
+		gallery.ExpiresAt = input.ExpiresAt
+	}
+	if input.AllowDownload != nil {
+		gallery.AllowDownload = *input.AllowDownload
+	}
+	if input.DownloadLimit != nil {
+		if *input.DownloadLimit < 0 {
+			return nil, ErrInvalidDownloadLimit
+		}
+		gallery.DownloadLimit = *input.DownloadLimit
+	}
+	if input.MaxSelections != nil {
+		if *input.MaxSelections < 0 {
+			return nil, ErrInvalidMaxSelection
+		}
+		gallery.MaxSelections = *input.MaxSelections
+	}
+	if input.ClientUploads != nil {
+		gallery.ClientUploads = *input.ClientUploads
+	}
+	if input.ProofingEnabled != nil {
+		// Під час першого увімкнення відбору ліміт береться з пакета бронювання
+		if *input.ProofingEnabled && !gallery.ProofingEnabled && input.MaxSelections == nil && gallery.MaxSelections == 0 {
+			booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+			if err != nil {
+				return nil, err
+			}
+			gallery.MaxSelections = s.packageMaxSelections(ctx, booking)
+		}
+		gallery.ProofingEnabled = *input.ProofingEnabled
+	}
+
+	if err := s.galleryRepo.Update(ctx, gallery); err != nil {
+		return nil, fmt.Errorf("failed to update gallery: %w", err)
+	}
+	return gallery, nil
+}
+
+// Extend продовжує доступ до сторінки на days днів (або на термін бронювання, якщо days <= 0).
+// Продовження рахується від поточної дати закінчення, а для заблокованої сторінки - від сьогодні.
+func (s *galleryService) Extend(ctx context.Context, userID, bookingID uuid.UUID, days int) (*models.Gallery, error) {
+	booking, err := s.ownedBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	gallery, err := s.galleryRepo.GetByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now()
+	if gallery.ExpiresAt != nil && gallery.ExpiresAt.After(from) {
+		from = *gallery.ExpiresAt
+	}
+	if days > 0 {
+		expiresAt := from.AddDate(0, 0, days)
+		gallery.ExpiresAt = &expiresAt
+	} else {
+		gallery.ExpiresAt = expiryFrom(from, booking)
+	}
+
+	if err := s.galleryRepo.Update(ctx, gallery); err != nil {
+		return nil, fmt.Errorf("failed to update gallery: %w", err)
+	}
+	return gallery, nil
+}
+
+// Activity повертає статистику та останні записи журналу доступу
+func (s *galleryService) Activity(ctx context.Context, userID, bookingID uuid.UUID, limit int) (*Activity, error) {
+	gallery, err := s.GetByBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.galleryRepo.AccessStats(ctx, gallery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.galleryRepo.ListAccess(ctx, gallery.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Activity{Stats: stats, Recent: recent}, nil
+}
+
+// Access перевіряє доступ відвідувача до сторінки, не фіксуючи перегляд
+func (s *galleryService) Access(ctx context.Context, token string, visitor *Visitor) (*models.Gallery, error) {
+	return s.accessibleGallery(ctx, token, visitor)
+}
+
+// accessibleGallery повертає галерею, якщо вона увімкнена, не прострочена і відвідувач знає пароль
+func (s *galleryService) accessibleGallery(ctx context.Context, token string, visitor *Visitor) (*models.Gallery, error) {
+	gallery, err := s.enabledGallery(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if gallery.IsExpired(time.Now()) {
+		return nil, ErrGalleryExpired
+	}
+	if gallery.HasPassword() && !hmac.Equal([]byte(visitor.AccessToken), []byte(s.accessToken(gallery))) {
+		return nil, ErrPasswordRequired
+	}
+	return gallery, nil
+}
+
+// accessToken підписує ID галереї разом з хешем пароля, тож зміна пароля анулює видані cookie
+func (s *galleryService) accessToken(gallery *models.Gallery) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Storage.SigningKey))
+	mac.Write([]byte(gallery.ID.String() + ":" + gallery.PasswordHash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// expiryFrom повертає дату закінчення доступу: DeadlineDays бронювання від вказаної дати
+func expiryFrom(from time.Time, booking *models.Booking) *time.Time {
+	days := booking.DeadlineDays
+	if days <= 0 {
+		days = defaultExpiryDays
+	}
+	expiresAt := from.AddDate(0, 0, days)
+	return &expiresAt
+}
@@ -0,0 +1,194 @@
+package gallery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/events"
+	"timebride/internal/models"
+)
+
+// visitSession - повторні відкриття сторінки тим самим відвідувачем у цей проміжок не сповіщуються
+const visitSession = 30 * time.Minute
+
+// ViewFile повертає файл для перегляду на сторінці та фіксує перегляд
+func (s *galleryService) ViewFile(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor) (*models.File, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.galleryFile(ctx, gallery, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAccess(ctx, gallery, models.GalleryActionFileView, file, visitor)
+	return file, nil
+}
+
+// Archive повертає файли галереї для ZIP-архіву; архів рахується як одне завантаження
+func (s *galleryService) Archive(ctx context.Context, token string, visitor *Visitor) (*View, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if !gallery.AllowDownload {
+		return nil, ErrDownloadsDisabled
+	}
+
+	view, err := s.view(ctx, gallery)
+	if err != nil {
+		return nil, err
+	}
+	if view.OriginalsLocked {
+		return nil, ErrOriginalsLocked
+	}
+
+	ok, err := s.galleryRepo.IncrementDownloads(ctx, gallery.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDownloadLimitReached
+	}
+
+	s.logAccess(ctx, gallery, models.GalleryActionZipDownload, nil, visitor)
+	return view, nil
+}
+
+// ActivitySummary повертає активність клієнтів у галереях користувача за бронюваннями
+func (s *galleryService) ActivitySummary(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.BookingGalleryActivity, error) {
+	return s.galleryRepo.ActivityByUser(ctx, userID, since)
+}
+
+// logAccess записує дію відвідувача та за потреби одразу сповіщує власника.
+// Помилки журналу не повинні блокувати клієнта.
+func (s *galleryService) logAccess(ctx context.Context, gallery *models.Gallery, action models.GalleryAction, file *models.File, visitor *Visitor) {
+	access := &models.GalleryAccess{
+		GalleryID: gallery.ID,
+		Action:    action,
+		IP:        anonymizeIP(visitor.IP),
+		UserAgent: truncate(visitor.UserAgent, 512),
+	}
+	if file != nil {
+		access.FileID = &file.ID
+	}
+
+	notify := action == models.GalleryActionDownload || action == models.GalleryActionZipDownload
+	if action == models.GalleryActionView {
+		count, err := s.galleryRepo.CountAccessSince(ctx, gallery.ID, action, access.IP, time.Now().Add(-visitSession))
+		notify = err == nil && count == 0
+	}
+
+	// Подія завантаження записується в outbox разом із журналом доступу
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.galleryRepo.LogAccess(ctx, access); err != nil {
+			return err
+		}
+		if action != models.GalleryActionDownload && action != models.GalleryActionZipDownload {
+			return nil
+		}
+		records, err := events.Record(downloaded(gallery, action, file))
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, records)
+	})
+	if err != nil {
+		log.Printf("Failed to log gallery %s access: %v", gallery.ID, err)
+		return
+	}
+	if notify {
+		s.notifyActivity(ctx, gallery, action, file)
+	}
+}
+
+// downloaded описує завантаження файлу або архіву галереї доменною подією
+func downloaded(gallery *models.Gallery, action models.GalleryAction, file *models.File) *events.GalleryDownloaded {
+	event := &events.GalleryDownloaded{
+		GalleryID: gallery.ID,
+		BookingID: gallery.BookingID,
+		UserID:    gallery.UserID,
+		Action:    action,
+	}
+	if file != nil {
+		event.FileID = &file.ID
+		event.FileName = file.Name
+	}
+	return event
+}
+
+// notifyActivity надсилає миттєве сповіщення, якщо власник обрав таку частоту
+func (s *galleryService) notifyActivity(ctx context.Context, gallery *models.Gallery, action models.GalleryAction, file *models.File) {
+	owner, err := s.userRepo.GetByID(ctx, gallery.UserID)
+	if err != nil || galleryFrequency(owner) != models.NotificationFrequencyInstant {
+		return
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, gallery.BookingID)
+	if err != nil {
+		return
+	}
+
+	var title string
+	switch {
+	case action == models.GalleryActionView:
+		title = fmt.Sprintf("Клієнт відкрив галерею «%s»", booking.Title)
+	case action == models.GalleryActionZipDownload:
+		title = fmt.Sprintf("Клієнт завантажив архів галереї «%s»", booking.Title)
+	case file != nil:
+		title = fmt.Sprintf("Клієнт завантажив %s з галереї «%s»", file.Name, booking.Title)
+	default:
+		return
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"gallery_id": gallery.ID,
+		"booking_id": gallery.BookingID,
+		"action":     action,
+	})
+	err = s.notifier.Notify(ctx, &models.Notification{
+		UserID: gallery.UserID,
+		Type:   models.NotificationGalleryActivity,
+		Title:  title,
+		Data:   data,
+	})
+	if err != nil {
+		log.Printf("Failed to notify about gallery %s activity: %v", gallery.ID, err)
+	}
+}
+
+// galleryFrequency повертає частоту сповіщень про галереї з налаштувань користувача
+func galleryFrequency(user *models.User) models.NotificationFrequency {
+	settings, err := user.GetSettings()
+	if err != nil {
+		return models.NotificationFrequencyOff
+	}
+	return settings.NotificationSettings.GalleryActivityFrequency()
+}
+
+// anonymizeIP обнуляє останній октет IPv4 та все після /48 для IPv6
+func anonymizeIP(raw string) string {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return ""
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,128 @@
+package gallery
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/services/storage"
+)
+
+// IGalleryService визначає інтерфейс сервісу публічних галерей
+type IGalleryService interface {
+	// Generate створює галерею бронювання або перегенеровує її посилання
+	Generate(ctx context.Context, userID, bookingID uuid.UUID) (*models.Gallery, error)
+
+	// Disable вимикає публічну сторінку бронювання
+	Disable(ctx context.Context, userID, bookingID uuid.UUID) error
+
+	// GetByBooking повертає галерею бронювання
+	GetByBooking(ctx context.Context, userID, bookingID uuid.UUID) (*models.Gallery, error)
+
+	// Update змінює налаштування доступу: пароль, термін дії, завантаження
+	Update(ctx context.Context, userID, bookingID uuid.UUID, input *models.GalleryUpdate) (*models.Gallery, error)
+
+	// Extend продовжує доступ до сторінки на вказану кількість днів
+	Extend(ctx context.Context, userID, bookingID uuid.UUID, days int) (*models.Gallery, error)
+
+	// Activity повертає статистику переглядів та завантажень сторінки
+	Activity(ctx context.Context, userID, bookingID uuid.UUID, limit int) (*Activity, error)
+
+	// Open повертає вміст публічної сторінки за токеном та фіксує перегляд
+	Open(ctx context.Context, token string, visitor *Visitor) (*View, error)
+
+	// Unlock перевіряє пароль сторінки та повертає токен доступу
+	Unlock(ctx context.Context, token, password string, visitor *Visitor) (string, error)
+
+	// Access перевіряє доступ відвідувача до сторінки, не фіксуючи перегляд
+	Access(ctx context.Context, token string, visitor *Visitor) (*models.Gallery, error)
+
+	// GetFile повертає файл галереї для перегляду (мініатюри, відео)
+	GetFile(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor) (*models.File, error)
+
+	// ViewFile повертає файл для перегляду на сторінці та фіксує перегляд
+	ViewFile(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor) (*models.File, error)
+
+	// Preview повертає мініатюру фото; до повної оплати - з водяним знаком
+	Preview(ctx context.Context, token string, fileID uuid.UUID, size int, visitor *Visitor) (*storage.FileContent, error)
+
+	// Download повертає оригінал файлу, враховуючи дозвіл та ліміт завантажень
+	Download(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor) (*models.File, error)
+
+	// Archive повертає файли галереї для завантаження ZIP-архівом
+	Archive(ctx context.Context, token string, visitor *Visitor) (*View, error)
+
+	// ActivitySummary повертає активність клієнтів у галереях користувача за бронюваннями
+	ActivitySummary(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.BookingGalleryActivity, error)
+
+	// Proofs повертає поточні позначки клієнта в режимі відбору
+	Proofs(ctx context.Context, token string, visitor *Visitor) (*models.Selection, error)
+
+	// MarkProof позначає фото як обране та/або залишає коментар
+	MarkProof(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor, input *models.ProofUpdate) (*models.GalleryProof, error)
+
+	// SubmitSelection фіксує відбір клієнта та повідомляє власника
+	SubmitSelection(ctx context.Context, token string, visitor *Visitor) (*models.Selection, error)
+
+	// Selection повертає відбір клієнта власнику
+	Selection(ctx context.Context, userID, bookingID uuid.UUID) (*models.Selection, error)
+
+	// ReopenSelection дозволяє клієнту змінити надісланий відбір
+	ReopenSelection(ctx context.Context, userID, bookingID uuid.UUID) (*models.Gallery, error)
+
+	// ClientUpload зберігає файл, надісланий клієнтом зі сторінки, у папці "from-client"
+	ClientUpload(ctx context.Context, token string, header *multipart.FileHeader, visitor *Visitor) (*models.File, error)
+
+	// ClientUploads повертає файли, надіслані клієнтом до бронювання
+	ClientUploads(ctx context.Context, userID, bookingID uuid.UUID) ([]*models.File, error)
+
+	// Watermark повертає налаштування водяного знака
+	Watermark(ctx context.Context, userID uuid.UUID) (*models.WatermarkSettings, error)
+
+	// UpdateWatermark змінює параметри водяного знака
+	UpdateWatermark(ctx context.Context, userID uuid.UUID, input *models.WatermarkSettings) (*models.WatermarkSettings, error)
+
+	// UploadWatermarkLogo завантажує логотип водяного знака
+	UploadWatermarkLogo(ctx context.Context, userID uuid.UUID, header *multipart.FileHeader) (*models.WatermarkSettings, error)
+
+	// UpdateBranding оновлює шаблон, логотип та посилання студії на публічних сторінках
+	UpdateBranding(ctx context.Context, userID uuid.UUID, branding *Branding) (*models.User, error)
+}
+
+// Branding містить налаштування оформлення публічних сторінок студії
+type Branding struct {
+	Template    string              `json:"template"`
+	LogoURL     string              `json:"logo_url"`
+	SocialLinks []models.SocialLink `json:"social_links"`
+}
+
+// Visitor описує відвідувача публічної сторінки
+type Visitor struct {
+	IP        string
+	UserAgent string
+	// AccessToken - значення cookie, отримане після введення пароля
+	AccessToken string
+}
+
+// Activity містить статистику та журнал доступу до сторінки
+type Activity struct {
+	Stats  *models.GalleryStats    `json:"stats"`
+	Recent []*models.GalleryAccess `json:"recent"`
+}
+
+// View містить дані для відображення публічної сторінки
+type View struct {
+	Gallery *models.Gallery
+	Booking *models.Booking
+	Studio  *models.User
+	Images  []*models.File
+	Videos  []*models.File
+	Others  []*models.File
+	// OriginalsLocked - до повної оплати доступні лише превʼю з водяним знаком
+	OriginalsLocked bool
+	// UploadMaxBytes - максимальний розмір файлу, який клієнт може надіслати
+	UploadMaxBytes int64
+}
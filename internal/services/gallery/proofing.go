@@ -0,0 +1,223 @@
+package gallery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// maxCommentLength - максимальна довжина коментаря клієнта до фото
+const maxCommentLength = 2000
+
+var (
+	ErrProofingDisabled    = errors.New("proofing is disabled for this gallery")
+	ErrSelectionSubmitted  = errors.New("selection has already been submitted")
+	ErrSelectionLimit      = errors.New("selection limit reached")
+	ErrSelectionEmpty      = errors.New("selection is empty")
+	ErrCommentTooLong      = errors.New("comment is too long")
+	ErrInvalidMaxSelection = errors.New("max selections cannot be negative")
+)
+
+// Proofs повертає поточні позначки клієнта в галереї
+func (s *galleryService) Proofs(ctx context.Context, token string, visitor *Visitor) (*models.Selection, error) {
+	gallery, err := s.proofingGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	return s.selection(ctx, gallery)
+}
+
+// MarkProof позначає фото як обране та/або залишає коментар
+func (s *galleryService) MarkProof(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor, input *models.ProofUpdate) (*models.GalleryProof, error) {
+	gallery, err := s.proofingGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if gallery.SelectionSubmitted() {
+		return nil, ErrSelectionSubmitted
+	}
+
+	file, err := s.galleryFile(ctx, gallery, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if !file.IsImage() {
+		return nil, ErrFileNotInGallery
+	}
+
+	proof, err := s.proofRepo.Get(ctx, gallery.ID, file.ID)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		proof = &models.GalleryProof{GalleryID: gallery.ID, FileID: file.ID}
+	}
+
+	if input.Comment != nil {
+		if len(*input.Comment) > maxCommentLength {
+			return nil, ErrCommentTooLong
+		}
+		proof.Comment = *input.Comment
+	}
+	if input.Favorite != nil {
+		if *input.Favorite && !proof.Favorite && gallery.MaxSelections > 0 {
+			count, err := s.proofRepo.CountFavorites(ctx, gallery.ID)
+			if err != nil {
+				return nil, err
+			}
+			if count >= int64(gallery.MaxSelections) {
+				return nil, ErrSelectionLimit
+			}
+		}
+		proof.Favorite = *input.Favorite
+	}
+
+	proof.UpdatedAt = time.Now()
+	if err := s.proofRepo.Save(ctx, proof); err != nil {
+		return nil, fmt.Errorf("failed to save proof: %w", err)
+	}
+	return proof, nil
+}
+
+// SubmitSelection фіксує відбір клієнта та повідомляє власника
+func (s *galleryService) SubmitSelection(ctx context.Context, token string, visitor *Visitor) (*models.Selection, error) {
+	gallery, err := s.proofingGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if gallery.SelectionSubmitted() {
+		return nil, ErrSelectionSubmitted
+	}
+
+	selection, err := s.selection(ctx, gallery)
+	if err != nil {
+		return nil, err
+	}
+	if selection.Favorites == 0 {
+		return nil, ErrSelectionEmpty
+	}
+	if gallery.MaxSelections > 0 && selection.Favorites > gallery.MaxSelections {
+		return nil, ErrSelectionLimit
+	}
+
+	now := time.Now()
+	gallery.SelectionSubmittedAt = &now
+	if err := s.galleryRepo.Update(ctx, gallery); err != nil {
+		return nil, fmt.Errorf("failed to update gallery: %w", err)
+	}
+	selection.SubmittedAt = &now
+
+	s.notifySelection(ctx, gallery, selection)
+	return selection, nil
+}
+
+// Selection повертає відбір клієнта власнику галереї
+func (s *galleryService) Selection(ctx context.Context, userID, bookingID uuid.UUID) (*models.Selection, error) {
+	gallery, err := s.GetByBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return s.selection(ctx, gallery)
+}
+
+// ReopenSelection дозволяє клієнту змінити вже надісланий відбір
+func (s *galleryService) ReopenSelection(ctx context.Context, userID, bookingID uuid.UUID) (*models.Gallery, error) {
+	gallery, err := s.GetByBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	gallery.SelectionSubmittedAt = nil
+	if err := s.galleryRepo.Update(ctx, gallery); err != nil {
+		return nil, fmt.Errorf("failed to update gallery: %w", err)
+	}
+	return gallery, nil
+}
+
+// proofingGallery повертає доступну галерею з увімкненим режимом відбору
+func (s *galleryService) proofingGallery(ctx context.Context, token string, visitor *Visitor) (*models.Gallery, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if !gallery.ProofingEnabled {
+		return nil, ErrProofingDisabled
+	}
+	return gallery, nil
+}
+
+// selection збирає позначки клієнта; файли, видалені після відбору, пропускаються
+func (s *galleryService) selection(ctx context.Context, gallery *models.Gallery) (*models.Selection, error) {
+	proofs, err := s.proofRepo.ListByGallery(ctx, gallery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &models.Selection{
+		Items:         make([]models.SelectionItem, 0, len(proofs)),
+		MaxSelections: gallery.MaxSelections,
+		SubmittedAt:   gallery.SelectionSubmittedAt,
+	}
+	for _, proof := range proofs {
+		if proof.File == nil || (!proof.Favorite && proof.Comment == "") {
+			continue
+		}
+		if proof.Favorite {
+			selection.Favorites++
+		}
+		selection.Items = append(selection.Items, models.SelectionItem{
+			FileID:    proof.FileID,
+			FileName:  proof.File.Name,
+			Favorite:  proof.Favorite,
+			Comment:   proof.Comment,
+			UpdatedAt: proof.UpdatedAt,
+		})
+	}
+	return selection, nil
+}
+
+// packageMaxSelections повертає ліміт відбору з пакета (шаблону ціни) бронювання
+func (s *galleryService) packageMaxSelections(ctx context.Context, booking *models.Booking) int {
+	if booking.PackageName == "" {
+		return 0
+	}
+
+	templates, err := s.priceRepo.GetByUserID(ctx, booking.UserID)
+	if err != nil {
+		log.Printf("Failed to load packages of user %s: %v", booking.UserID, err)
+		return 0
+	}
+	for _, template := range templates {
+		if template.Name == booking.PackageName {
+			return template.MaxSelections
+		}
+	}
+	return 0
+}
+
+// notifySelection повідомляє власника про надісланий відбір
+func (s *galleryService) notifySelection(ctx context.Context, gallery *models.Gallery, selection *models.Selection) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"gallery_id": gallery.ID,
+		"booking_id": gallery.BookingID,
+		"favorites":  selection.Favorites,
+	})
+
+	err := s.notifier.Notify(ctx, &models.Notification{
+		UserID:  gallery.UserID,
+		Type:    models.NotificationSelectionSubmitted,
+		Title:   "Клієнт надіслав відбір фото",
+		Message: fmt.Sprintf("Обрано фото: %d. Відбір можна експортувати для Lightroom або в CSV.", selection.Favorites),
+		Data:    data,
+	})
+	if err != nil {
+		log.Printf("Failed to notify about gallery %s selection: %v", gallery.ID, err)
+	}
+}
@@ -0,0 +1,304 @@
+package gallery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"timebride/internal/config"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/notification"
+	"timebride/internal/services/storage"
+)
+
+// tokenBytes - довжина випадкового токена; 24 байти дають 192 біти ентропії
+const tokenBytes = 24
+
+var (
+	ErrGalleryDisabled     = errors.New("gallery is disabled")
+	ErrFileNotInGallery    = errors.New("file does not belong to gallery")
+	ErrInvalidTemplate     = errors.New("unknown gallery template")
+	ErrInvalidBrandingLink = errors.New("branding links must be http(s) urls")
+)
+
+type galleryService struct {
+	config      *config.Config
+	galleryRepo repositories.GalleryRepository
+	bookingRepo repositories.BookingRepository
+	userRepo    repositories.UserRepository
+	priceRepo   repositories.PriceRepository
+	proofRepo   repositories.GalleryProofRepository
+	storage     storage.IStorageService
+	notifier    notification.INotificationService
+	outboxRepo  repositories.EventOutboxRepository
+	tx          repositories.UnitOfWork
+}
+
+// NewGalleryService створює новий сервіс галерей
+func NewGalleryService(
+	cfg *config.Config,
+	galleryRepo repositories.GalleryRepository,
+	bookingRepo repositories.BookingRepository,
+	userRepo repositories.UserRepository,
+	priceRepo repositories.PriceRepository,
+	proofRepo repositories.GalleryProofRepository,
+	storageService storage.IStorageService,
+	notifier notification.INotificationService,
+	outboxRepo repositories.EventOutboxRepository,
+	tx repositories.UnitOfWork,
+) IGalleryService {
+	return &galleryService{
+		config:      cfg,
+		galleryRepo: galleryRepo,
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+		priceRepo:   priceRepo,
+		proofRepo:   proofRepo,
+		storage:     storageService,
+		notifier:    notifier,
+		outboxRepo:  outboxRepo,
+		tx:          tx,
+	}
+}
+
+// Generate створює галерею бронювання або перегенеровує її посилання.
+// Після перегенерації попереднє посилання перестає працювати.
+func (s *galleryService) Generate(ctx context.Context, userID, bookingID uuid.UUID) (*models.Gallery, error) {
+	booking, err := s.ownedBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	gallery, err := s.galleryRepo.GetByBookingID(ctx, booking.ID)
+	switch {
+	case errors.Is(err, repositories.ErrGalleryNotFound):
+		gallery = &models.Gallery{
+			BookingID:     booking.ID,
+			UserID:        booking.UserID,
+			Token:         token,
+			Enabled:       true,
+			AllowDownload: true,
+			ExpiresAt:     expiryFrom(time.Now(), booking),
+		}
+		if err := s.galleryRepo.Create(ctx, gallery); err != nil {
+			return nil, fmt.Errorf("failed to create gallery: %w", err)
+		}
+	case err != nil:
+		return nil, err
+	default:
+		gallery.Token = token
+		gallery.Enabled = true
+		if gallery.IsExpired(time.Now()) {
+			gallery.ExpiresAt = expiryFrom(time.Now(), booking)
+		}
+		if err := s.galleryRepo.Update(ctx, gallery); err != nil {
+			return nil, fmt.Errorf("failed to update gallery: %w", err)
+		}
+	}
+
+	if err := s.bookingRepo.SetDeliveryPageURL(ctx, booking.ID, s.publicURL(gallery)); err != nil {
+		return nil, err
+	}
+	return gallery, nil
+}
+
+// Disable вимикає публічну сторінку бронювання
+func (s *galleryService) Disable(ctx context.Context, userID, bookingID uuid.UUID) error {
+	gallery, err := s.GetByBooking(ctx, userID, bookingID)
+	if err != nil {
+		return err
+	}
+
+	gallery.Enabled = false
+	if err := s.galleryRepo.Update(ctx, gallery); err != nil {
+		return fmt.Errorf("failed to update gallery: %w", err)
+	}
+	return s.bookingRepo.SetDeliveryPageURL(ctx, bookingID, "")
+}
+
+// GetByBooking повертає галерею бронювання
+func (s *galleryService) GetByBooking(ctx context.Context, userID, bookingID uuid.UUID) (*models.Gallery, error) {
+	if _, err := s.ownedBooking(ctx, userID, bookingID); err != nil {
+		return nil, err
+	}
+	return s.galleryRepo.GetByBookingID(ctx, bookingID)
+}
+
+// Open повертає вміст публічної сторінки за токеном та фіксує перегляд
+func (s *galleryService) Open(ctx context.Context, token string, visitor *Visitor) (*View, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.view(ctx, gallery)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAccess(ctx, gallery, models.GalleryActionView, nil, visitor)
+	return view, nil
+}
+
+// view збирає дані сторінки: бронювання, студію та файли, доступні клієнту
+func (s *galleryService) view(ctx context.Context, gallery *models.Gallery) (*View, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, gallery.BookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	studio, err := s.userRepo.GetByID(ctx, gallery.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.storage.SearchFiles(ctx, gallery.UserID, models.FileSearchOptions{
+		BookingID: &gallery.BookingID,
+		SortBy:    "taken_at",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, locked := watermarkSettings(booking, studio)
+	view := &View{
+		Gallery:         gallery,
+		Booking:         booking,
+		Studio:          studio,
+		OriginalsLocked: locked,
+		UploadMaxBytes:  s.config.Storage.GetClientUploadMaxSize(),
+	}
+	for _, file := range files {
+		if !isDeliverable(file) {
+			continue
+		}
+		switch {
+		case file.IsImage():
+			view.Images = append(view.Images, file)
+		case file.IsVideo():
+			view.Videos = append(view.Videos, file)
+		default:
+			view.Others = append(view.Others, file)
+		}
+	}
+	return view, nil
+}
+
+// GetFile повертає файл галереї за токеном для перегляду (мініатюри, відео)
+func (s *galleryService) GetFile(ctx context.Context, token string, fileID uuid.UUID, visitor *Visitor) (*models.File, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	return s.galleryFile(ctx, gallery, fileID)
+}
+
+// galleryFile повертає файл, якщо він належить студії та бронюванню галереї і може бути показаний клієнту
+func (s *galleryService) galleryFile(ctx context.Context, gallery *models.Gallery, fileID uuid.UUID) (*models.File, error) {
+	file, err := s.storage.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, ErrFileNotInGallery
+	}
+	if file.UserID != gallery.UserID || file.BookingID == nil || *file.BookingID != gallery.BookingID || !isDeliverable(file) {
+		return nil, ErrFileNotInGallery
+	}
+	return file, nil
+}
+
+// UpdateBranding оновлює шаблон, логотип та посилання студії на публічних сторінках
+func (s *galleryService) UpdateBranding(ctx context.Context, userID uuid.UUID, branding *Branding) (*models.User, error) {
+	if branding.Template != "" && !models.IsValidGalleryTemplate(branding.Template) {
+		return nil, ErrInvalidTemplate
+	}
+	if branding.LogoURL != "" && !isHTTPURL(branding.LogoURL) {
+		return nil, ErrInvalidBrandingLink
+	}
+	for _, link := range branding.SocialLinks {
+		if !isHTTPURL(link.URL) {
+			return nil, ErrInvalidBrandingLink
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := json.Marshal(branding.SocialLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	user.GalleryTemplate = branding.Template
+	user.GalleryLogoURL = branding.LogoURL
+	user.GallerySocialLinks = datatypes.JSON(links)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// enabledGallery повертає увімкнену галерею за токеном
+func (s *galleryService) enabledGallery(ctx context.Context, token string) (*models.Gallery, error) {
+	gallery, err := s.galleryRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !gallery.Enabled {
+		return nil, ErrGalleryDisabled
+	}
+	return gallery, nil
+}
+
+// ownedBooking повертає бронювання, якщо воно належить користувачу
+func (s *galleryService) ownedBooking(ctx context.Context, userID, bookingID uuid.UUID) (*models.Booking, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != userID {
+		return nil, repositories.ErrBookingNotFound
+	}
+	return booking, nil
+}
+
+// publicURL повертає повне посилання на публічну сторінку
+func (s *galleryService) publicURL(gallery *models.Gallery) string {
+	return s.config.Server.BaseURL + gallery.PublicPath()
+}
+
+// isDeliverable перевіряє, чи можна показувати файл клієнту.
+// На сторінці віддачі показуються лише матеріали студії - файли з папок
+// (надіслані клієнтом, договори) сюди не потрапляють.
+func isDeliverable(file *models.File) bool {
+	return file.IsClean() && file.Type != models.FileTypeAvatar && file.Folder == ""
+}
+
+// isHTTPURL перевіряє, що посилання веде на http(s) - інші схеми (javascript:) небезпечні на публічній сторінці
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// newToken генерує неявний токен для публічного посилання
+func newToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate gallery token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,131 @@
+package gallery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/services/storage"
+)
+
+var (
+	ErrClientUploadsDisabled = errors.New("client uploads are disabled")
+	ErrUploadTooLarge        = errors.New("file is too large")
+	ErrUploadTypeNotAllowed  = errors.New("file type is not allowed")
+)
+
+// clientUploadExtensions - типи файлів, які клієнт може надіслати: референси, документи з таймінгом, музика
+var clientUploadExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".heic": true, ".webp": true,
+	".pdf": true, ".txt": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true, ".odt": true,
+	".mp3": true, ".wav": true, ".m4a": true, ".aac": true, ".flac": true, ".ogg": true,
+}
+
+// ClientUploadAccept повертає дозволені розширення для атрибута accept поля завантаження
+func ClientUploadAccept() string {
+	extensions := make([]string, 0, len(clientUploadExtensions))
+	for ext := range clientUploadExtensions {
+		extensions = append(extensions, ext)
+	}
+	sort.Strings(extensions)
+	return strings.Join(extensions, ",")
+}
+
+// ClientUpload зберігає файл клієнта в папці "from-client" бронювання.
+// Розмір файлу враховується в квоті власника; власник отримує сповіщення
+// про перший файл у межах візиту.
+func (s *galleryService) ClientUpload(ctx context.Context, token string, header *multipart.FileHeader, visitor *Visitor) (*models.File, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if !gallery.ClientUploads {
+		return nil, ErrClientUploadsDisabled
+	}
+	if header.Size > s.config.Storage.GetClientUploadMaxSize() {
+		return nil, ErrUploadTooLarge
+	}
+	if !clientUploadExtensions[strings.ToLower(filepath.Ext(header.Filename))] {
+		return nil, ErrUploadTypeNotAllowed
+	}
+
+	file, err := s.storage.UploadFile(ctx, &storage.UploadInput{
+		UserID:    gallery.UserID,
+		BookingID: &gallery.BookingID,
+		Folder:    models.FolderFromClient,
+		File:      header,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logClientUpload(ctx, gallery, file, visitor)
+	return file, nil
+}
+
+// ClientUploads повертає файли, надіслані клієнтом, від найновіших
+func (s *galleryService) ClientUploads(ctx context.Context, userID, bookingID uuid.UUID) ([]*models.File, error) {
+	booking, err := s.ownedBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := models.FolderFromClient
+	return s.storage.SearchFiles(ctx, userID, models.FileSearchOptions{
+		BookingID: &booking.ID,
+		Folder:    &folder,
+		SortDesc:  true,
+	})
+}
+
+// logClientUpload записує надсилання файлу в журнал сторінки та сповіщує власника
+// лише про перший файл візиту, щоб пакет файлів не породжував десятки сповіщень
+func (s *galleryService) logClientUpload(ctx context.Context, gallery *models.Gallery, file *models.File, visitor *Visitor) {
+	access := &models.GalleryAccess{
+		GalleryID: gallery.ID,
+		FileID:    &file.ID,
+		Action:    models.GalleryActionClientUpload,
+		IP:        anonymizeIP(visitor.IP),
+		UserAgent: truncate(visitor.UserAgent, 512),
+	}
+
+	count, err := s.galleryRepo.CountAccessSince(ctx, gallery.ID, access.Action, access.IP, time.Now().Add(-visitSession))
+	if err := s.galleryRepo.LogAccess(ctx, access); err != nil {
+		log.Printf("Failed to log gallery %s upload: %v", gallery.ID, err)
+	}
+	if err != nil || count > 0 {
+		return
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, gallery.BookingID)
+	if err != nil {
+		return
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"gallery_id": gallery.ID,
+		"booking_id": gallery.BookingID,
+		"file_id":    file.ID,
+		"folder":     models.FolderFromClient,
+	})
+	err = s.notifier.Notify(ctx, &models.Notification{
+		UserID:  gallery.UserID,
+		Type:    models.NotificationClientUpload,
+		Title:   fmt.Sprintf("Клієнт надіслав файли до бронювання «%s»", booking.Title),
+		Message: file.Name,
+		Data:    data,
+	})
+	if err != nil {
+		log.Printf("Failed to notify about client upload to gallery %s: %v", gallery.ID, err)
+	}
+}
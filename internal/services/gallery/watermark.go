@@ -0,0 +1,194 @@
+package gallery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/media"
+	"timebride/internal/models"
+	"timebride/internal/services/storage"
+)
+
+var (
+	ErrOriginalsLocked  = errors.New("originals are available after full payment")
+	ErrInvalidWatermark = errors.New("invalid watermark settings")
+	ErrInvalidLogo      = errors.New("watermark logo must be a png or jpeg image")
+)
+
+// Preview повертає мініатюру фото галереї; до повної оплати - з водяним знаком.
+// Велика мініатюра відкривається в переглядачі, тож фіксується як перегляд фото.
+func (s *galleryService) Preview(ctx context.Context, token string, fileID uuid.UUID, size int, visitor *Visitor) (*storage.FileContent, error) {
+	gallery, err := s.accessibleGallery(ctx, token, visitor)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.galleryFile(ctx, gallery, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	wm, err := s.watermarkFor(ctx, gallery)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.storage.OpenPreview(ctx, file, size, wm)
+	if err != nil {
+		return nil, err
+	}
+	if size == storage.ThumbnailLarge {
+		s.logAccess(ctx, gallery, models.GalleryActionFileView, file, visitor)
+	}
+	return content, nil
+}
+
+// Watermark повертає налаштування водяного знака користувача
+func (s *galleryService) Watermark(ctx context.Context, userID uuid.UUID) (*models.WatermarkSettings, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := user.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	return &settings.Watermark, nil
+}
+
+// UpdateWatermark змінює положення, прозорість та масштаб водяного знака
+func (s *galleryService) UpdateWatermark(ctx context.Context, userID uuid.UUID, input *models.WatermarkSettings) (*models.WatermarkSettings, error) {
+	if !media.WatermarkPosition(input.Position).IsValid() || input.Opacity <= 0 || input.Opacity > 1 || input.Scale <= 0 || input.Scale > 1 {
+		return nil, ErrInvalidWatermark
+	}
+	if input.LogoFileID != nil {
+		logo, err := s.storage.GetFile(ctx, *input.LogoFileID)
+		if err != nil || logo.UserID != userID || logo.Type != models.FileTypeWatermark {
+			return nil, ErrInvalidLogo
+		}
+	}
+
+	return s.saveWatermark(ctx, userID, func(settings *models.WatermarkSettings) {
+		*settings = *input
+	})
+}
+
+// UploadWatermarkLogo завантажує логотип водяного знака та робить його поточним
+func (s *galleryService) UploadWatermarkLogo(ctx context.Context, userID uuid.UUID, header *multipart.FileHeader) (*models.WatermarkSettings, error) {
+	switch header.Header.Get("Content-Type") {
+	case "image/png", "image/jpeg":
+	default:
+		return nil, ErrInvalidLogo
+	}
+
+	logo, err := s.storage.UploadFile(ctx, &storage.UploadInput{
+		UserID: userID,
+		Type:   models.FileTypeWatermark,
+		File:   header,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload watermark logo: %w", err)
+	}
+
+	return s.saveWatermark(ctx, userID, func(settings *models.WatermarkSettings) {
+		settings.LogoFileID = &logo.ID
+		if settings.Position == "" {
+			settings.Position = string(media.WatermarkCenter)
+		}
+		if settings.Opacity == 0 {
+			settings.Opacity = 0.5
+		}
+		if settings.Scale == 0 {
+			settings.Scale = 0.3
+		}
+	})
+}
+
+// saveWatermark змінює налаштування водяного знака в налаштуваннях користувача
+func (s *galleryService) saveWatermark(ctx context.Context, userID uuid.UUID, change func(*models.WatermarkSettings)) (*models.WatermarkSettings, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := user.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	change(&settings.Watermark)
+
+	if err := user.SetSettings(settings); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &settings.Watermark, nil
+}
+
+// watermarkFor повертає водяний знак для превʼю галереї або nil, якщо бронювання оплачене
+// чи знак вимкнено. Якщо знак потрібен, але недоступний, повертається помилка -
+// превʼю без знака до оплати не віддаються.
+func (s *galleryService) watermarkFor(ctx context.Context, gallery *models.Gallery) (*storage.Watermark, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, gallery.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	studio, err := s.userRepo.GetByID(ctx, gallery.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, ok := watermarkSettings(booking, studio)
+	if !ok {
+		return nil, nil
+	}
+
+	logo, err := s.storage.GetFile(ctx, *settings.LogoFileID)
+	if err != nil {
+		return nil, fmt.Errorf("watermark logo is unavailable: %w", err)
+	}
+
+	return &storage.Watermark{
+		Logo: logo,
+		Options: media.WatermarkOptions{
+			Position: media.WatermarkPosition(settings.Position),
+			Opacity:  settings.Opacity,
+			Scale:    settings.Scale,
+		},
+	}, nil
+}
+
+// originalsLocked перевіряє, чи оригінали заблоковані до повної оплати бронювання
+func (s *galleryService) originalsLocked(ctx context.Context, gallery *models.Gallery) (bool, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, gallery.BookingID)
+	if err != nil {
+		return false, err
+	}
+	studio, err := s.userRepo.GetByID(ctx, gallery.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	_, locked := watermarkSettings(booking, studio)
+	return locked, nil
+}
+
+// watermarkSettings повертає налаштування знака, якщо він діє для бронювання:
+// знак увімкнено та бронювання ще не оплачене повністю
+func watermarkSettings(booking *models.Booking, studio *models.User) (*models.WatermarkSettings, bool) {
+	if booking.PaymentStatus == models.PaymentStatusPaid {
+		return nil, false
+	}
+
+	settings, err := studio.GetSettings()
+	if err != nil || !settings.Watermark.IsActive() {
+		return nil, false
+	}
+	return &settings.Watermark, true
+}
@@ -0,0 +1,166 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/channels"
+	"timebride/internal/models"
+)
+
+const (
+	// sendLease - час, на який обробник черги бере доставки; після нього їх може взяти інший
+	sendLease = 5 * time.Minute
+	// sendTimeout - максимальна тривалість однієї спроби надсилання
+	sendTimeout = 30 * time.Second
+	// sendBatch - кількість доставок, що надсилаються за один запуск
+	sendBatch = 100
+)
+
+// retryDelays - паузи перед повторними спробами; після останньої доставка вважається невдалою
+var retryDelays = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	12 * time.Hour,
+}
+
+// Deliveries повертає журнал доставки сповіщень користувача в зовнішні канали
+func (s *notificationService) Deliveries(ctx context.Context, userID uuid.UUID) ([]*models.NotificationDelivery, error) {
+	return s.deliveryRepo.ListByUser(ctx, userID, listLimit)
+}
+
+// SendDue надсилає доставки, час спроби яких настав, та повертає кількість успішних
+func (s *notificationService) SendDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.deliveryRepo.Claim(ctx, now, sendLease, sendBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	// Решту доставок після завершення оренди може взяти інший обробник
+	deadline := now.Add(sendLease - sendTimeout)
+	sent := 0
+	for _, delivery := range due {
+		if ctx.Err() != nil || time.Now().After(deadline) {
+			break
+		}
+		user, err := s.userRepo.GetByID(ctx, delivery.UserID)
+		if err != nil {
+			s.fail(ctx, delivery, fmt.Errorf("failed to load user: %w", err))
+			continue
+		}
+		if s.attempt(ctx, delivery, user) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// dispatch ставить доставки в канали, обрані користувачем, у чергу; першу спробу робить SendDue
+func (s *notificationService) dispatch(ctx context.Context, notification *models.Notification) {
+	if len(s.channels) == 0 {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(ctx, notification.UserID)
+	if err != nil {
+		log.Printf("Failed to load user %s for notification delivery: %v", notification.UserID, err)
+		return
+	}
+	settings, err := user.GetSettings()
+	if err != nil {
+		log.Printf("Failed to read notification settings of user %s: %v", user.ID, err)
+		return
+	}
+
+	var linked []models.NotificationChannel
+	for _, channel := range s.channels {
+		if channel.Linked(user) {
+			linked = append(linked, channel.Name())
+		}
+	}
+
+	for _, name := range settings.NotificationSettings.ChannelsFor(notification.Type, linked) {
+		next := time.Now()
+		delivery := &models.NotificationDelivery{
+			NotificationID: notification.ID,
+			UserID:         notification.UserID,
+			Channel:        name,
+			Status:         models.DeliveryStatusPending,
+			NextAttemptAt:  &next,
+			Notification:   notification,
+		}
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			log.Printf("Failed to queue notification %s for %s: %v", notification.ID, name, err)
+		}
+	}
+}
+
+// attempt надсилає сповіщення в канал доставки та записує результат; повертає true при успіху
+func (s *notificationService) attempt(ctx context.Context, delivery *models.NotificationDelivery, user *models.User) bool {
+	channel := s.channel(delivery.Channel)
+	if channel == nil {
+		s.fail(ctx, delivery, errors.New("channel is not configured"))
+		return false
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := channel.Send(sendCtx, user, &channels.Message{
+		Type:  delivery.Notification.Type,
+		Title: delivery.Notification.Title,
+		Text:  delivery.Notification.Message,
+
+		Markdown: delivery.Notification.Markdown,
+		HTML:     delivery.Notification.HTML,
+	})
+	cancel()
+
+	delivery.Attempts++
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryStatusSent
+		delivery.SentAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case errors.Is(err, channels.ErrNotLinked) || delivery.Attempts > len(retryDelays):
+		s.fail(ctx, delivery, err)
+		return false
+	default:
+		next := now.Add(retryDelays[delivery.Attempts-1])
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		log.Printf("Failed to update notification delivery %s: %v", delivery.ID, err)
+	}
+	return delivery.Status == models.DeliveryStatusSent
+}
+
+// fail позначає доставку невдалою без подальших спроб
+func (s *notificationService) fail(ctx context.Context, delivery *models.NotificationDelivery, cause error) {
+	delivery.Status = models.DeliveryStatusFailed
+	delivery.NextAttemptAt = nil
+	delivery.LastError = cause.Error()
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		log.Printf("Failed to update notification delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// channel повертає налаштований канал за назвою
+func (s *notificationService) channel(name models.NotificationChannel) channels.Channel {
+	for _, channel := range s.channels {
+		if channel.Name() == name {
+			return channel
+		}
+	}
+	return nil
+}
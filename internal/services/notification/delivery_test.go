@@ -0,0 +1,253 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/channels"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+// memoryDeliveries - журнал доставки в пам'яті
+type memoryDeliveries struct {
+	mu         sync.Mutex
+	deliveries map[uuid.UUID]models.NotificationDelivery
+}
+
+func (r *memoryDeliveries) Create(ctx context.Context, delivery *models.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryDeliveries) Update(ctx context.Context, delivery *models.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.LockedUntil = nil
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryDeliveries) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*models.NotificationDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status != models.DeliveryStatusPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if delivery.LockedUntil != nil && delivery.LockedUntil.After(now) {
+			continue
+		}
+		delivery := delivery
+		due = append(due, &delivery)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	lockedUntil := now.Add(lease)
+	for _, delivery := range due {
+		delivery.LockedUntil = &lockedUntil
+		r.deliveries[delivery.ID] = *delivery
+	}
+	return due, nil
+}
+
+func (r *memoryDeliveries) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*models.NotificationDelivery, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryDeliveries) get(id uuid.UUID) models.NotificationDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deliveries[id]
+}
+
+// makeDue переносить наступну спробу доставки в минуле
+func (r *memoryDeliveries) makeDue(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery := r.deliveries[id]
+	past := time.Now().Add(-time.Second)
+	delivery.NextAttemptAt = &past
+	r.deliveries[id] = delivery
+}
+
+// memoryUsers повертає користувачів з пам'яті; решта методів UserRepository у тестах не викликається
+type memoryUsers struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *memoryUsers) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, repositories.ErrUserNotFound
+}
+
+// newDeliveryTest створює сервіс з тестовим каналом і одну доставку, що очікує першої спроби
+func newDeliveryTest(t *testing.T) (*notificationService, *memoryDeliveries, *channels.Fake, *models.NotificationDelivery) {
+	t.Helper()
+	user := &models.User{ID: uuid.New()}
+	fake := channels.NewFake(models.NotificationChannelTelegram)
+	deliveries := &memoryDeliveries{deliveries: map[uuid.UUID]models.NotificationDelivery{}}
+	s := &notificationService{
+		deliveryRepo: deliveries,
+		userRepo:     &memoryUsers{users: map[uuid.UUID]*models.User{user.ID: user}},
+		channels:     []channels.Channel{fake},
+	}
+
+	next := time.Now().Add(-time.Second)
+	delivery := &models.NotificationDelivery{
+		NotificationID: uuid.New(),
+		UserID:         user.ID,
+		Channel:        fake.Name(),
+		Status:         models.DeliveryStatusPending,
+		NextAttemptAt:  &next,
+		Notification: &models.Notification{
+			UserID:  user.ID,
+			Type:    models.NotificationDeadlineSoon,
+			Title:   "Термін віддачі",
+			Message: "Залишилось 3 дні",
+		},
+	}
+	if err := deliveries.Create(context.Background(), delivery); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+	return s, deliveries, fake, delivery
+}
+
+func TestSendDueSchedulesRetryAndSends(t *testing.T) {
+	s, deliveries, fake, delivery := newDeliveryTest(t)
+	ctx := context.Background()
+
+	fake.Fail(errors.New("telegram is down"))
+	before := time.Now()
+	sent, err := s.SendDue(ctx)
+	if err != nil || sent != 0 {
+		t.Fatalf("SendDue = %d, %v; want 0, nil", sent, err)
+	}
+	got := deliveries.get(delivery.ID)
+	if got.Status != models.DeliveryStatusPending || got.Attempts != 1 {
+		t.Fatalf("after failure: status %s, attempts %d; want pending, 1", got.Status, got.Attempts)
+	}
+	if got.LastError != "telegram is down" {
+		t.Fatalf("last error = %q", got.LastError)
+	}
+	if got.NextAttemptAt == nil || got.NextAttemptAt.Before(before.Add(retryDelays[0])) {
+		t.Fatalf("next attempt = %v, want at least %s from now", got.NextAttemptAt, retryDelays[0])
+	}
+
+	// Пауза ще не минула - доставка не повторюється
+	if sent, _ := s.SendDue(ctx); sent != 0 || deliveries.get(delivery.ID).Attempts != 1 {
+		t.Fatal("delivery was retried before its next attempt")
+	}
+
+	fake.Fail(nil)
+	deliveries.makeDue(delivery.ID)
+	sent, err = s.SendDue(ctx)
+	if err != nil || sent != 1 {
+		t.Fatalf("SendDue = %d, %v; want 1, nil", sent, err)
+	}
+	got = deliveries.get(delivery.ID)
+	if got.Status != models.DeliveryStatusSent || got.Attempts != 2 || got.SentAt == nil || got.NextAttemptAt != nil || got.LastError != "" {
+		t.Fatalf("after retry: %+v", got)
+	}
+	if messages := fake.Sent(); len(messages) != 1 || messages[0].Message.Title != "Термін віддачі" {
+		t.Fatalf("sent messages = %+v", messages)
+	}
+}
+
+func TestSendDueGivesUpAfterLastDelay(t *testing.T) {
+	s, deliveries, fake, delivery := newDeliveryTest(t)
+	ctx := context.Background()
+	fake.Fail(errors.New("timeout"))
+
+	for attempt := 1; attempt <= len(retryDelays); attempt++ {
+		if _, err := s.SendDue(ctx); err != nil {
+			t.Fatalf("attempt %d: %v", attempt, err)
+		}
+		if got := deliveries.get(delivery.ID); got.Status != models.DeliveryStatusPending || got.Attempts != attempt {
+			t.Fatalf("attempt %d: status %s, attempts %d", attempt, got.Status, got.Attempts)
+		}
+		deliveries.makeDue(delivery.ID)
+	}
+
+	if _, err := s.SendDue(ctx); err != nil {
+		t.Fatalf("last attempt: %v", err)
+	}
+	got := deliveries.get(delivery.ID)
+	if got.Status != models.DeliveryStatusFailed || got.NextAttemptAt != nil {
+		t.Fatalf("after last attempt: status %s, next %v; want failed, nil", got.Status, got.NextAttemptAt)
+	}
+	if got.Attempts != len(retryDelays)+1 {
+		t.Fatalf("attempts = %d, want %d", got.Attempts, len(retryDelays)+1)
+	}
+	if due, _ := deliveries.Claim(ctx, time.Now().Add(24*time.Hour), sendLease, sendBatch); len(due) != 0 {
+		t.Fatal("failed delivery is still queued")
+	}
+}
+
+func TestSendDueStopsWhenChannelUnlinked(t *testing.T) {
+	s, deliveries, fake, delivery := newDeliveryTest(t)
+	fake.Fail(fmt.Errorf("send: %w", channels.ErrNotLinked))
+
+	if _, err := s.SendDue(context.Background()); err != nil {
+		t.Fatalf("SendDue: %v", err)
+	}
+	got := deliveries.get(delivery.ID)
+	if got.Status != models.DeliveryStatusFailed || got.Attempts != 1 {
+		t.Fatalf("status %s, attempts %d; want failed, 1", got.Status, got.Attempts)
+	}
+}
+
+func TestSendDueFailsForUnknownUser(t *testing.T) {
+	s, deliveries, _, delivery := newDeliveryTest(t)
+	s.userRepo = &memoryUsers{users: map[uuid.UUID]*models.User{}}
+
+	if _, err := s.SendDue(context.Background()); err != nil {
+		t.Fatalf("SendDue: %v", err)
+	}
+	if got := deliveries.get(delivery.ID); got.Status != models.DeliveryStatusFailed {
+		t.Fatalf("status = %s, want failed", got.Status)
+	}
+}
+
+func TestSendDueSkipsClaimedDelivery(t *testing.T) {
+	s, deliveries, fake, delivery := newDeliveryTest(t)
+	ctx := context.Background()
+
+	// Інший обробник уже взяв доставку
+	claimed, err := deliveries.Claim(ctx, time.Now(), sendLease, sendBatch)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Claim = %d deliveries, %v; want 1, nil", len(claimed), err)
+	}
+	if sent, err := s.SendDue(ctx); err != nil || sent != 0 {
+		t.Fatalf("SendDue = %d, %v; want 0, nil", sent, err)
+	}
+	if len(fake.Sent()) != 0 || deliveries.get(delivery.ID).Attempts != 0 {
+		t.Fatal("claimed delivery was sent twice")
+	}
+
+	// Після завершення оренди доставку підбирає наступний запуск
+	if _, err := deliveries.Claim(ctx, time.Now().Add(sendLease), sendLease, sendBatch); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if due, _ := deliveries.Claim(ctx, time.Now().Add(sendLease), sendLease, sendBatch); len(due) != 0 {
+		t.Fatal("delivery was claimed twice within one lease")
+	}
+}
@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// INotificationService визначає інтерфейс сервісу сповіщень
+type INotificationService interface {
+	// Notify створює сповіщення для користувача
+	Notify(ctx context.Context, notification *models.Notification) error
+
+	// List повертає останні сповіщення користувача
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*models.Notification, error)
+
+	// CountUnread повертає кількість непрочитаних сповіщень
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// MarkRead позначає сповіщення як прочитане
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+
+	// MarkAllRead позначає всі сповіщення користувача як прочитані
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+
+	// LatestOfType повертає останнє сповіщення вказаного типу або nil
+	LatestOfType(ctx context.Context, userID uuid.UUID, notificationType models.NotificationType) (*models.Notification, error)
+
+	// Deliveries повертає журнал доставки сповіщень у зовнішні канали
+	Deliveries(ctx context.Context, userID uuid.UUID) ([]*models.NotificationDelivery, error)
+
+	// SendDue надсилає доставки з черги, час спроби яких настав; повертає кількість успішних
+	SendDue(ctx context.Context) (int, error)
+
+	// TelegramStatus повертає стан підключення Telegram-бота
+	TelegramStatus(ctx context.Context, userID uuid.UUID) (*TelegramStatus, error)
+
+	// TelegramLink повертає посилання для підключення Telegram-бота
+	TelegramLink(ctx context.Context, userID uuid.UUID) (string, error)
+
+	// UnlinkTelegram відключає Telegram-бота від акаунта
+	UnlinkTelegram(ctx context.Context, userID uuid.UUID) error
+
+	// ServeTelegram обробляє команди Telegram-бота до скасування контексту
+	ServeTelegram(ctx context.Context)
+}
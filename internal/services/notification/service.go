@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/channels"
+	"timebride/internal/config"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+// listLimit - максимальна кількість сповіщень у списку
+const listLimit = 100
+
+var ErrInvalidNotification = errors.New("notification must have user and title")
+
+type notificationService struct {
+	config       *config.Config
+	repo         repositories.NotificationRepository
+	deliveryRepo repositories.NotificationDeliveryRepository
+	userRepo     repositories.UserRepository
+	channels     []channels.Channel
+	// telegram - бот для команд підключення; nil, якщо Telegram не налаштований
+	telegram *channels.Telegram
+}
+
+// NewNotificationService створює новий сервіс сповіщень.
+// Сповіщення зберігаються в застосунку та доставляються в передані зовнішні канали.
+func NewNotificationService(
+	cfg *config.Config,
+	repo repositories.NotificationRepository,
+	deliveryRepo repositories.NotificationDeliveryRepository,
+	userRepo repositories.UserRepository,
+	external ...channels.Channel,
+) INotificationService {
+	s := &notificationService{
+		config:       cfg,
+		repo:         repo,
+		deliveryRepo: deliveryRepo,
+		userRepo:     userRepo,
+		channels:     external,
+	}
+	for _, channel := range external {
+		if telegram, ok := channel.(*channels.Telegram); ok {
+			s.telegram = telegram
+		}
+	}
+	return s
+}
+
+// Notify створює сповіщення для користувача та ставить його в черги доставки
+// зовнішніх каналів, обраних користувачем для цього типу сповіщень
+func (s *notificationService) Notify(ctx context.Context, notification *models.Notification) error {
+	if notification.UserID == uuid.Nil || notification.Title == "" {
+		return ErrInvalidNotification
+	}
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	s.dispatch(ctx, notification)
+	return nil
+}
+
+// List повертає останні сповіщення користувача
+func (s *notificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]*models.Notification, error) {
+	return s.repo.ListByUser(ctx, userID, unreadOnly, listLimit)
+}
+
+// CountUnread повертає кількість непрочитаних сповіщень
+func (s *notificationService) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead позначає сповіщення як прочитане
+func (s *notificationService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead позначає всі сповіщення користувача як прочитані
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// LatestOfType повертає останнє сповіщення вказаного типу або nil
+func (s *notificationService) LatestOfType(ctx context.Context, userID uuid.UUID, notificationType models.NotificationType) (*models.Notification, error) {
+	return s.repo.LatestOfType(ctx, userID, notificationType)
+}
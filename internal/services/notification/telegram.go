@@ -0,0 +1,140 @@
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/channels"
+)
+
+// telegramLinkTTL - час дії посилання для підключення бота
+const telegramLinkTTL = 15 * time.Minute
+
+var (
+	ErrChannelUnavailable = errors.New("notification channel is not configured")
+	ErrInvalidLinkToken   = errors.New("invalid or expired link token")
+)
+
+// TelegramStatus - стан підключення Telegram для користувача
+type TelegramStatus struct {
+	// Available - бот налаштований на сервері
+	Available bool   `json:"available"`
+	Linked    bool   `json:"linked"`
+	Bot       string `json:"bot,omitempty"`
+}
+
+// TelegramStatus повертає стан підключення бота користувачем
+func (s *notificationService) TelegramStatus(ctx context.Context, userID uuid.UUID) (*TelegramStatus, error) {
+	if s.telegram == nil {
+		return &TelegramStatus{}, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &TelegramStatus{
+		Available: true,
+		Linked:    s.telegram.Linked(user),
+		Bot:       s.telegram.Username(),
+	}, nil
+}
+
+// TelegramLink повертає посилання t.me, що відкриває бота з командою /start <токен>.
+// Токен підписаний і діє telegramLinkTTL, тож його не потрібно зберігати.
+func (s *notificationService) TelegramLink(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.telegram == nil || s.telegram.Username() == "" {
+		return "", ErrChannelUnavailable
+	}
+	token := s.linkToken(userID, time.Now().Add(telegramLinkTTL))
+	return fmt.Sprintf("https://t.me/%s?start=%s", s.telegram.Username(), token), nil
+}
+
+// UnlinkTelegram відключає бота від акаунта користувача
+func (s *notificationService) UnlinkTelegram(ctx context.Context, userID uuid.UUID) error {
+	return s.userRepo.SetTelegramChatID(ctx, userID, "")
+}
+
+// ServeTelegram обробляє команди бота до скасування контексту; без налаштованого бота одразу завершується
+func (s *notificationService) ServeTelegram(ctx context.Context) {
+	if s.telegram == nil {
+		return
+	}
+	s.telegram.Poll(ctx, s.handleTelegram)
+}
+
+// handleTelegram виконує команду, надіслану боту, та повертає відповідь
+func (s *notificationService) handleTelegram(ctx context.Context, update channels.TelegramUpdate) string {
+	command, argument, _ := strings.Cut(strings.TrimSpace(update.Text), " ")
+	// У групах команда може містити ім'я бота: /start@bot
+	command, _, _ = strings.Cut(command, "@")
+
+	switch command {
+	case "/start":
+		if argument == "" {
+			return "Щоб отримувати сповіщення TimeBride, відкрийте налаштування сповіщень у застосунку та натисніть «Підключити Telegram»."
+		}
+		userID, err := s.parseLinkToken(strings.TrimSpace(argument), time.Now())
+		if err != nil {
+			return "Посилання для підключення недійсне або застаріло. Створіть нове в налаштуваннях сповіщень."
+		}
+		if err := s.userRepo.SetTelegramChatID(ctx, userID, update.ChatID); err != nil {
+			log.Printf("Failed to link telegram chat for user %s: %v", userID, err)
+			return "Не вдалося підключити сповіщення. Спробуйте пізніше."
+		}
+		return "Готово! Сповіщення TimeBride надходитимуть у цей чат. Щоб відключити їх, надішліть /stop."
+	case "/stop":
+		if err := s.userRepo.UnlinkTelegramChat(ctx, update.ChatID); err != nil {
+			log.Printf("Failed to unlink telegram chat: %v", err)
+			return "Не вдалося відключити сповіщення. Спробуйте пізніше."
+		}
+		return "Сповіщення відключено. Підключити їх знову можна в налаштуваннях застосунку."
+	default:
+		return "Цей бот надсилає сповіщення TimeBride. /stop - відключити сповіщення."
+	}
+}
+
+// linkToken формує токен підключення: ID користувача, час завершення дії та підпис
+func (s *notificationService) linkToken(userID uuid.UUID, expires time.Time) string {
+	payload := make([]byte, 20, 32)
+	copy(payload, userID[:])
+	binary.BigEndian.PutUint32(payload[16:], uint32(expires.Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.linkSignature(payload)...))
+}
+
+// parseLinkToken перевіряє підпис і термін дії токена та повертає ID користувача
+func (s *notificationService) parseLinkToken(token string, now time.Time) (uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != 32 {
+		return uuid.Nil, ErrInvalidLinkToken
+	}
+	payload, signature := raw[:20], raw[20:]
+	if !hmac.Equal(signature, s.linkSignature(payload)) {
+		return uuid.Nil, ErrInvalidLinkToken
+	}
+	if now.Unix() > int64(binary.BigEndian.Uint32(payload[16:])) {
+		return uuid.Nil, ErrInvalidLinkToken
+	}
+
+	var userID uuid.UUID
+	copy(userID[:], payload[:16])
+	return userID, nil
+}
+
+// linkSignature - скорочений HMAC-SHA256 токена; параметр /start обмежений 64 символами
+func (s *notificationService) linkSignature(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(s.config.JWT.Secret))
+	mac.Write([]byte("telegram-link:"))
+	mac.Write(payload)
+	return mac.Sum(nil)[:12]
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/config"
+)
+
+func newTestService(secret string) *notificationService {
+	return &notificationService{config: &config.Config{JWT: config.JWTConfig{Secret: secret}}}
+}
+
+func TestParseLinkToken(t *testing.T) {
+	s := newTestService("secret")
+	userID := uuid.New()
+	now := time.Now()
+	token := s.linkToken(userID, now.Add(telegramLinkTTL))
+
+	if len(token) > 64 {
+		t.Fatalf("token is %d characters, /start allows 64", len(token))
+	}
+
+	got, err := s.parseLinkToken(token, now)
+	if err != nil {
+		t.Fatalf("parseLinkToken: %v", err)
+	}
+	if got != userID {
+		t.Fatalf("user = %s, want %s", got, userID)
+	}
+
+	// Токен дійсний до останньої секунди включно
+	if _, err := s.parseLinkToken(token, now.Add(telegramLinkTTL)); err != nil {
+		t.Fatalf("token rejected at expiry: %v", err)
+	}
+}
+
+func TestParseLinkTokenExpired(t *testing.T) {
+	s := newTestService("secret")
+	now := time.Now()
+	token := s.linkToken(uuid.New(), now.Add(telegramLinkTTL))
+
+	_, err := s.parseLinkToken(token, now.Add(telegramLinkTTL+time.Second))
+	if !errors.Is(err, ErrInvalidLinkToken) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidLinkToken)
+	}
+}
+
+func TestParseLinkTokenTampered(t *testing.T) {
+	s := newTestService("secret")
+	now := time.Now()
+	token := s.linkToken(uuid.New(), now.Add(telegramLinkTTL))
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+
+	tamper := func(i int) string {
+		changed := append([]byte(nil), raw...)
+		changed[i] ^= 1
+		return base64.RawURLEncoding.EncodeToString(changed)
+	}
+	tests := map[string]string{
+		"user":          tamper(0),
+		"expiry":        tamper(19),
+		"signature":     tamper(len(raw) - 1),
+		"truncated":     token[:len(token)-2],
+		"not base64":    "!" + token[1:],
+		"empty":         "",
+		"other secret":  newTestService("other").linkToken(uuid.New(), now.Add(telegramLinkTTL)),
+		"extended life": forgeExpiry(s, raw, now.Add(24*time.Hour)),
+	}
+	for name, token := range tests {
+		if _, err := s.parseLinkToken(token, now); !errors.Is(err, ErrInvalidLinkToken) {
+			t.Errorf("%s: error = %v, want %v", name, err, ErrInvalidLinkToken)
+		}
+	}
+}
+
+// forgeExpiry переписує час дії токена, залишаючи старий підпис
+func forgeExpiry(s *notificationService, raw []byte, expires time.Time) string {
+	var userID uuid.UUID
+	copy(userID[:], raw[:16])
+	forged, _ := base64.RawURLEncoding.DecodeString(s.linkToken(userID, expires))
+	copy(forged[20:], raw[20:])
+	return base64.RawURLEncoding.EncodeToString(forged)
+}
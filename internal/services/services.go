@@ -1,14 +1,22 @@
 package services
 
 import (
+	"timebride/internal/jobs"
 	"timebride/internal/services/auth"
 	"timebride/internal/services/booking"
 	"timebride/internal/services/client"
+	"timebride/internal/services/contract"
+	"timebride/internal/services/deadline"
+	"timebride/internal/services/digest"
+	"timebride/internal/services/email"
+	"timebride/internal/services/gallery"
+	"timebride/internal/services/notification"
 	"timebride/internal/services/price"
 	"timebride/internal/services/storage"
 	"timebride/internal/services/team"
 	"timebride/internal/services/template"
 	"timebride/internal/services/user"
+	"timebride/internal/services/webhook"
 )
 
 // Services містить всі сервіси програми
@@ -21,6 +29,22 @@ type Services struct {
 	Price    price.IPriceService
 	Storage  storage.IStorageService
 	Template template.ITemplateService
+	// Notification - сповіщення користувачів у застосунку
+	Notification notification.INotificationService
+	// Gallery - публічні сторінки віддачі матеріалів клієнтам
+	Gallery gallery.IGalleryService
+	// Contract - версії договорів бронювань
+	Contract contract.IContractService
+	// Email - транзакційні листи через чергу надсилання
+	Email email.IEmailService
+	// Deadline - терміни віддачі матеріалів та нагадування про них
+	Deadline deadline.IDeadlineService
+	// Digest - щоденні та щотижневі звіти
+	Digest digest.IDigestService
+	// Webhook - вихідні вебхуки для зовнішніх інтеграцій
+	Webhook webhook.IWebhookService
+	// Jobs - черга фонових задач
+	Jobs jobs.IJobQueue
 }
 
 // NewServices створює нову структуру Services
@@ -33,6 +57,14 @@ func NewServices(
 	priceSvc price.IPriceService,
 	storageSvc storage.IStorageService,
 	templateSvc template.ITemplateService,
+	notificationSvc notification.INotificationService,
+	gallerySvc gallery.IGalleryService,
+	contractSvc contract.IContractService,
+	emailSvc email.IEmailService,
+	deadlineSvc deadline.IDeadlineService,
+	digestSvc digest.IDigestService,
+	webhookSvc webhook.IWebhookService,
+	jobQueue jobs.IJobQueue,
 ) *Services {
 	return &Services{
 		Auth:     authSvc,
@@ -43,5 +75,14 @@ func NewServices(
 		Price:    priceSvc,
 		Storage:  storageSvc,
 		Template: templateSvc,
+
+		Notification: notificationSvc,
+		Gallery:      gallerySvc,
+		Contract:     contractSvc,
+		Email:        emailSvc,
+		Deadline:     deadlineSvc,
+		Digest:       digestSvc,
+		Webhook:      webhookSvc,
+		Jobs:         jobQueue,
 	}
 }
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"timebride/internal/models"
+)
+
+// WriteArchive потоково записує ZIP-архів з файлами у w.
+// Файли зберігаються без стиснення (фото та відео вже стиснуті), тому архів
+// формується без буферизації на диску. ZIP64 вмикається автоматично для
+// архівів та файлів понад 4 ГБ.
+func (s *storageService) WriteArchive(ctx context.Context, w io.Writer, files []*models.File) error {
+	zw := zip.NewWriter(w)
+	names := make(map[string]int, len(files))
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Файли в карантині або ще не перевірені до архіву не потрапляють
+		if !file.IsClean() {
+			continue
+		}
+
+		if err := s.writeArchiveEntry(ctx, zw, file, archiveName(file, names)); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (s *storageService) writeArchiveEntry(ctx context.Context, zw *zip.Writer, file *models.File, name string) error {
+	content, err := s.OpenFile(ctx, file)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", file.ID, err)
+	}
+	defer content.Reader.Close()
+
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: file.CreatedAt,
+	}
+	header.UncompressedSize64 = uint64(content.Size)
+
+	entry, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(entry, content.Reader); err != nil {
+		return fmt.Errorf("failed to write file %s to archive: %w", file.ID, err)
+	}
+	return nil
+}
+
+// archiveName повертає шлях файлу в архіві на основі ключа сховища (без ID користувача).
+// Однакові імена отримують суфікс " (2)", " (3)" і т.д.
+func archiveName(file *models.File, used map[string]int) string {
+	key := file.GetStorageKey()
+	if _, rest, found := strings.Cut(key, "/"); found {
+		key = rest
+	}
+	key = path.Clean("/" + key)[1:]
+
+	used[key]++
+	if used[key] == 1 {
+		return key
+	}
+
+	ext := path.Ext(key)
+	base := strings.TrimSuffix(key, ext)
+	for {
+		candidate := fmt.Sprintf("%s (%d)%s", base, used[key], ext)
+		if _, taken := used[candidate]; !taken {
+			used[candidate] = 1
+			return candidate
+		}
+		used[key]++
+	}
+}
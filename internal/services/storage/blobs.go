@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+// spoolUpload копіює вміст у тимчасовий файл, паралельно рахуючи SHA-256,
+// тож великі файли не потрібно читати двічі
+func (s *storageService) spoolUpload(src io.Reader) (string, string, int64, error) {
+	tmpDir, err := s.localPath("tmp")
+	if err != nil {
+		return "", "", 0, err
+	}
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", "", 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(tmpDir, "upload-*")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), src)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", "", 0, fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	return tmp.Name(), hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+// commitBlob додає посилання на blob з вказаним хешем. Новий вміст переноситься
+// з тимчасового файлу у content-addressed шлях і списується з квоти один раз;
+// для вже відомого вмісту тимчасовий файл просто видаляється.
+func (s *storageService) commitBlob(ctx context.Context, userID uuid.UUID, tmpPath, hash string, size int64) (*models.FileBlob, error) {
+	defer os.Remove(tmpPath)
+
+	blob, created, err := s.blobRepo.Acquire(ctx, &models.FileBlob{
+		UserID: userID,
+		Hash:   hash,
+		Size:   size,
+		Path:   models.BlobPath(userID, hash),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register blob: %w", err)
+	}
+
+	fullPath, err := s.localPath(blob.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		if err := s.userRepo.ReserveStorage(ctx, userID, size); err != nil {
+			s.rollbackBlob(ctx, userID, hash, false)
+			return nil, err
+		}
+	}
+
+	// Переносимо вміст, якщо blob новий або його файл було втрачено
+	if _, statErr := os.Stat(fullPath); os.IsNotExist(statErr) {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			s.rollbackBlob(ctx, userID, hash, created)
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.Rename(tmpPath, fullPath); err != nil {
+			s.rollbackBlob(ctx, userID, hash, created)
+			return nil, fmt.Errorf("failed to store file: %w", err)
+		}
+	}
+
+	return blob, nil
+}
+
+// rollbackBlob відміняє Acquire після невдалого завантаження
+func (s *storageService) rollbackBlob(ctx context.Context, userID uuid.UUID, hash string, charged bool) {
+	blob, removed, err := s.blobRepo.Release(ctx, userID, hash)
+	if err != nil {
+		log.Printf("Failed to rollback blob %s: %v", hash, err)
+		return
+	}
+	if removed && charged {
+		if err := s.userRepo.ReleaseStorage(ctx, userID, blob.Size); err != nil {
+			log.Printf("Failed to release storage for blob %s: %v", hash, err)
+		}
+	}
+}
+
+// releaseBlob знімає посилання файлу на blob; вміст видаляється, а квота
+// повертається лише тоді, коли зникає останнє посилання. within, якщо задано,
+// виконується в тій самій транзакції, тож файли та квота звільняються лише після її фіксації.
+func (s *storageService) releaseBlob(ctx context.Context, file *models.File, within func(ctx context.Context) error) error {
+	if file.ContentHash == "" {
+		// Файли, завантажені до дедуплікації, зберігаються за власним шляхом
+		if within != nil {
+			if err := s.tx.Do(ctx, within); err != nil {
+				return err
+			}
+		}
+		s.removeThumbnails(file)
+		return s.removeLocal(file.Path)
+	}
+
+	// Вміст відкладається вбік до фіксації, поки рядок blob заблокований. Паралельний Acquire
+	// того самого хешу чекає на цю транзакцію і після неї переносить вміст заново.
+	var blob *models.FileBlob
+	var removed bool
+	var trash string
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if within != nil {
+			if err := within(ctx); err != nil {
+				return err
+			}
+		}
+		var err error
+		blob, removed, err = s.blobRepo.Release(ctx, file.UserID, file.ContentHash)
+		if errors.Is(err, repositories.ErrFileBlobNotFound) {
+			// Посилання вже немає - знімати нічого
+			return nil
+		}
+		if err != nil || !removed {
+			return err
+		}
+		trash, err = s.discardLocal(blob.Path)
+		return err
+	})
+	if err != nil {
+		if trash != "" {
+			s.restoreLocal(trash, blob.Path)
+		}
+		return err
+	}
+	if !removed {
+		return nil
+	}
+
+	if trash != "" {
+		if err := os.Remove(trash); err != nil {
+			log.Printf("Failed to delete released blob %s: %v", blob.Hash, err)
+		}
+	}
+	s.removeThumbnails(file)
+	return s.userRepo.ReleaseStorage(ctx, file.UserID, blob.Size)
+}
+
+// discardLocal переносить файл сховища під тимчасове ім'я і повертає його;
+// порожній рядок - файлу вже немає
+func (s *storageService) discardLocal(path string) (string, error) {
+	fullPath, err := s.localPath(path)
+	if err != nil {
+		return "", err
+	}
+	trash := fullPath + ".deleted-" + uuid.NewString()
+	if err := os.Rename(fullPath, trash); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to delete file: %w", err)
+	}
+	return trash, nil
+}
+
+// restoreLocal повертає файл, відкладений discardLocal, якщо транзакцію відкочено
+func (s *storageService) restoreLocal(trash, path string) {
+	fullPath, err := s.localPath(path)
+	if err == nil {
+		err = os.Rename(trash, fullPath)
+	}
+	if err != nil {
+		log.Printf("Failed to restore blob %s: %v", path, err)
+	}
+}
+
+// removeLocal видаляє файл з локального сховища
+func (s *storageService) removeLocal(path string) error {
+	fullPath, err := s.localPath(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
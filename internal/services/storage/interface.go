@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"mime/multipart"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -12,11 +13,29 @@ import (
 
 // IStorageService визначає інтерфейс сервісу сховища
 type IStorageService interface {
-	// UploadFile завантажує файл та повертає його URL
-	UploadFile(ctx context.Context, file *multipart.FileHeader, path string) (string, error)
+	// UploadFile завантажує файл, дедуплікуючи вміст за SHA-256, та створює запис в БД
+	UploadFile(ctx context.Context, input *UploadInput) (*models.File, error)
 
-	// DeleteFile видаляє файл за шляхом
-	DeleteFile(ctx context.Context, path string) error
+	// DeleteFile переміщує файл у кошик
+	DeleteFile(ctx context.Context, id uuid.UUID) error
+
+	// RestoreFile повертає файл з кошика
+	RestoreFile(ctx context.Context, id uuid.UUID) (*models.File, error)
+
+	// GetTrashedFile отримує файл з кошика за ID
+	GetTrashedFile(ctx context.Context, id uuid.UUID) (*models.File, error)
+
+	// ListTrash отримує файли користувача в кошику
+	ListTrash(ctx context.Context, userID uuid.UUID) ([]*models.File, error)
+
+	// PurgeFile остаточно видаляє файл з кошика; вміст видаляється разом з останнім посиланням на нього
+	PurgeFile(ctx context.Context, id uuid.UUID) error
+
+	// EmptyTrash остаточно видаляє всі файли користувача з кошика
+	EmptyTrash(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// PurgeExpiredTrash остаточно видаляє файли, термін зберігання яких у кошику минув
+	PurgeExpiredTrash(ctx context.Context) (int, error)
 
 	// GetFile отримує файл за ID
 	GetFile(ctx context.Context, id uuid.UUID) (*models.File, error)
@@ -24,9 +43,71 @@ type IStorageService interface {
 	// ListFiles отримує список файлів
 	ListFiles(ctx context.Context, filter map[string]interface{}) ([]*models.File, error)
 
+	// SearchFiles отримує файли користувача за фільтрами метаданих
+	SearchFiles(ctx context.Context, userID uuid.UUID, opts models.FileSearchOptions) ([]*models.File, error)
+
 	// DownloadFile завантажує файл та повертає його вміст
 	DownloadFile(ctx context.Context, path string) (io.ReadCloser, error)
 
-	// GetFileURL повертає URL файлу
-	GetFileURL(ctx context.Context, path string) string
+	// GetFileURL повертає підписаний URL файлу з терміном дії за замовчуванням
+	GetFileURL(ctx context.Context, file *models.File) string
+
+	// GetSignedURL повертає підписаний URL файлу з заданим терміном дії та лімітом завантажень
+	GetSignedURL(ctx context.Context, file *models.File, opts SignedURLOptions) (string, error)
+
+	// OpenSignedFile перевіряє підписане посилання та відкриває файл для читання
+	OpenSignedFile(ctx context.Context, path string, params SignedURLParams) (*FileContent, error)
+
+	// OpenFile відкриває файл для читання
+	OpenFile(ctx context.Context, file *models.File) (*FileContent, error)
+
+	// RescanPending повторно перевіряє файли, перевірка яких не вдалася або не завершилася
+	RescanPending(ctx context.Context) (int, error)
+
+	// ScanFile перевіряє завантажений файл; обробник задачі ScanJob
+	ScanFile(ctx context.Context, fileID uuid.UUID) error
+
+	// OpenThumbnail відкриває зменшену копію зображення (ThumbnailSmall або ThumbnailLarge)
+	OpenThumbnail(ctx context.Context, file *models.File, size int) (*FileContent, error)
+
+	// OpenPreview відкриває зменшену копію зображення з водяним знаком
+	OpenPreview(ctx context.Context, file *models.File, size int, wm *Watermark) (*FileContent, error)
+
+	// WriteArchive потоково записує ZIP-архів з файлами
+	WriteArchive(ctx context.Context, w io.Writer, files []*models.File) error
+}
+
+// UploadInput містить дані для завантаження файлу
+type UploadInput struct {
+	UserID    uuid.UUID
+	BookingID *uuid.UUID
+	// Type визначається за MIME-типом, якщо не задано
+	Type models.FileType
+	// Folder - папка файлу в межах бронювання (models.FolderFromClient)
+	Folder string
+	File   *multipart.FileHeader
+	// Content, Name та ContentType задають вміст, згенерований сервером, якщо File не задано
+	Content     io.Reader
+	Name        string
+	ContentType string
+	// Generated - вміст сформовано сервером (PDF договорів), перевірка на шкідливе ПЗ не потрібна
+	Generated bool
+}
+
+// SignedURLOptions визначає обмеження підписаного посилання
+type SignedURLOptions struct {
+	// Expiry - термін дії посилання; за замовчуванням береться з конфігурації
+	Expiry time.Duration
+	// MaxDownloads - максимальна кількість завантажень (0 - без обмежень, 1 - одноразове).
+	// Для хмарного сховища ліміт не підтримується, оскільки presigned URL обслуговує S3.
+	MaxDownloads int
+}
+
+// FileContent представляє відкритий для читання файл
+type FileContent struct {
+	Reader      io.ReadSeekCloser
+	Name        string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
 }
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/config"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+// memoryLinks - посилання на завантаження в пам'яті
+type memoryLinks struct {
+	links map[uuid.UUID]*models.FileLink
+}
+
+func (r *memoryLinks) Create(ctx context.Context, link *models.FileLink) error {
+	link.ID = uuid.New()
+	r.links[link.ID] = link
+	return nil
+}
+
+func (r *memoryLinks) GetByID(ctx context.Context, id uuid.UUID) (*models.FileLink, error) {
+	if link, ok := r.links[id]; ok {
+		return link, nil
+	}
+	return nil, errors.New("link not found")
+}
+
+func (r *memoryLinks) Consume(ctx context.Context, id uuid.UUID) error {
+	link := r.links[id]
+	if link.MaxDownloads > 0 && link.Downloads >= link.MaxDownloads {
+		return repositories.ErrFileLinkExhausted
+	}
+	link.Downloads++
+	return nil
+}
+
+// memoryFiles повертає файли з пам'яті; решта методів FileRepository у тестах не викликається
+type memoryFiles struct {
+	repositories.FileRepository
+	files map[uuid.UUID]*models.File
+}
+
+func (r *memoryFiles) GetByID(ctx context.Context, id uuid.UUID) (*models.File, error) {
+	if file, ok := r.files[id]; ok {
+		return file, nil
+	}
+	return nil, errors.New("file not found")
+}
+
+func TestOpenSignedFileConsumesLinkOncePerDownload(t *testing.T) {
+	root := t.TempDir()
+	content := make([]byte, 4096)
+	if err := os.MkdirAll(filepath.Join(root, "blobs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "blobs", "video.mp4"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := &models.File{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Name:       "video.mp4",
+		Path:       "blobs/video.mp4",
+		Size:       int64(len(content)),
+		ScanStatus: models.ScanStatusClean,
+	}
+	links := &memoryLinks{links: map[uuid.UUID]*models.FileLink{}}
+	s := &storageService{
+		config:      &config.Config{Storage: config.StorageConfig{Provider: "local"}},
+		fileRepo:    &memoryFiles{files: map[uuid.UUID]*models.File{file.ID: file}},
+		linkRepo:    links,
+		signer:      newURLSigner("secret"),
+		storagePath: root,
+	}
+	ctx := context.Background()
+
+	signed, err := s.GetSignedURL(ctx, file, SignedURLOptions{Expiry: time.Hour, MaxDownloads: 1})
+	if err != nil {
+		t.Fatalf("GetSignedURL: %v", err)
+	}
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	query := parsed.Query()
+	open := func(rangeHeader string) error {
+		content, err := s.OpenSignedFile(ctx, file.Path, SignedURLParams{
+			Expires:   query.Get("expires"),
+			LinkID:    query.Get("link"),
+			Name:      query.Get("name"),
+			Signature: query.Get("sig"),
+			Range:     rangeHeader,
+		})
+		if err == nil {
+			content.Reader.Close()
+		}
+		return err
+	}
+
+	// Плеєр починає з першого байта, а далі перемотує та дочитує частинами
+	for _, rangeHeader := range []string{"bytes=0-1023", "bytes=1024-2047", "bytes=2048-", "bytes=1024-4095"} {
+		if err := open(rangeHeader); err != nil {
+			t.Fatalf("Range %q: %v", rangeHeader, err)
+		}
+	}
+	link := links.links[uuid.MustParse(query.Get("link"))]
+	if link.Downloads != 1 {
+		t.Fatalf("downloads = %d, want 1", link.Downloads)
+	}
+
+	// Нове завантаження з початку файлу вичерпує посилання
+	for _, rangeHeader := range []string{"", "bytes=0-", "bytes=-4096"} {
+		if err := open(rangeHeader); !errors.Is(err, repositories.ErrFileLinkExhausted) {
+			t.Errorf("Range %q: error = %v, want %v", rangeHeader, err, repositories.ErrFileLinkExhausted)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseRange розбирає заголовок Range з одним діапазоном байтів
+// ("bytes=0-499", "bytes=500-", "bytes=-500")
+func ParseRange(header string, size int64) (int64, int64, bool) {
+	if !strings.HasPrefix(header, "bytes=") || size == 0 {
+		return 0, 0, false
+	}
+	spec := strings.TrimSpace(strings.TrimPrefix(header, "bytes="))
+	if strings.Contains(spec, ",") {
+		// Кілька діапазонів віддаємо як перший - браузерам цього достатньо
+		spec = strings.TrimSpace(strings.SplitN(spec, ",", 2)[0])
+	}
+
+	startStr, endStr, found := strings.Cut(spec, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	if startStr == "" {
+		// Суфіксний діапазон: останні N байтів
+		suffix, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || suffix <= 0 {
+			return 0, 0, false
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, size - 1, true
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false
+	}
+
+	end := size - 1
+	if endStr != "" {
+		end, err = strconv.ParseInt(endStr, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	return start, end, true
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"timebride/internal/config"
+)
+
+// maxPresignExpiry - максимальний термін дії presigned URL, який допускає S3
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+var ErrMissingCredentials = errors.New("storage credentials are not configured")
+
+// presignS3GetURL формує presigned GET URL (AWS Signature V4, query auth) для
+// S3-сумісного сховища. Використовується path-style адресація, яку підтримують
+// як AWS S3, так і Backblaze B2.
+func presignS3GetURL(cfg *config.StorageConfig, key string, expiry time.Duration, now time.Time, filename string) (string, error) {
+	if !cfg.HasCredentials() {
+		return "", ErrMissingCredentials
+	}
+
+	endpoint, err := url.Parse(cfg.GetEndpoint())
+	if err != nil || endpoint.Host == "" {
+		return "", fmt.Errorf("invalid storage endpoint: %q", cfg.GetEndpoint())
+	}
+
+	if expiry <= 0 || expiry > maxPresignExpiry {
+		expiry = maxPresignExpiry
+	}
+
+	region := cfg.GetRegion()
+	amzDate := now.UTC().Format("20060102T150405Z")
+	shortDate := amzDate[:8]
+	scope := shortDate + "/" + region + "/s3/aws4_request"
+
+	canonicalURI := "/" + s3Escape(cfg.Path, false) + "/" + s3Escape(strings.TrimPrefix(key, "/"), true)
+
+	params := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    cfg.AccessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if filename != "" {
+		params["response-content-disposition"] = fmt.Sprintf("attachment; filename=%q", filename)
+	}
+	canonicalQuery := s3CanonicalQuery(params)
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		canonicalURI,
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+cfg.SecretKey), shortDate)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s",
+		endpoint.Scheme, endpoint.Host, canonicalURI, canonicalQuery, signature), nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery будує відсортований та закодований рядок запиту
+func s3CanonicalQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, s3Escape(k, false)+"="+s3Escape(params[k], false))
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape кодує рядок за правилами SigV4: без змін залишаються лише
+// незарезервовані символи (та '/', якщо keepSlash)
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
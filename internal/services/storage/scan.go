@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"timebride/internal/jobs"
+	"timebride/internal/models"
+)
+
+const (
+	// ScanJob - тип фонової задачі перевірки завантаженого файлу
+	ScanJob = "storage.scan"
+	// ScanTimeout обмежує час перевірки одного файлу
+	ScanTimeout = 10 * time.Minute
+	// rescanDelay - через скільки часу файл без результату перевірки вважається завислим
+	rescanDelay = 15 * time.Minute
+	// rescanBatchSize - кількість файлів, що повторно перевіряються за один прохід
+	rescanBatchSize = 50
+)
+
+var (
+	ErrFileNotScanned  = errors.New("file has not been scanned yet")
+	ErrFileQuarantined = errors.New("file is quarantined")
+)
+
+// checkScan дозволяє доступ до вмісту лише для файлів, що пройшли перевірку
+func checkScan(file *models.File) error {
+	switch file.ScanStatus {
+	case models.ScanStatusClean:
+		return nil
+	case models.ScanStatusInfected:
+		return ErrFileQuarantined
+	default:
+		return ErrFileNotScanned
+	}
+}
+
+// ScanPayload - параметри задачі ScanJob
+type ScanPayload struct {
+	FileID uuid.UUID `json:"file_id"`
+}
+
+// queueScan ставить перевірку файлу в чергу задач, щоб не затримувати відповідь на завантаження.
+// Якщо задачу не вдалося додати, файл перевірить RescanPending.
+func (s *storageService) queueScan(ctx context.Context, file *models.File) {
+	_, err := s.jobs.Enqueue(ctx, jobs.Request{
+		Type:      ScanJob,
+		Payload:   ScanPayload{FileID: file.ID},
+		UniqueKey: ScanJob + ":" + file.ID.String(),
+	})
+	if err != nil {
+		log.Printf("Failed to queue scan of file %s: %v", file.ID, err)
+	}
+}
+
+// ScanFile перевіряє файл з черги задач; видалені та вже перевірені файли пропускаються
+func (s *storageService) ScanFile(ctx context.Context, fileID uuid.UUID) error {
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if file.ScanStatus == models.ScanStatusClean || file.ScanStatus == models.ScanStatusInfected {
+		return nil
+	}
+	return s.scanFile(ctx, file)
+}
+
+// scanFile перевіряє вміст файлу та зберігає результат.
+// Помилка сканера зберігається як failed, і файл буде перевірено повторно.
+func (s *storageService) scanFile(ctx context.Context, file *models.File) error {
+	content, err := s.openLocal(file.Path, file.Name, file.MimeType)
+	if err != nil {
+		return s.saveScanResult(ctx, file, models.ScanStatusFailed, "", err)
+	}
+	defer content.Reader.Close()
+
+	result, err := s.scanner.Scan(ctx, content.Reader)
+	if err != nil {
+		return s.saveScanResult(ctx, file, models.ScanStatusFailed, "", err)
+	}
+	if !result.Infected {
+		return s.saveScanResult(ctx, file, models.ScanStatusClean, "", nil)
+	}
+
+	if err := s.saveScanResult(ctx, file, models.ScanStatusInfected, result.Signature, nil); err != nil {
+		return err
+	}
+	s.notifyInfected(ctx, file, result.Signature)
+	return nil
+}
+
+func (s *storageService) saveScanResult(ctx context.Context, file *models.File, status models.ScanStatus, signature string, scanErr error) error {
+	if err := s.fileRepo.UpdateScanResult(ctx, file.ID, status, signature); err != nil {
+		return fmt.Errorf("failed to save scan result: %w", err)
+	}
+	file.ScanStatus = status
+	file.ScanSignature = signature
+	return scanErr
+}
+
+// notifyInfected повідомляє власника про файл, поміщений у карантин
+func (s *storageService) notifyInfected(ctx context.Context, file *models.File, signature string) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"file_id":    file.ID,
+		"file_name":  file.Name,
+		"booking_id": file.BookingID,
+		"signature":  signature,
+	})
+
+	err := s.notifier.Notify(ctx, &models.Notification{
+		UserID:  file.UserID,
+		Type:    models.NotificationFileInfected,
+		Title:   "Файл поміщено в карантин",
+		Message: fmt.Sprintf("У файлі %q виявлено загрозу %s. Завантаження файлу заблоковано.", file.Name, signature),
+		Data:    data,
+	})
+	if err != nil {
+		log.Printf("Failed to notify about infected file %s: %v", file.ID, err)
+	}
+}
+
+// RescanPending повторно перевіряє файли, перевірка яких не вдалася
+// або не завершилася (наприклад, через перезапуск сервера)
+func (s *storageService) RescanPending(ctx context.Context) (int, error) {
+	files, err := s.fileRepo.ListUnscanned(ctx, time.Now().Add(-rescanDelay), rescanBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	scanned := 0
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return scanned, ctx.Err()
+		}
+
+		scanCtx, cancel := context.WithTimeout(ctx, ScanTimeout)
+		err := s.scanFile(scanCtx, file)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to rescan file %s: %v", file.ID, err)
+			continue
+		}
+		scanned++
+	}
+	return scanned, nil
+}
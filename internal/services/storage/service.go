@@ -2,77 +2,169 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"log"
+	"mime"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 
 	"timebride/internal/config"
+	"timebride/internal/jobs"
+	"timebride/internal/media"
 	"timebride/internal/models"
 	"timebride/internal/repositories"
+	"timebride/internal/scanner"
+	"timebride/internal/services/notification"
 )
 
+var ErrInvalidPath = errors.New("invalid file path")
+
 type storageService struct {
 	config      *config.Config
 	fileRepo    repositories.FileRepository
+	linkRepo    repositories.FileLinkRepository
+	blobRepo    repositories.FileBlobRepository
+	userRepo    repositories.UserRepository
+	tx          repositories.UnitOfWork
+	scanner     scanner.Scanner
+	notifier    notification.INotificationService
+	jobs        jobs.IJobQueue
+	signer      *urlSigner
 	storagePath string
 }
 
 // NewStorageService creates a new storage service instance
-func NewStorageService(cfg *config.Config, fileRepo repositories.FileRepository) IStorageService {
+func NewStorageService(
+	cfg *config.Config,
+	fileRepo repositories.FileRepository,
+	linkRepo repositories.FileLinkRepository,
+	blobRepo repositories.FileBlobRepository,
+	userRepo repositories.UserRepository,
+	tx repositories.UnitOfWork,
+	fileScanner scanner.Scanner,
+	notifier notification.INotificationService,
+	jobQueue jobs.IJobQueue,
+) IStorageService {
 	return &storageService{
 		config:      cfg,
 		fileRepo:    fileRepo,
+		linkRepo:    linkRepo,
+		blobRepo:    blobRepo,
+		userRepo:    userRepo,
+		tx:          tx,
+		scanner:     fileScanner,
+		notifier:    notifier,
+		jobs:        jobQueue,
+		signer:      newURLSigner(cfg.Storage.SigningKey),
 		storagePath: cfg.Storage.Path,
 	}
 }
 
 // UploadFile завантажує файл та створює запис в БД
-func (s *storageService) UploadFile(ctx context.Context, file *multipart.FileHeader, path string) (string, error) {
-	// Створюємо директорію, якщо не існує
-	fullPath := filepath.Join(s.storagePath, path)
-	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+func (s *storageService) UploadFile(ctx context.Context, input *UploadInput) (*models.File, error) {
+	src, name, mimeType := input.Content, input.Name, input.ContentType
+	if input.File != nil {
+		uploaded, err := input.File.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+		}
+		defer uploaded.Close()
+		src, name, mimeType = uploaded, input.File.Filename, input.File.Header.Get("Content-Type")
 	}
 
-	// Відкриваємо файл для запису
-	dst, err := os.Create(fullPath)
+	tmpPath, hash, size, err := s.spoolUpload(src)
 	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
+		return nil, err
+	}
+
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(name)); byExt != "" {
+			mimeType = byExt
+		}
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
 	}
-	defer dst.Close()
 
-	// Відкриваємо вхідний файл
-	src, err := file.Open()
+	// Метадані читаються з тимчасового файлу до переміщення в сховище
+	metadata, takenAt := s.extractMetadata(tmpPath, mimeType)
+
+	blob, err := s.commitBlob(ctx, input.UserID, tmpPath, hash, size)
 	if err != nil {
-		return "", fmt.Errorf("failed to open uploaded file: %w", err)
+		return nil, err
+	}
+
+	fileType := input.Type
+	if fileType == "" {
+		fileType = models.FileTypeFromMime(mimeType)
+	}
+
+	file := &models.File{
+		UserID:      input.UserID,
+		BookingID:   input.BookingID,
+		Name:        filepath.Base(name),
+		Path:        blob.Path,
+		Size:        size,
+		ContentType: mimeType,
+		MimeType:    mimeType,
+		Type:        fileType,
+		ContentHash: hash,
+		Folder:      input.Folder,
+		Metadata:    metadata,
+		TakenAt:     takenAt,
+		ScanStatus:  models.ScanStatusPending,
+	}
+	file.URL = s.GetFileURL(ctx, file)
+
+	if input.Generated {
+		now := time.Now()
+		file.ScanStatus = models.ScanStatusClean
+		file.ScannedAt = &now
+	}
+
+	if err := s.fileRepo.Create(ctx, file); err != nil {
+		// Запис не створено - знімаємо посилання на blob
+		if releaseErr := s.releaseBlob(ctx, file, nil); releaseErr != nil {
+			log.Printf("Failed to release blob %s: %v", hash, releaseErr)
+		}
+		return nil, fmt.Errorf("failed to create file record: %w", err)
 	}
-	defer src.Close()
 
-	// Копіюємо вміст
-	if _, err = io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to copy file: %w", err)
+	// Файл недоступний для завантаження, доки не пройде перевірку
+	if !input.Generated {
+		s.queueScan(ctx, file)
 	}
 
-	return s.GetFileURL(ctx, path), nil
+	return file, nil
 }
 
 // DownloadFile завантажує файл зі сховища
 func (s *storageService) DownloadFile(ctx context.Context, path string) (io.ReadCloser, error) {
-	fullPath := filepath.Join(s.storagePath, path)
+	fullPath, err := s.localPath(path)
+	if err != nil {
+		return nil, err
+	}
 	return os.Open(fullPath)
 }
 
-// DeleteFile видаляє файл зі сховища та БД
-func (s *storageService) DeleteFile(ctx context.Context, path string) error {
-	fullPath := filepath.Join(s.storagePath, path)
-	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete file: %w", err)
+// DeleteFile переміщує файл у кошик. Вміст залишається в сховищі
+// до остаточного видалення (PurgeFile, EmptyTrash або PurgeExpiredTrash).
+func (s *storageService) DeleteFile(ctx context.Context, id uuid.UUID) error {
+	file, err := s.fileRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	return s.fileRepo.Delete(ctx, file.ID)
 }
 
 // ListFiles повертає список файлів користувача
@@ -80,6 +172,11 @@ func (s *storageService) ListFiles(ctx context.Context, filter map[string]interf
 	return s.fileRepo.List(ctx, filter)
 }
 
+// SearchFiles повертає файли користувача за фільтрами метаданих
+func (s *storageService) SearchFiles(ctx context.Context, userID uuid.UUID, opts models.FileSearchOptions) ([]*models.File, error) {
+	return s.fileRepo.Search(ctx, userID, opts)
+}
+
 // GetFileByID повертає файл за ID
 func (s *storageService) GetFile(ctx context.Context, id uuid.UUID) (*models.File, error) {
 	return s.fileRepo.GetByID(ctx, id)
@@ -105,14 +202,218 @@ func (s *storageService) GetTotalSize(ctx context.Context) (int64, error) {
 	return totalSize, nil
 }
 
-// GetFileURL повертає URL файлу
-func (s *storageService) GetFileURL(ctx context.Context, path string) string {
-	// TODO: Implement proper URL generation based on storage provider
-	return fmt.Sprintf("/storage/%s", path)
+// GetFileURL повертає підписаний URL файлу з терміном дії за замовчуванням
+func (s *storageService) GetFileURL(ctx context.Context, file *models.File) string {
+	expiry := s.config.Storage.GetURLExpiry()
+
+	if s.config.Storage.IsCloudStorage() {
+		signedURL, err := presignS3GetURL(&s.config.Storage, file.Path, expiry, time.Now(), file.Name)
+		if err != nil {
+			log.Printf("Failed to presign storage url for %s: %v", file.Path, err)
+			return ""
+		}
+		return signedURL
+	}
+
+	return s.localURL(file, time.Now().Add(expiry), "")
+}
+
+// GetSignedURL повертає підписаний URL файлу з заданим терміном дії та лімітом завантажень
+func (s *storageService) GetSignedURL(ctx context.Context, file *models.File, opts SignedURLOptions) (string, error) {
+	expiry := opts.Expiry
+	if expiry <= 0 {
+		expiry = s.config.Storage.GetURLExpiry()
+	}
+
+	if err := checkScan(file); err != nil {
+		return "", err
+	}
+
+	if s.config.Storage.IsCloudStorage() {
+		return presignS3GetURL(&s.config.Storage, file.Path, expiry, time.Now(), file.Name)
+	}
+
+	expiresAt := time.Now().Add(expiry)
+	if opts.MaxDownloads <= 0 {
+		return s.localURL(file, expiresAt, ""), nil
+	}
+
+	// Для обмеженої кількості завантажень зберігаємо посилання в БД,
+	// щоб лічильник працював між запитами та репліками
+	link := &models.FileLink{
+		FileID:       file.ID,
+		UserID:       file.UserID,
+		ExpiresAt:    expiresAt,
+		MaxDownloads: opts.MaxDownloads,
+	}
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return "", fmt.Errorf("failed to create file link: %w", err)
+	}
+
+	return s.localURL(file, expiresAt, link.ID.String()), nil
+}
+
+// OpenSignedFile перевіряє підписане посилання та відкриває файл для читання
+func (s *storageService) OpenSignedFile(ctx context.Context, path string, params SignedURLParams) (*FileContent, error) {
+	if err := s.signer.verify(path, params, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if params.LinkID == "" {
+		if err := s.checkPathScan(ctx, path); err != nil {
+			return nil, err
+		}
+		// Вміст зберігається під хешем, тому назва для завантаження береться з підписаного посилання
+		return s.openLocal(path, params.Name, "")
+	}
+
+	linkID, err := uuid.Parse(params.LinkID)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.fileRepo.GetByID(ctx, link.FileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.Path != path {
+		return nil, ErrInvalidSignature
+	}
+	if err := checkScan(file); err != nil {
+		return nil, err
+	}
+
+	// Плеєри та менеджери завантажень дочитують файл кількома Range-запитами;
+	// завантаженням вважається лише запит без діапазону або з діапазоном від першого байта
+	if start, _, ok := ParseRange(params.Range, file.Size); params.Range == "" || (ok && start == 0) {
+		if err := s.linkRepo.Consume(ctx, link.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.OpenFile(ctx, file)
+}
+
+// OpenFile відкриває файл для читання
+func (s *storageService) OpenFile(ctx context.Context, file *models.File) (*FileContent, error) {
+	if err := checkScan(file); err != nil {
+		return nil, err
+	}
+	return s.openLocal(file.Path, file.Name, file.MimeType)
 }
 
 // Допоміжні методи
 
+// extractMetadata читає метадані медіафайлу. Помилки розбору не блокують
+// завантаження - файл просто зберігається без метаданих.
+func (s *storageService) extractMetadata(path, mimeType string) (datatypes.JSON, *time.Time) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	meta, err := media.Extract(f, mimeType)
+	if err != nil {
+		log.Printf("Failed to extract metadata: %v", err)
+		return nil, nil
+	}
+	if meta.IsEmpty() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, nil
+	}
+	return datatypes.JSON(data), meta.TakenAt
+}
+
+// checkPathScan перевіряє, що вміст за шляхом належить хоча б одному перевіреному файлу.
+// Вміст дедуплікований, тож усі файли з одним шляхом мають однаковий результат перевірки.
+func (s *storageService) checkPathScan(ctx context.Context, path string) error {
+	files, err := s.fileRepo.ListByPath(ctx, path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return os.ErrNotExist
+	}
+
+	result := ErrFileNotScanned
+	for _, file := range files {
+		switch file.ScanStatus {
+		case models.ScanStatusInfected:
+			return ErrFileQuarantined
+		case models.ScanStatusClean:
+			result = nil
+		}
+	}
+	return result
+}
+
+// localPath повертає повний шлях до файлу, не дозволяючи вийти за межі сховища
+func (s *storageService) localPath(path string) (string, error) {
+	root, err := filepath.Abs(s.storagePath)
+	if err != nil {
+		return "", err
+	}
+	fullPath := filepath.Join(root, filepath.FromSlash(path))
+	if fullPath != root && !strings.HasPrefix(fullPath, root+string(filepath.Separator)) {
+		return "", ErrInvalidPath
+	}
+	return fullPath, nil
+}
+
+// localURL формує підписане посилання на файл у локальному сховищі
+func (s *storageService) localURL(file *models.File, expiresAt time.Time, linkID string) string {
+	escaped := (&url.URL{Path: file.Path}).EscapedPath()
+	return "/files/" + strings.TrimPrefix(escaped, "/") + "?" + s.signer.query(file.Path, expiresAt, linkID, file.Name)
+}
+
+// openLocal відкриває файл з локального сховища
+func (s *storageService) openLocal(path, name, contentType string) (*FileContent, error) {
+	fullPath, err := s.localPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, ErrInvalidPath
+	}
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &FileContent{
+		Reader:      f,
+		Name:        name,
+		ContentType: contentType,
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
 func (s *storageService) saveFile(src io.Reader, dst string) error {
 	// TODO: Реалізувати збереження файлу
 	return nil
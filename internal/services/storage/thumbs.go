@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"timebride/internal/media"
+	"timebride/internal/models"
+)
+
+// Розміри похідних зображень (більша сторона в пікселях)
+const (
+	ThumbnailSmall = 480
+	ThumbnailLarge = 1600
+)
+
+// thumbnailQuality - якість JPEG для мініатюр
+const thumbnailQuality = 82
+
+var ErrThumbnailUnsupported = errors.New("thumbnail is not supported for this file")
+
+// Watermark описує водяний знак для похідних зображень
+type Watermark struct {
+	// Logo - зображення знака, завантажене користувачем
+	Logo    *models.File
+	Options media.WatermarkOptions
+}
+
+// signature повертає короткий відбиток знака для імені кешованого файлу:
+// зміна логотипа чи параметрів створює нові похідні замість застарілих
+func (w *Watermark) signature() string {
+	key := w.Logo.ContentHash
+	if key == "" {
+		key = w.Logo.ID.String()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%.3f|%.3f", key, w.Options.Position, w.Options.Opacity, w.Options.Scale)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// OpenThumbnail повертає зменшену копію зображення, створюючи її за потреби.
+// Мініатюри кешуються на диску за хешем вмісту, тож дублікати мають спільну мініатюру.
+func (s *storageService) OpenThumbnail(ctx context.Context, file *models.File, size int) (*FileContent, error) {
+	return s.OpenPreview(ctx, file, size, nil)
+}
+
+// OpenPreview повертає зменшену копію зображення з водяним знаком (якщо wm != nil).
+// Похідні з різними знаками кешуються окремо.
+func (s *storageService) OpenPreview(ctx context.Context, file *models.File, size int, wm *Watermark) (*FileContent, error) {
+	if err := checkScan(file); err != nil {
+		return nil, err
+	}
+	if size != ThumbnailSmall && size != ThumbnailLarge {
+		size = ThumbnailSmall
+	}
+	if !file.IsImage() || file.MimeType == "image/webp" {
+		return nil, ErrThumbnailUnsupported
+	}
+
+	thumbPath := thumbnailPath(file, size)
+	if wm != nil {
+		if err := checkScan(wm.Logo); err != nil {
+			return nil, err
+		}
+		thumbPath = strings.TrimSuffix(thumbPath, ".jpg") + "_wm" + wm.signature() + ".jpg"
+	}
+	name := thumbnailName(file.Name)
+
+	content, err := s.openLocal(thumbPath, name, "image/jpeg")
+	if err == nil {
+		return content, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := s.createThumbnail(file, thumbPath, size, wm); err != nil {
+		return nil, err
+	}
+	return s.openLocal(thumbPath, name, "image/jpeg")
+}
+
+// createThumbnail генерує мініатюру (за потреби з водяним знаком) та атомарно зберігає її
+func (s *storageService) createThumbnail(file *models.File, thumbPath string, size int, wm *Watermark) error {
+	src, err := s.openLocal(file.Path, file.Name, file.MimeType)
+	if err != nil {
+		return err
+	}
+	defer src.Reader.Close()
+
+	var meta media.Metadata
+	if len(file.Metadata) > 0 {
+		_ = json.Unmarshal(file.Metadata, &meta)
+	}
+
+	img, err := media.Thumbnail(src.Reader, size, meta.ExifOrientation)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrThumbnailUnsupported, err)
+	}
+	if wm != nil {
+		mark, err := s.decodeLogo(wm.Logo)
+		if err != nil {
+			return err
+		}
+		img = media.Watermark(img, mark, wm.Options)
+	}
+
+	fullPath, err := s.localPath(thumbPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), "thumb-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := media.EncodeJPEG(tmp, img, thumbnailQuality); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Паралельні запити можуть згенерувати мініатюру одночасно - перемагає останній rename
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+// decodeLogo завантажує зображення водяного знака
+func (s *storageService) decodeLogo(logo *models.File) (image.Image, error) {
+	content, err := s.openLocal(logo.Path, logo.Name, logo.MimeType)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Reader.Close()
+
+	mark, err := media.DecodeImage(content.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode watermark: %w", err)
+	}
+	return mark, nil
+}
+
+// removeThumbnails видаляє мініатюри вмісту з вказаним хешем, включно з варіантами з водяним знаком
+func (s *storageService) removeThumbnails(file *models.File) {
+	for _, size := range []int{ThumbnailSmall, ThumbnailLarge} {
+		thumbPath := thumbnailPath(file, size)
+		_ = s.removeLocal(thumbPath)
+
+		pattern, err := s.localPath(strings.TrimSuffix(thumbPath, ".jpg") + "_wm*.jpg")
+		if err != nil {
+			continue
+		}
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			_ = os.Remove(match)
+		}
+	}
+}
+
+// thumbnailPath повертає шлях мініатюри; для файлів без хешу використовується ID
+func thumbnailPath(file *models.File, size int) string {
+	key := file.ContentHash
+	if key == "" {
+		key = file.ID.String()
+	}
+	return "thumbs/" + file.UserID.String() + "/" + key[:2] + "/" + key + "_" + strconv.Itoa(size) + ".jpg"
+}
+
+func thumbnailName(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))] + ".jpg"
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// purgeBatchSize - кількість файлів, що остаточно видаляються за один прохід
+const purgeBatchSize = 100
+
+var ErrFileNotInTrash = errors.New("file is not in trash")
+
+// RestoreFile повертає файл з кошика
+func (s *storageService) RestoreFile(ctx context.Context, id uuid.UUID) (*models.File, error) {
+	file, err := s.fileRepo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return nil, ErrFileNotInTrash
+	}
+
+	if err := s.fileRepo.Restore(ctx, file.ID); err != nil {
+		return nil, fmt.Errorf("failed to restore file: %w", err)
+	}
+
+	file.DeletedAt.Valid = false
+	return file, nil
+}
+
+// GetTrashedFile повертає файл з кошика
+func (s *storageService) GetTrashedFile(ctx context.Context, id uuid.UUID) (*models.File, error) {
+	return s.fileRepo.GetTrashedByID(ctx, id)
+}
+
+// ListTrash повертає файли користувача в кошику
+func (s *storageService) ListTrash(ctx context.Context, userID uuid.UUID) ([]*models.File, error) {
+	return s.fileRepo.ListTrashed(ctx, userID)
+}
+
+// PurgeFile остаточно видаляє файл з кошика
+func (s *storageService) PurgeFile(ctx context.Context, id uuid.UUID) error {
+	file, err := s.fileRepo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return ErrFileNotInTrash
+	}
+	return s.purge(ctx, file)
+}
+
+// EmptyTrash остаточно видаляє всі файли користувача з кошика
+func (s *storageService) EmptyTrash(ctx context.Context, userID uuid.UUID) (int, error) {
+	files, err := s.fileRepo.ListTrashed(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, file := range files {
+		if err := s.purge(ctx, file); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// PurgeExpiredTrash остаточно видаляє файли, що пролежали в кошику довше
+// терміну зберігання. Помилки окремих файлів логуються, щоб не блокувати решту.
+func (s *storageService) PurgeExpiredTrash(ctx context.Context) (int, error) {
+	before := time.Now().Add(-s.config.Storage.GetTrashRetention())
+
+	purged := 0
+	for {
+		files, err := s.fileRepo.ListTrashedBefore(ctx, before, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		failed := 0
+		for _, file := range files {
+			if err := s.purge(ctx, file); err != nil {
+				log.Printf("Failed to purge file %s: %v", file.ID, err)
+				failed++
+				continue
+			}
+			purged++
+		}
+
+		// Якщо весь пакет завершився помилками, наступний прохід поверне ті самі файли
+		if len(files) < purgeBatchSize || failed == len(files) {
+			return purged, nil
+		}
+	}
+}
+
+// purge видаляє запис про файл та знімає посилання на його вміст однією транзакцією.
+// Квота звільняється лише тут, а не при переміщенні в кошик.
+func (s *storageService) purge(ctx context.Context, file *models.File) error {
+	return s.releaseBlob(ctx, file, func(ctx context.Context) error {
+		if err := s.fileRepo.Purge(ctx, file.ID); err != nil {
+			return fmt.Errorf("failed to purge file record: %w", err)
+		}
+		return nil
+	})
+}
@@ -3,7 +3,9 @@ package template
 import (
 	"context"
 
+	"timebride/internal/merge"
 	"timebride/internal/models"
+	"timebride/internal/services/email"
 
 	"github.com/google/uuid"
 )
@@ -17,4 +19,15 @@ type ITemplateService interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetTotalTemplates(ctx context.Context) (int64, error)
 	GetActiveTemplates(ctx context.Context) (int64, error)
+
+	// Fields повертає задокументовані поля підстановки для шаблонів договорів
+	Fields() []merge.Field
+	// EmailEvents повертає події транзакційних листів з полями підстановки
+	EmailEvents() []email.Event
+	// Validate перевіряє шаблон договору або листа: синтаксис, умови, цикли та невідомі поля
+	Validate(template *models.Template) error
+	// Render заповнює шаблон даними бронювання
+	Render(ctx context.Context, userID, templateID, bookingID uuid.UUID) (*Rendered, error)
+	// Preview заповнює ще не збережений шаблон даними бронювання
+	Preview(ctx context.Context, userID uuid.UUID, template *models.Template, bookingID uuid.UUID) (*Rendered, error)
 }
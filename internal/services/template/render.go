@@ -0,0 +1,257 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/merge"
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+	"timebride/internal/services/email"
+	"timebride/internal/utils"
+)
+
+var (
+	ErrInvalidVariable   = errors.New("custom variable names must be lowercase latin words separated by dots and must not override built-in fields")
+	ErrInvalidEmailEvent = errors.New("unknown email event")
+	ErrInvalidLanguage   = errors.New("language must be either uk or en")
+)
+
+// Rendered містить документ, заповнений даними бронювання
+type Rendered struct {
+	Subject string `json:"subject"`
+	Content string `json:"content"`
+}
+
+// eventTypeLabels - назви типів подій для документів
+var eventTypeLabels = map[models.EventType]string{
+	models.EventTypeWedding:    "Весілля",
+	models.EventTypeEngagement: "Заручини",
+	models.EventTypeCorporate:  "Корпоратив",
+	models.EventTypeFamily:     "Сімейна зйомка",
+	models.EventTypePortrait:   "Портретна зйомка",
+	models.EventTypeCommercial: "Комерційна зйомка",
+	models.EventTypeOther:      "Інша подія",
+}
+
+// Fields повертає задокументовані поля підстановки
+func (s *templateService) Fields() []merge.Field {
+	return merge.Fields
+}
+
+// EmailEvents повертає події листів з полями підстановки
+func (s *templateService) EmailEvents() []email.Event {
+	return email.Events
+}
+
+// Validate перевіряє синтаксис шаблону договору або листа та відсутність невідомих полів
+func (s *templateService) Validate(template *models.Template) error {
+	if template.Type == models.TemplateTypeEmail {
+		return validateEmail(template)
+	}
+	if template.Type != models.TemplateTypeContract {
+		return nil
+	}
+
+	custom, err := customFields(template.Variables)
+	if err != nil {
+		return err
+	}
+	if err := merge.Validate(template.Subject, custom); err != nil {
+		return err
+	}
+	return merge.Validate(template.Content, custom)
+}
+
+// Render заповнює збережений шаблон даними бронювання
+func (s *templateService) Render(ctx context.Context, userID, templateID, bookingID uuid.UUID) (*Rendered, error) {
+	template, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.UserID != userID {
+		return nil, repositories.ErrTemplateNotFound
+	}
+	return s.Preview(ctx, userID, template, bookingID)
+}
+
+// Preview заповнює шаблон (зокрема ще не збережений) даними реального бронювання
+func (s *templateService) Preview(ctx context.Context, userID uuid.UUID, template *models.Template, bookingID uuid.UUID) (*Rendered, error) {
+	custom, err := customFields(template.Variables)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := merge.Parse(template.Subject)
+	if err != nil {
+		return nil, err
+	}
+	content, err := merge.Parse(template.Content)
+	if err != nil {
+		return nil, err
+	}
+	if err := subject.Validate(custom); err != nil {
+		return nil, err
+	}
+	if err := content.Validate(custom); err != nil {
+		return nil, err
+	}
+
+	data, err := s.bookingData(ctx, userID, bookingID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range template.Variables {
+		data.Values[name] = value
+	}
+
+	return &Rendered{
+		Subject: subject.Render(data),
+		Content: content.Render(data),
+	}, nil
+}
+
+// bookingData збирає значення полів підстановки з бронювання, клієнта та налаштувань студії
+func (s *templateService) bookingData(ctx context.Context, userID, bookingID uuid.UUID, now time.Time) (merge.Data, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return merge.Data{}, err
+	}
+	if booking.UserID != userID {
+		return merge.Data{}, repositories.ErrBookingNotFound
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, booking.ClientID)
+	if err != nil {
+		return merge.Data{}, fmt.Errorf("failed to load booking client: %w", err)
+	}
+
+	studio, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return merge.Data{}, err
+	}
+	settings, err := studio.GetSettings()
+	if err != nil {
+		return merge.Data{}, err
+	}
+
+	studioName := studio.CompanyName
+	if studioName == "" {
+		studioName = studio.FullName
+	}
+	grandTotal := booking.PriceTotal + booking.PriceExtra
+	deadline := booking.EventDate.AddDate(0, 0, booking.DeadlineDays)
+
+	values := map[string]string{
+		"client.name":  client.FullName,
+		"client.phone": client.Phone,
+		"client.email": client.Email,
+
+		"booking.title":    booking.Title,
+		"event.type":       eventTypeLabels[booking.EventType],
+		"event.date":       utils.FormatDate(booking.EventDate),
+		"event.start_time": formatClock(booking.StartTime),
+		"event.end_time":   formatClock(booking.EndTime),
+		"event.location":   booking.Location,
+
+		"package.name":      booking.PackageName,
+		"price.total":       utils.FormatMoney(booking.PriceTotal),
+		"price.extra":       utils.FormatMoney(booking.PriceExtra),
+		"price.grand_total": utils.FormatMoney(grandTotal),
+		"price.deposit":     utils.FormatMoney(booking.PricePrepayment),
+		"price.balance":     utils.FormatMoney(grandTotal - booking.PricePrepayment),
+		"price.currency":    settings.DefaultCurrency,
+
+		"deadline.days": strconv.Itoa(booking.DeadlineDays),
+		"deadline.date": "",
+
+		"studio.name":       studioName,
+		"studio.owner":      studio.FullName,
+		"studio.phone":      studio.Phone,
+		"studio.email":      studio.Email,
+		"studio.legal_name": settings.Requisites.LegalName,
+		"studio.tax_id":     settings.Requisites.TaxID,
+		"studio.iban":       settings.Requisites.IBAN,
+		"studio.bank":       settings.Requisites.Bank,
+		"studio.address":    settings.Requisites.Address,
+
+		"today": utils.FormatDate(now),
+	}
+	if booking.DeadlineDays > 0 {
+		values["deadline.date"] = utils.FormatDate(deadline)
+	}
+
+	return merge.Data{
+		Values: values,
+		Lists: map[string][]map[string]string{
+			"installments": installments(booking, now),
+		},
+	}, nil
+}
+
+// installments будує графік платежів: передоплата в день формування договору,
+// залишок - у день події
+func installments(booking *models.Booking, now time.Time) []map[string]string {
+	var plan []map[string]string
+	add := func(label string, amount float64, due time.Time) {
+		plan = append(plan, map[string]string{
+			"installment.number":   strconv.Itoa(len(plan) + 1),
+			"installment.label":    label,
+			"installment.amount":   utils.FormatMoney(amount),
+			"installment.due_date": utils.FormatDate(due),
+		})
+	}
+
+	total := booking.PriceTotal + booking.PriceExtra
+	if booking.PricePrepayment > 0 {
+		add("Передоплата", booking.PricePrepayment, now)
+	}
+	if balance := total - booking.PricePrepayment; balance > 0 {
+		add("Остаточний розрахунок", balance, booking.EventDate)
+	}
+	return plan
+}
+
+// validateEmail перевіряє подію, мову та поля шаблону листа
+func validateEmail(template *models.Template) error {
+	event, ok := email.LookupEvent(template.Event)
+	if !ok {
+		return ErrInvalidEmailEvent
+	}
+	if !email.IsValidLanguage(template.Language) {
+		return ErrInvalidLanguage
+	}
+	if err := merge.ValidateFields(template.Subject, event.Fields); err != nil {
+		return err
+	}
+	return merge.ValidateFields(template.Content, event.Fields)
+}
+
+// customFields перетворює власні змінні шаблону на поля підстановки
+func customFields(variables map[string]string) ([]merge.Field, error) {
+	custom := make([]merge.Field, 0, len(variables))
+	for name := range variables {
+		if !merge.IsValidName(name) {
+			return nil, ErrInvalidVariable
+		}
+		for _, f := range merge.Fields {
+			if f.Name == name {
+				return nil, ErrInvalidVariable
+			}
+		}
+		custom = append(custom, merge.Field{Name: name, Description: "Власне поле шаблону"})
+	}
+	return custom, nil
+}
+
+// formatClock повертає час або порожній рядок, якщо час не задано
+func formatClock(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return utils.FormatTime(t)
+}
@@ -11,17 +11,31 @@ import (
 
 type templateService struct {
 	templateRepo repositories.Repository[models.Template]
+	bookingRepo  repositories.BookingRepository
+	clientRepo   repositories.ClientRepository
+	userRepo     repositories.UserRepository
 }
 
 // NewTemplateService creates a new template service instance
-func NewTemplateService(templateRepo repositories.Repository[models.Template]) ITemplateService {
+func NewTemplateService(
+	templateRepo repositories.Repository[models.Template],
+	bookingRepo repositories.BookingRepository,
+	clientRepo repositories.ClientRepository,
+	userRepo repositories.UserRepository,
+) ITemplateService {
 	return &templateService{
 		templateRepo: templateRepo,
+		bookingRepo:  bookingRepo,
+		clientRepo:   clientRepo,
+		userRepo:     userRepo,
 	}
 }
 
-// Create створює новий шаблон
+// Create створює новий шаблон; шаблон договору попередньо перевіряється
 func (s *templateService) Create(ctx context.Context, template *models.Template) error {
+	if err := s.Validate(template); err != nil {
+		return err
+	}
 	return s.templateRepo.Create(ctx, template)
 }
 
@@ -35,8 +49,11 @@ func (s *templateService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 	return s.templateRepo.List(ctx, map[string]interface{}{"user_id": userID})
 }
 
-// Update оновлює шаблон
+// Update оновлює шаблон; шаблон договору попередньо перевіряється
 func (s *templateService) Update(ctx context.Context, template *models.Template) error {
+	if err := s.Validate(template); err != nil {
+		return err
+	}
 	return s.templateRepo.Update(ctx, template)
 }
 
@@ -0,0 +1,254 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+const (
+	// sendLease - час на спробу доставки; лише після нього доставку знову підбирає обробник черги
+	sendLease = time.Minute
+	// sendTimeout - максимальна тривалість одного запиту
+	sendTimeout = 15 * time.Second
+	// sendBatch - кількість доставок, що надсилаються за один запуск
+	sendBatch = 100
+
+	// retryBase - пауза перед першою повторною спробою; кожна наступна вчетверо довша
+	retryBase = time.Minute
+	// maxAttempts - після стількох невдалих спроб доставка вважається недоставленою
+	maxAttempts = 6
+
+	// responseLimit - скільки байтів відповіді зберігається в журналі
+	responseLimit = 512
+	// userAgent - User-Agent запитів вебхуків
+	userAgent = "TimeBride-Webhooks/1.0"
+)
+
+// Заголовки запиту вебхука
+const (
+	headerEvent     = "X-TimeBride-Event"
+	headerDelivery  = "X-TimeBride-Delivery"
+	headerSignature = "X-TimeBride-Signature"
+)
+
+// envelope - тіло запиту вебхука
+type envelope struct {
+	ID        uuid.UUID           `json:"id"`
+	Event     models.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      interface{}         `json:"data"`
+}
+
+// Emit ставить доставки для активних підписок на подію в чергу; першу спробу робить SendDue.
+// Повторний виклик з тим самим eventID пропускає підписки, для яких доставку вже створено.
+func (s *webhookService) Emit(ctx context.Context, eventID, userID uuid.UUID, event models.WebhookEvent, data interface{}) error {
+	webhooks, err := s.webhookRepo.ListActive(ctx, userID, event)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	if eventID == uuid.Nil {
+		eventID = uuid.New()
+	}
+	now := time.Now()
+	payload, err := json.Marshal(envelope{ID: eventID, Event: event, CreatedAt: now, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s webhook: %w", event, err)
+	}
+
+	for _, webhook := range webhooks {
+		if _, err := s.deliveryRepo.Enqueue(ctx, newDelivery(webhook, eventID, event, payload, now)); err != nil {
+			return fmt.Errorf("failed to queue %s webhook %s: %w", event, webhook.ID, err)
+		}
+	}
+	return nil
+}
+
+// Ping надсилає тестовий запит незалежно від стану підписки
+func (s *webhookService) Ping(ctx context.Context, userID, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	webhook, err := s.owned(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uuid.New()
+	payload, err := json.Marshal(envelope{
+		ID:        eventID,
+		Event:     models.WebhookEventPing,
+		CreatedAt: time.Now(),
+		Data:      map[string]interface{}{"webhook_id": webhook.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sendNow(ctx, webhook, newDelivery(webhook, eventID, models.WebhookEventPing, payload, time.Now().Add(sendLease)))
+}
+
+// Replay надсилає тіло запиту з журналу як нову доставку з тим самим ідентифікатором події
+func (s *webhookService) Replay(ctx context.Context, userID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	original, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.UserID != userID {
+		return nil, repositories.ErrWebhookDeliveryNotFound
+	}
+	webhook, err := s.owned(ctx, userID, original.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	delivery := newDelivery(webhook, original.EventID, original.Event, original.Payload, time.Now().Add(sendLease))
+	delivery.ReplayOf = &original.ID
+	if original.ReplayOf != nil {
+		delivery.ReplayOf = original.ReplayOf
+	}
+	return s.sendNow(ctx, webhook, delivery)
+}
+
+// SendDue надсилає запити з черги, час яких настав
+func (s *webhookService) SendDue(ctx context.Context) (int, error) {
+	due, err := s.deliveryRepo.Claim(ctx, time.Now(), sendLease, sendBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	webhooks := make(map[uuid.UUID]*models.Webhook)
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook, err = s.webhookRepo.GetByID(ctx, delivery.WebhookID); err != nil {
+				log.Printf("Failed to load webhook %s: %v", delivery.WebhookID, err)
+				continue
+			}
+			webhooks[webhook.ID] = webhook
+		}
+		if s.attempt(ctx, delivery, webhook) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendNow зберігає доставку і надсилає її одразу; невдала доставка залишається в черзі повторних спроб
+func (s *webhookService) sendNow(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+	s.attempt(ctx, delivery, webhook)
+	return delivery, nil
+}
+
+// newDelivery описує доставку в стані pending з першою спробою в next
+func newDelivery(webhook *models.Webhook, eventID uuid.UUID, event models.WebhookEvent, payload []byte, next time.Time) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		UserID:        webhook.UserID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: &next,
+	}
+}
+
+// attempt надсилає запит і записує результат спроби; повертає true при успіху
+func (s *webhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery, webhook *models.Webhook) bool {
+	status, err := s.post(ctx, delivery, webhook)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryStatusSent
+		delivery.SentAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case status == http.StatusGone || errors.Is(err, ErrForbiddenHost) || delivery.Attempts >= maxAttempts:
+		// 410 Gone - отримувач явно відмовився від запитів; внутрішня адреса не стане публічною від повторів
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+	default:
+		next := now.Add(backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		log.Printf("Failed to update webhook delivery %s: %v", delivery.ID, err)
+	}
+	return delivery.Status == models.DeliveryStatusSent
+}
+
+// post надсилає підписаний запит; успіх - відповідь 2xx
+func (s *webhookService) post(ctx context.Context, delivery *models.WebhookDelivery, webhook *models.Webhook) (int, error) {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(sendCtx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(headerEvent, string(delivery.Event))
+	req.Header.Set(headerDelivery, delivery.EventID.String())
+	req.Header.Set(headerSignature, sign(webhook.Secret, time.Now(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, ErrForbiddenHost) {
+		// Адреса, до якої розв'язалося ім'я, не розкривається в журналі
+		return 0, ErrForbiddenHost
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// sign повертає підпис запиту у форматі "t=<unix>,v1=<hex>", де v1 - HMAC-SHA256
+// рядка "<unix>.<тіло>" секретом підписки. Мітка часу дозволяє отримувачу відкидати старі запити.
+func sign(secret string, at time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff повертає паузу перед наступною спробою: 1, 4, 16, 64 хвилини і т.д.
+func backoff(attempts int) time.Duration {
+	return retryBase << (2 * (attempts - 1))
+}
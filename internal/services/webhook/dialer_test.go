@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"timebride/internal/models"
+)
+
+func TestIsPublic(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":                true,
+		"2606:4700::1111":        true,
+		"127.0.0.1":              false,
+		"10.1.2.3":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"100.64.0.1":             false,
+		"0.0.0.0":                false,
+		"::1":                    false,
+		"fe80::1":                false,
+		"fd00::1":                false,
+		"::ffff:169.254.169.254": false,
+	}
+	for raw, want := range cases {
+		if got := isPublic(netip.MustParseAddr(raw)); got != want {
+			t.Errorf("isPublic(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestValidateRejectsInternalHosts(t *testing.T) {
+	events := []models.WebhookEvent{models.WebhookEventBookingCreated}
+	for _, raw := range []string{
+		"http://localhost/hook",
+		"http://127.0.0.1:8080/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"https://[::1]/hook",
+		"http://api.localhost./hook",
+	} {
+		if err := validate(raw, events); !errors.Is(err, ErrForbiddenHost) {
+			t.Errorf("validate(%q) = %v, want ErrForbiddenHost", raw, err)
+		}
+	}
+	if err := validate("https://hooks.example.com/timebride", events); err != nil {
+		t.Errorf("validate public URL: %v", err)
+	}
+}
+
+func TestPostRefusesInternalAddressAtConnect(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("internal secret"))
+	}))
+	defer server.Close()
+
+	s := &webhookService{client: newClient()}
+	delivery := &models.WebhookDelivery{Event: models.WebhookEventPing, Payload: []byte(`{}`)}
+	status, err := s.post(context.Background(), delivery, &models.Webhook{URL: server.URL, Secret: "whsec_test"})
+	if !errors.Is(err, ErrForbiddenHost) || err.Error() != ErrForbiddenHost.Error() {
+		t.Fatalf("post to %s: status %d, err %v; want ErrForbiddenHost", server.URL, status, err)
+	}
+	if called {
+		t.Fatal("request reached the internal server")
+	}
+}
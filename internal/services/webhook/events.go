@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"context"
+
+	"timebride/internal/events"
+	"timebride/internal/models"
+)
+
+// subscriberName - назва підписника вебхуків у журналі обробки подій
+const subscriberName = "webhooks"
+
+// Subscribe підписує вебхуки на доменні події бронювань, оплат та галерей.
+// Ідентифікатор події outbox стає ідентифікатором запиту, тож повторна обробка не дублює доставки.
+func Subscribe(bus *events.Bus, service IWebhookService) {
+	events.Subscribe(bus, subscriberName, func(ctx context.Context, e *events.BookingCreated) error {
+		return service.Emit(ctx, events.ID(ctx), e.Booking.UserID, models.WebhookEventBookingCreated, e.Booking)
+	})
+	events.Subscribe(bus, subscriberName, func(ctx context.Context, e *events.BookingStatusChanged) error {
+		return service.Emit(ctx, events.ID(ctx), e.Booking.UserID, models.WebhookEventBookingStatusChanged, map[string]interface{}{
+			"booking":         e.Booking,
+			"previous_status": e.PreviousStatus,
+		})
+	})
+	events.Subscribe(bus, subscriberName, func(ctx context.Context, e *events.PaymentReceived) error {
+		return service.Emit(ctx, events.ID(ctx), e.UserID, models.WebhookEventPaymentReceived, e)
+	})
+	events.Subscribe(bus, subscriberName, func(ctx context.Context, e *events.GalleryDownloaded) error {
+		return service.Emit(ctx, events.ID(ctx), e.UserID, models.WebhookEventGalleryDownloaded, e)
+	})
+}
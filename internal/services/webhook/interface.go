@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+)
+
+// Input - дані для створення або зміни підписки
+type Input struct {
+	URL    string                `json:"url"`
+	Events []models.WebhookEvent `json:"events"`
+	// Active - nil залишає стан без змін; нова підписка за замовчуванням активна
+	Active *bool `json:"active"`
+}
+
+// IWebhookService визначає інтерфейс вихідних вебхуків
+type IWebhookService interface {
+	// Events повертає події, на які можна підписатися
+	Events() []models.WebhookEvent
+
+	// List повертає підписки користувача
+	List(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error)
+	// Create створює підписку з новим секретом для підпису запитів
+	Create(ctx context.Context, userID uuid.UUID, input *Input) (*models.Webhook, error)
+	// Update змінює URL, події або стан підписки
+	Update(ctx context.Context, userID, webhookID uuid.UUID, input *Input) (*models.Webhook, error)
+	// Delete видаляє підписку разом із журналом доставки
+	Delete(ctx context.Context, userID, webhookID uuid.UUID) error
+
+	// Deliveries повертає журнал доставки підписки
+	Deliveries(ctx context.Context, userID, webhookID uuid.UUID) ([]*models.WebhookDelivery, error)
+	// Replay повторно надсилає запит із журналу як нову доставку та повертає її результат
+	Replay(ctx context.Context, userID, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
+	// Ping надсилає тестовий запит на URL підписки та повертає результат
+	Ping(ctx context.Context, userID, webhookID uuid.UUID) (*models.WebhookDelivery, error)
+
+	// Emit ставить подію в чергу для активних підписок користувача; запити надсилає SendDue.
+	// Подія з тим самим eventID ставиться в чергу кожної підписки лише один раз.
+	Emit(ctx context.Context, eventID, userID uuid.UUID, event models.WebhookEvent, data interface{}) error
+	// SendDue надсилає запити з черги, час яких настав; повертає кількість доставлених
+	SendDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"timebride/internal/models"
+	"timebride/internal/repositories"
+)
+
+const (
+	// secretBytes - довжина секрету підпису
+	secretBytes = 32
+	// secretPrefix позначає секрет вебхука, щоб його легко було впізнати в налаштуваннях інтеграцій
+	secretPrefix = "whsec_"
+	// listLimit - кількість записів журналу доставки у відповіді
+	listLimit = 100
+)
+
+var (
+	ErrInvalidURL    = errors.New("webhook URL must be an absolute http(s) URL")
+	ErrInvalidEvents = errors.New("webhook must subscribe to at least one known event")
+)
+
+type webhookService struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+	client       *http.Client
+}
+
+// NewWebhookService створює сервіс вихідних вебхуків
+func NewWebhookService(
+	webhookRepo repositories.WebhookRepository,
+	deliveryRepo repositories.WebhookDeliveryRepository,
+) IWebhookService {
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		client:       newClient(),
+	}
+}
+
+// Events повертає події, на які можна підписатися
+func (s *webhookService) Events() []models.WebhookEvent {
+	return models.WebhookEvents
+}
+
+// List повертає підписки користувача
+func (s *webhookService) List(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	return s.webhookRepo.ListByUser(ctx, userID)
+}
+
+// Create створює активну підписку з новим секретом
+func (s *webhookService) Create(ctx context.Context, userID uuid.UUID, input *Input) (*models.Webhook, error) {
+	if err := validate(input.URL, input.Events); err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		UserID: userID,
+		URL:    input.URL,
+		Secret: secret,
+		Events: input.Events,
+		Active: input.Active == nil || *input.Active,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Update змінює підписку; порожні URL та список подій залишаються без змін
+func (s *webhookService) Update(ctx context.Context, userID, webhookID uuid.UUID, input *Input) (*models.Webhook, error) {
+	webhook, err := s.owned(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != "" {
+		webhook.URL = input.URL
+	}
+	if input.Events != nil {
+		webhook.Events = input.Events
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+	if err := validate(webhook.URL, webhook.Events); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Delete видаляє підписку користувача
+func (s *webhookService) Delete(ctx context.Context, userID, webhookID uuid.UUID) error {
+	if _, err := s.owned(ctx, userID, webhookID); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, webhookID)
+}
+
+// Deliveries повертає останні доставки підписки
+func (s *webhookService) Deliveries(ctx context.Context, userID, webhookID uuid.UUID) ([]*models.WebhookDelivery, error) {
+	if _, err := s.owned(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.ListByWebhook(ctx, webhookID, listLimit)
+}
+
+// owned повертає підписку, якщо вона належить користувачу
+func (s *webhookService) owned(ctx context.Context, userID, webhookID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.UserID != userID {
+		return nil, repositories.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// validate перевіряє URL та події підписки
+func validate(rawURL string, events []models.WebhookEvent) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if err := checkHost(u.Hostname()); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return ErrInvalidEvents
+	}
+	for _, event := range events {
+		if !event.IsValid() {
+			return ErrInvalidEvents
+		}
+	}
+	return nil
+}
+
+// newSecret генерує секрет для підпису запитів
+func newSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
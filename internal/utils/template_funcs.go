@@ -150,12 +150,12 @@ func FormatReminderTime(hours int) string {
 		return fmt.Sprintf("%d годин", hours)
 	} else {
 		days := hours / 24
-		return fmt.Sprintf("%d %s", days, pluralizeDays(days))
+		return fmt.Sprintf("%d %s", days, PluralizeDays(days))
 	}
 }
 
-// pluralizeDays returns the correct form of the word "day" based on the count
-func pluralizeDays(days int) string {
+// PluralizeDays returns the correct form of the word "day" based on the count
+func PluralizeDays(days int) string {
 	lastDigit := days % 10
 	lastTwoDigits := days % 100
 
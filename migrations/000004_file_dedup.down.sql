@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS update_file_blobs_updated_at ON file_blobs;
+
+ALTER TABLE users DROP COLUMN IF EXISTS storage_used_bytes;
+
+DROP INDEX IF EXISTS idx_files_content_hash;
+ALTER TABLE files DROP COLUMN IF EXISTS content_hash;
+
+DROP TABLE IF EXISTS file_blobs CASCADE;
@@ -0,0 +1,24 @@
+-- Content-addressed blobs (вміст файлів зберігається один раз на акаунт)
+CREATE TABLE file_blobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hash CHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_file_blobs_user_hash ON file_blobs(user_id, hash);
+
+ALTER TABLE files ADD COLUMN content_hash CHAR(64);
+CREATE INDEX idx_files_content_hash ON files(content_hash);
+
+-- Використаний обсяг сховища в байтах (квота списується один раз на blob)
+ALTER TABLE users ADD COLUMN storage_used_bytes BIGINT NOT NULL DEFAULT 0;
+
+CREATE TRIGGER update_file_blobs_updated_at
+    BEFORE UPDATE ON file_blobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
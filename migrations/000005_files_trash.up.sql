@@ -0,0 +1,3 @@
+-- Кошик: файли видаляються м'яко і зберігаються до остаточного видалення
+ALTER TABLE files ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX idx_files_deleted_at ON files(deleted_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_files_metadata;
+DROP INDEX IF EXISTS idx_files_taken_at;
+
+ALTER TABLE files DROP COLUMN IF EXISTS taken_at;
+ALTER TABLE files DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,6 @@
+-- Метадані медіафайлів (EXIF, роздільна здатність, тривалість відео)
+ALTER TABLE files ADD COLUMN metadata JSONB;
+ALTER TABLE files ADD COLUMN taken_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_files_taken_at ON files(user_id, taken_at);
+CREATE INDEX idx_files_metadata ON files USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notifications CASCADE;
+
+DROP INDEX IF EXISTS idx_files_scan_status;
+ALTER TABLE files DROP COLUMN IF EXISTS scanned_at;
+ALTER TABLE files DROP COLUMN IF EXISTS scan_signature;
+ALTER TABLE files DROP COLUMN IF EXISTS scan_status;
@@ -0,0 +1,21 @@
+-- Перевірка файлів на шкідливе ПЗ; файли, завантажені раніше, вважаються чистими
+ALTER TABLE files ADD COLUMN scan_status VARCHAR(20) NOT NULL DEFAULT 'clean';
+ALTER TABLE files ALTER COLUMN scan_status SET DEFAULT 'pending';
+ALTER TABLE files ADD COLUMN scan_signature VARCHAR(255);
+ALTER TABLE files ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_files_scan_status ON files(scan_status) WHERE scan_status <> 'clean';
+
+-- Сповіщення користувачів у застосунку
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT,
+    data JSONB,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
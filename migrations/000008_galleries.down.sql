@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_galleries_updated_at ON galleries;
+DROP TABLE IF EXISTS galleries CASCADE;
@@ -0,0 +1,17 @@
+-- Публічні сторінки віддачі матеріалів клієнтам (одна на бронювання)
+CREATE TABLE galleries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID NOT NULL UNIQUE REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_galleries_user_id ON galleries(user_id);
+
+CREATE TRIGGER update_galleries_updated_at
+    BEFORE UPDATE ON galleries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
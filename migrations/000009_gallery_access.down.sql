@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS gallery_access_log CASCADE;
+
+DROP INDEX IF EXISTS idx_galleries_expires_at;
+ALTER TABLE galleries DROP COLUMN IF EXISTS download_count;
+ALTER TABLE galleries DROP COLUMN IF EXISTS download_limit;
+ALTER TABLE galleries DROP COLUMN IF EXISTS allow_download;
+ALTER TABLE galleries DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE galleries DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,21 @@
+-- Обмеження доступу до публічних сторінок
+ALTER TABLE galleries ADD COLUMN password_hash VARCHAR(255);
+ALTER TABLE galleries ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE galleries ADD COLUMN allow_download BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE galleries ADD COLUMN download_limit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE galleries ADD COLUMN download_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_galleries_expires_at ON galleries(expires_at);
+
+-- Журнал переглядів та завантажень
+CREATE TABLE gallery_access_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    gallery_id UUID NOT NULL REFERENCES galleries(id) ON DELETE CASCADE,
+    file_id UUID REFERENCES files(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    ip VARCHAR(64),
+    user_agent VARCHAR(512),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gallery_access_log_gallery_created ON gallery_access_log(gallery_id, created_at DESC);
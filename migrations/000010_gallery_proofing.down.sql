@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS gallery_proofs CASCADE;
+
+ALTER TABLE price_templates DROP COLUMN IF EXISTS max_selections;
+
+ALTER TABLE galleries DROP COLUMN IF EXISTS selection_submitted_at;
+ALTER TABLE galleries DROP COLUMN IF EXISTS max_selections;
+ALTER TABLE galleries DROP COLUMN IF EXISTS proofing_enabled;
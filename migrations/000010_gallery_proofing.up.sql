@@ -0,0 +1,18 @@
+-- Відбір фото клієнтом для ретуші
+ALTER TABLE galleries ADD COLUMN proofing_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE galleries ADD COLUMN max_selections INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE galleries ADD COLUMN selection_submitted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE price_templates ADD COLUMN max_selections INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE gallery_proofs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    gallery_id UUID NOT NULL REFERENCES galleries(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    favorite BOOLEAN NOT NULL DEFAULT FALSE,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_gallery_proofs_file ON gallery_proofs(gallery_id, file_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_user_type;
+DROP INDEX IF EXISTS idx_gallery_access_log_created;
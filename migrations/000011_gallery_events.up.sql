@@ -0,0 +1,7 @@
+-- Адреси відвідувачів галерей зберігаються анонімізованими
+UPDATE gallery_access_log
+SET ip = host(network(set_masklen(ip::inet, CASE WHEN family(ip::inet) = 4 THEN 24 ELSE 48 END)))
+WHERE ip IS NOT NULL AND ip <> '';
+
+CREATE INDEX idx_gallery_access_log_created ON gallery_access_log(created_at);
+CREATE INDEX idx_notifications_user_type ON notifications(user_id, type, created_at DESC);
@@ -0,0 +1,4 @@
+ALTER TABLE galleries DROP COLUMN IF EXISTS client_uploads;
+
+DROP INDEX IF EXISTS idx_files_booking_folder;
+ALTER TABLE files DROP COLUMN IF EXISTS folder;
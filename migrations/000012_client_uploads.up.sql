@@ -0,0 +1,5 @@
+-- Файли, надіслані клієнтом з публічної сторінки бронювання
+ALTER TABLE files ADD COLUMN folder VARCHAR(50) NOT NULL DEFAULT '';
+CREATE INDEX idx_files_booking_folder ON files(booking_id, folder) WHERE booking_id IS NOT NULL;
+
+ALTER TABLE galleries ADD COLUMN client_uploads BOOLEAN NOT NULL DEFAULT FALSE;
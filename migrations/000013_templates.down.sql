@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_templates_updated_at ON templates;
+DROP TABLE IF EXISTS templates;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_contracts_updated_at ON contracts;
+DROP TABLE IF EXISTS contracts;
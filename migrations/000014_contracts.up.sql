@@ -0,0 +1,20 @@
+-- Версії договорів бронювань; bookings.contract_file_url вказує на останню версію
+CREATE TABLE contracts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    template_id UUID REFERENCES templates(id) ON DELETE SET NULL,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_contracts_booking_version ON contracts(booking_id, version);
+CREATE INDEX idx_contracts_user_id ON contracts(user_id);
+
+CREATE TRIGGER update_contracts_updated_at
+    BEFORE UPDATE ON contracts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
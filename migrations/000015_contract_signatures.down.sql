@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS contract_signatures;
+
+ALTER TABLE contracts
+    DROP COLUMN IF EXISTS signed_at,
+    DROP COLUMN IF EXISTS sent_at,
+    DROP COLUMN IF EXISTS signed_file_id;
@@ -0,0 +1,19 @@
+ALTER TABLE contracts
+    ADD COLUMN signed_file_id UUID REFERENCES files(id) ON DELETE SET NULL,
+    ADD COLUMN sent_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN signed_at TIMESTAMP WITH TIME ZONE;
+
+-- Журнал підписання: хто, коли, звідки та який саме документ підписав
+CREATE TABLE contract_signatures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    signer_name VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    document_sha256 VARCHAR(64) NOT NULL,
+    image BYTEA NOT NULL,
+    signed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_contract_signatures_contract_id ON contract_signatures(contract_id);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_notification_deliveries_updated_at ON notification_deliveries;
+DROP TABLE IF EXISTS notification_deliveries;
+DROP INDEX IF EXISTS idx_users_telegram_chat_id;
+ALTER TABLE users DROP COLUMN IF EXISTS telegram_chat_id;
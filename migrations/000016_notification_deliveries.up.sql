@@ -0,0 +1,27 @@
+-- Чат Telegram, підключений користувачем до бота; один чат належить одному акаунту
+ALTER TABLE users ADD COLUMN telegram_chat_id VARCHAR(32);
+CREATE UNIQUE INDEX idx_users_telegram_chat_id ON users(telegram_chat_id) WHERE telegram_chat_id IS NOT NULL;
+
+-- Журнал доставки сповіщень у зовнішні канали з повторними спробами
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_deliveries_notification_id ON notification_deliveries(notification_id);
+CREATE INDEX idx_notification_deliveries_user_id ON notification_deliveries(user_id, created_at DESC);
+CREATE INDEX idx_notification_deliveries_due ON notification_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE TRIGGER update_notification_deliveries_updated_at
+    BEFORE UPDATE ON notification_deliveries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
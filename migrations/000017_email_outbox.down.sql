@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_email_outbox_updated_at ON email_outbox;
+DROP TABLE IF EXISTS email_outbox;
+DROP INDEX IF EXISTS idx_templates_email_event_language;
+ALTER TABLE templates DROP COLUMN IF EXISTS language;
+ALTER TABLE templates DROP COLUMN IF EXISTS event;
@@ -0,0 +1,30 @@
+-- Шаблони листів: подія та мовний варіант; на подію й мову - один шаблон студії
+ALTER TABLE templates ADD COLUMN event VARCHAR(50);
+ALTER TABLE templates ADD COLUMN language VARCHAR(5);
+CREATE UNIQUE INDEX idx_templates_email_event_language ON templates(user_id, event, language) WHERE type = 'email';
+
+-- Черга листів: надсилаються фоновим обробником з повторними спробами
+CREATE TABLE email_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    recipients JSONB NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    attachments JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_email_outbox_user_id ON email_outbox(user_id);
+CREATE INDEX idx_email_outbox_due ON email_outbox(next_attempt_at) WHERE status = 'pending';
+
+CREATE TRIGGER update_email_outbox_updated_at
+    BEFORE UPDATE ON email_outbox
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
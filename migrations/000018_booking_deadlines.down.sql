@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS deadline_reminders;
+ALTER TABLE bookings DROP COLUMN IF EXISTS is_overdue;
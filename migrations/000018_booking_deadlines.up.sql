@@ -0,0 +1,18 @@
+-- Прострочені проєкти: термін віддачі (event_date + deadline_days) минув, а проєкт ще в роботі
+ALTER TABLE bookings ADD COLUMN is_overdue BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE bookings SET is_overdue = TRUE
+WHERE status IN ('booked', 'editing')
+  AND deadline_days > 0
+  AND event_date + deadline_days * INTERVAL '1 day' < CURRENT_TIMESTAMP;
+
+-- Надіслані нагадування про терміни віддачі; days_before = 0 - сповіщення про прострочення
+CREATE TABLE deadline_reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    days_before INTEGER NOT NULL,
+    deadline_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_deadline_reminders_unique ON deadline_reminders(booking_id, days_before, deadline_at);
@@ -0,0 +1,2 @@
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS html_body;
+ALTER TABLE notifications DROP COLUMN IF EXISTS markdown, DROP COLUMN IF EXISTS html;
@@ -0,0 +1,5 @@
+-- Форматовані версії сповіщень для Telegram (MarkdownV2) та листів
+ALTER TABLE notifications ADD COLUMN markdown TEXT, ADD COLUMN html TEXT;
+
+-- HTML-версія тіла листа; текстова версія залишається в body
+ALTER TABLE email_outbox ADD COLUMN html_body TEXT;
@@ -0,0 +1,22 @@
+-- Outbox доменних подій: записуються в транзакції зміни, розсилаються підписникам фоновим обробником.
+-- handled - підписники, що вже обробили подію
+CREATE TABLE event_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    handled JSONB NOT NULL DEFAULT '[]',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_event_outbox_due ON event_outbox(next_attempt_at) WHERE status = 'pending';
+
+CREATE TRIGGER update_event_outbox_updated_at
+    BEFORE UPDATE ON event_outbox
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1 @@
+ALTER TABLE clients DROP COLUMN IF EXISTS avatar_file_id;
@@ -0,0 +1,2 @@
+-- Аватар клієнта зберігається як файл; підписаний URL видається при кожному запиті
+ALTER TABLE clients ADD COLUMN avatar_file_id UUID REFERENCES files(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+-- Повторне надсилання з журналу посилається на первинну доставку
+ALTER TABLE webhook_deliveries ADD COLUMN replay_of UUID;
+
+-- Наявні повторні надсилання - усі доставки події, крім найпершої
+UPDATE webhook_deliveries d
+SET replay_of = first.id
+FROM (
+    SELECT DISTINCT ON (event_id, webhook_id) id, event_id, webhook_id
+    FROM webhook_deliveries
+    ORDER BY event_id, webhook_id, created_at, id
+) first
+WHERE d.event_id = first.event_id
+  AND d.webhook_id = first.webhook_id
+  AND d.id <> first.id;
+
+-- Подія ставиться в чергу підписки лише один раз, навіть якщо шина повторює обробку
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(event_id, webhook_id) WHERE replay_of IS NULL;
@@ -0,0 +1,2 @@
+-- Доставку, взяту обробником черги, інші обробники не беруть до цього часу
+ALTER TABLE notification_deliveries ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;